@@ -8,7 +8,21 @@ package infinity
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched by APIError through errors.Is
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("rate limited")
 )
 
 // APIError represents an error returned by the Infinity API
@@ -16,23 +30,108 @@ type APIError struct {
 	StatusCode int    `json:"status_code"`
 	Message    string `json:"message"`
 	Details    string `json:"details,omitempty"`
+
+	// Method and Endpoint identify the request that failed
+	Method   string `json:"method,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+	// Attempts is the number of times the request was sent
+	Attempts int `json:"attempts,omitempty"`
+	// FieldErrors holds the per-field validation errors of a rejected create or update
+	FieldErrors []FieldError `json:"field_errors,omitempty"`
+	// Retries records the failed attempts that were retried before this error
+	Retries []RetryAttempt `json:"retries,omitempty"`
+}
+
+// FieldError is a validation error for a single field of a request
+type FieldError struct {
+	// Resource is the resource the field belongs to, for example "conference", if the API reported it
+	Resource string   `json:"resource,omitempty"`
+	Field    string   `json:"field"`
+	Messages []string `json:"messages"`
+}
+
+// RetryAttempt describes a failed attempt that was retried
+type RetryAttempt struct {
+	Attempt    int           `json:"attempt"`
+	StatusCode int           `json:"status_code,omitempty"` // zero for transport errors
+	Error      string        `json:"error,omitempty"`
+	Backoff    time.Duration `json:"backoff"`
 }
 
 func (e *APIError) Error() string {
+	msg := fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
 	if e.Details != "" {
-		return fmt.Sprintf("API error %d: %s (%s)", e.StatusCode, e.Message, e.Details)
+		msg = fmt.Sprintf("%s (%s)", msg, e.Details)
 	}
-	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+	if e.Method != "" {
+		msg = fmt.Sprintf("%s %s: %s", e.Method, e.Endpoint, msg)
+	}
+	return msg
+}
+
+// Is reports whether the error matches one of the sentinel errors of this package
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	default:
+		return false
+	}
+}
+
+// FieldErrorsFor returns the validation errors reported for field
+func (e *APIError) FieldErrorsFor(field string) []string {
+	var messages []string
+	for _, fe := range e.FieldErrors {
+		if fe.Field == field {
+			messages = append(messages, fe.Messages...)
+		}
+	}
+	return messages
+}
+
+// IsNotFound reports whether err is an API error for a resource that does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict reports whether err is an API error for a request that conflicts with the current state
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsValidation reports whether err is an API error for a request that failed validation
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// IsUnauthorized reports whether err is an API error for a request that was not authenticated
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsRateLimited reports whether err is an API error for a request that was rate limited
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface for APIError
 func (e *APIError) UnmarshalJSON(data []byte) error {
 	// Define a temporary struct to handle various error response formats
 	var errorResp struct {
-		Error   string `json:"error"`
-		Message string `json:"message"`
-		Details string `json:"details"`
-		Detail  string `json:"detail"`
+		Error        string `json:"error"`
+		Message      string `json:"message"`
+		ErrorMessage string `json:"error_message"`
+		Details      string `json:"details"`
+		Detail       string `json:"detail"`
 	}
 
 	if err := json.Unmarshal(data, &errorResp); err != nil {
@@ -44,6 +143,8 @@ func (e *APIError) UnmarshalJSON(data []byte) error {
 		e.Message = errorResp.Error
 	} else if errorResp.Message != "" {
 		e.Message = errorResp.Message
+	} else if errorResp.ErrorMessage != "" {
+		e.Message = errorResp.ErrorMessage
 	}
 
 	if errorResp.Details != "" {
@@ -52,5 +153,58 @@ func (e *APIError) UnmarshalJSON(data []byte) error {
 		e.Details = errorResp.Detail
 	}
 
+	e.FieldErrors = parseFieldErrors(data)
 	return nil
 }
+
+// parseFieldErrors extracts Tastypie validation errors, which are either keyed by field,
+// as in {"name": ["already exists"]}, or by resource and then field, as in
+// {"conference": {"name": ["already exists"]}}
+func parseFieldErrors(data []byte) []FieldError {
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil
+	}
+
+	var fieldErrors []FieldError
+	for key, raw := range body {
+		switch key {
+		case "error", "message", "error_message", "details", "detail", "traceback":
+			continue
+		}
+		if messages, ok := parseMessages(raw); ok {
+			fieldErrors = append(fieldErrors, FieldError{Field: key, Messages: messages})
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			continue
+		}
+		for field, fieldRaw := range fields {
+			if messages, ok := parseMessages(fieldRaw); ok {
+				fieldErrors = append(fieldErrors, FieldError{Resource: key, Field: field, Messages: messages})
+			}
+		}
+	}
+
+	slices.SortFunc(fieldErrors, func(a, b FieldError) int {
+		if c := strings.Compare(a.Resource, b.Resource); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Field, b.Field)
+	})
+	return fieldErrors
+}
+
+// parseMessages decodes a validation message or list of messages
+func parseMessages(raw json.RawMessage) ([]string, bool) {
+	var messages []string
+	if err := json.Unmarshal(raw, &messages); err == nil {
+		return messages, true
+	}
+	var message string
+	if err := json.Unmarshal(raw, &message); err == nil {
+		return []string{message}, true
+	}
+	return nil, false
+}
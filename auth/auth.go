@@ -5,10 +5,12 @@
  */
 
 // Package auth provides authentication implementations for the Pexip Infinity SDK.
-// It supports multiple authentication methods including Basic Auth, Token Auth, Bearer Auth, and custom authentication.
+// It supports multiple authentication methods including Basic Auth, Token Auth, Bearer Auth, session login, OAuth2 client credentials,
+// and custom authentication.
 package auth
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
@@ -18,10 +20,19 @@ type Authenticator interface {
 	Authenticate(req *http.Request) error
 }
 
+// Reauthenticator is implemented by authenticators whose credentials can expire. When the server rejects
+// a request, the client calls Reauthenticate with the response and, if it returns true, sends the request
+// once more with fresh credentials.
+type Reauthenticator interface {
+	Authenticator
+	Reauthenticate(ctx context.Context, resp *http.Response) (bool, error)
+}
+
 // BasicAuth implements HTTP Basic Authentication
 type BasicAuth struct {
 	username string
 	password string
+	provider CredentialProvider
 }
 
 // NewBasicAuth creates a new BasicAuth authenticator
@@ -32,15 +43,32 @@ func NewBasicAuth(username, password string) *BasicAuth {
 	}
 }
 
+// NewBasicAuthWithProvider creates a new BasicAuth authenticator that uses the current username and password
+// of provider for every request
+func NewBasicAuthWithProvider(provider CredentialProvider) *BasicAuth {
+	return &BasicAuth{
+		provider: provider,
+	}
+}
+
 // Authenticate adds basic authentication to the HTTP request
 func (b *BasicAuth) Authenticate(req *http.Request) error {
-	req.SetBasicAuth(b.username, b.password)
+	if b.provider == nil {
+		req.SetBasicAuth(b.username, b.password)
+		return nil
+	}
+	creds, err := b.provider.Credentials(req.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get credentials: %w", err)
+	}
+	req.SetBasicAuth(creds.Username, creds.Password)
 	return nil
 }
 
 // TokenAuth implements token-based authentication
 type TokenAuth struct {
-	token string
+	token    string
+	provider CredentialProvider
 }
 
 // NewTokenAuth creates a new TokenAuth authenticator
@@ -50,9 +78,25 @@ func NewTokenAuth(token string) *TokenAuth {
 	}
 }
 
+// NewTokenAuthWithProvider creates a new TokenAuth authenticator that uses the current token of provider
+// for every request
+func NewTokenAuthWithProvider(provider CredentialProvider) *TokenAuth {
+	return &TokenAuth{
+		provider: provider,
+	}
+}
+
 // Authenticate adds token authentication to the HTTP request
 func (t *TokenAuth) Authenticate(req *http.Request) error {
-	req.Header.Set("Authorization", "Token "+t.token)
+	token := t.token
+	if t.provider != nil {
+		creds, err := t.provider.Credentials(req.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get credentials: %w", err)
+		}
+		token = creds.Token
+	}
+	req.Header.Set("Authorization", "Token "+token)
 	return nil
 }
 
@@ -0,0 +1,187 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is how often FileCredentials checks its files for changes
+const DefaultPollInterval = 5 * time.Second
+
+// ErrNoCredentials is returned when a provider has no credentials to offer
+var ErrNoCredentials = errors.New("no credentials available")
+
+// Credentials are a username and password, a token, or both
+type Credentials struct {
+	Username string
+	Password string
+	Token    string
+}
+
+// CredentialProvider supplies the current credentials. Authenticators call it for every request, so
+// credentials can be rotated without rebuilding the client.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialProviderFunc adapts a function to the CredentialProvider interface, for example to read
+// credentials from a secrets manager
+type CredentialProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials calls f
+func (f CredentialProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// EnvCredentials reads credentials from environment variables each time they are needed
+type EnvCredentials struct {
+	usernameVar string
+	passwordVar string
+	tokenVar    string
+}
+
+// NewEnvCredentials creates a provider that reads the username and password from the named environment variables
+func NewEnvCredentials(usernameVar, passwordVar string) *EnvCredentials {
+	return &EnvCredentials{
+		usernameVar: usernameVar,
+		passwordVar: passwordVar,
+	}
+}
+
+// NewEnvToken creates a provider that reads a token from the named environment variable
+func NewEnvToken(tokenVar string) *EnvCredentials {
+	return &EnvCredentials{
+		tokenVar: tokenVar,
+	}
+}
+
+// Credentials returns the current values of the environment variables
+func (e *EnvCredentials) Credentials(context.Context) (Credentials, error) {
+	c := Credentials{
+		Username: lookupEnv(e.usernameVar),
+		Password: lookupEnv(e.passwordVar),
+		Token:    lookupEnv(e.tokenVar),
+	}
+	if c == (Credentials{}) {
+		return c, fmt.Errorf("%w: environment variables %s are not set", ErrNoCredentials, e.vars())
+	}
+	return c, nil
+}
+
+func (e *EnvCredentials) vars() string {
+	var vars []string
+	for _, name := range []string{e.usernameVar, e.passwordVar, e.tokenVar} {
+		if name != "" {
+			vars = append(vars, name)
+		}
+	}
+	return strings.Join(vars, ", ")
+}
+
+func lookupEnv(name string) string {
+	if name == "" {
+		return ""
+	}
+	return os.Getenv(name)
+}
+
+// FileCredentials reads credentials from the files username, password and token in a directory, such as a
+// mounted Kubernetes secret. The files are checked for changes at most once per poll interval and reread
+// when they change, so rotated secrets are picked up without restarting. It is safe for concurrent use.
+type FileCredentials struct {
+	dir      string
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	checked time.Time
+	stamps  map[string]fileStamp
+	current Credentials
+}
+
+// fileStamp identifies a version of a file
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewFileCredentials creates a provider that reads the credential files in dir and checks them for changes
+// every pollInterval, or every DefaultPollInterval if pollInterval is zero
+func NewFileCredentials(dir string, pollInterval time.Duration) *FileCredentials {
+	if pollInterval == 0 {
+		pollInterval = DefaultPollInterval
+	}
+	return &FileCredentials{
+		dir:      dir,
+		interval: pollInterval,
+		now:      time.Now,
+	}
+}
+
+// Credentials returns the contents of the credential files, rereading them if they have changed
+func (f *FileCredentials) Credentials(context.Context) (Credentials, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	if f.stamps != nil && now.Sub(f.checked) < f.interval {
+		return f.current, nil
+	}
+	f.checked = now
+
+	stamps := make(map[string]fileStamp, 3)
+	for _, name := range []string{"username", "password", "token"} {
+		info, err := os.Stat(filepath.Join(f.dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Credentials{}, fmt.Errorf("failed to read credentials: %w", err)
+		}
+		stamps[name] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	if f.stamps != nil && sameStamps(stamps, f.stamps) {
+		return f.current, nil
+	}
+
+	c := Credentials{}
+	for name, target := range map[string]*string{"username": &c.Username, "password": &c.Password, "token": &c.Token} {
+		if _, ok := stamps[name]; !ok {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(f.dir, name))
+		if err != nil {
+			return Credentials{}, fmt.Errorf("failed to read credentials: %w", err)
+		}
+		*target = strings.TrimRight(string(data), "\r\n")
+	}
+	if c == (Credentials{}) {
+		return c, fmt.Errorf("%w: no credential files in %s", ErrNoCredentials, f.dir)
+	}
+	f.stamps, f.current = stamps, c
+	return c, nil
+}
+
+func sameStamps(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, stamp := range a {
+		if other, ok := b[name]; !ok || !stamp.modTime.Equal(other.modTime) || stamp.size != other.size {
+			return false
+		}
+	}
+	return true
+}
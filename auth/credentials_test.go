@@ -0,0 +1,93 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("INFINITY_USERNAME", "admin")
+	t.Setenv("INFINITY_PASSWORD", "first")
+
+	provider := NewEnvCredentials("INFINITY_USERNAME", "INFINITY_PASSWORD")
+	creds, err := provider.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{Username: "admin", Password: "first"}, creds)
+
+	t.Setenv("INFINITY_PASSWORD", "second")
+	creds, err = provider.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "second", creds.Password)
+
+	_, err = NewEnvToken("INFINITY_UNSET_TOKEN").Credentials(t.Context())
+	assert.ErrorIs(t, err, ErrNoCredentials)
+}
+
+func TestFileCredentials(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "username"), []byte("admin\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "password"), []byte("first\n"), 0o600))
+
+	provider := NewFileCredentials(dir, time.Minute)
+	now := time.Now()
+	provider.now = func() time.Time { return now }
+
+	creds, err := provider.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{Username: "admin", Password: "first"}, creds)
+
+	// The secret is rotated, but is only picked up after the poll interval
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "password"), []byte("rotated\n"), 0o600))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "password"), now, now.Add(time.Second)))
+	creds, err = provider.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "first", creds.Password)
+
+	now = now.Add(time.Minute)
+	creds, err = provider.Credentials(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", creds.Password)
+
+	_, err = NewFileCredentials(t.TempDir(), 0).Credentials(t.Context())
+	assert.ErrorIs(t, err, ErrNoCredentials)
+}
+
+func TestBasicAuthWithProvider(t *testing.T) {
+	password := "first"
+	auth := NewBasicAuthWithProvider(CredentialProviderFunc(func(context.Context) (Credentials, error) {
+		return Credentials{Username: "admin", Password: password}, nil
+	}))
+
+	for _, password = range []string{"first", "second"} {
+		req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+		require.NoError(t, err)
+		require.NoError(t, auth.Authenticate(req))
+		assert.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("admin:"+password)), req.Header.Get("Authorization"))
+	}
+}
+
+func TestTokenAuthWithProvider_Error(t *testing.T) {
+	auth := NewTokenAuthWithProvider(CredentialProviderFunc(func(context.Context) (Credentials, error) {
+		return Credentials{}, errors.New("vault unavailable")
+	}))
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	require.NoError(t, err)
+	assert.ErrorContains(t, auth.Authenticate(req), "vault unavailable")
+	assert.Empty(t, req.Header.Get("Authorization"))
+}
@@ -0,0 +1,311 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers the hashes used to sign assertions
+	_ "crypto/sha512"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTokenPath is the path of the management node's OAuth2 token endpoint
+	DefaultTokenPath = "/oauth/token/"
+	// DefaultRefreshBefore is how long before expiry a cached token is replaced
+	DefaultRefreshBefore = time.Minute
+
+	clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	assertionLifetime   = 5 * time.Minute
+)
+
+// ErrTokenRequest is returned when an access token cannot be obtained from the token endpoint
+var ErrTokenRequest = errors.New("oauth2 token request failed")
+
+// OAuth2ClientCredentials implements the OAuth2 client credentials grant for clients created with
+// config.OAuth2Client. It authenticates to the token endpoint with a JWT assertion signed by the client's
+// private key, caches the access token, replaces it shortly before it expires and fetches a new one when
+// a request is rejected with 401. It is safe for concurrent use.
+type OAuth2ClientCredentials struct {
+	clientID      string
+	key           crypto.Signer
+	alg           string
+	hash          crypto.Hash
+	tokenURL      *url.URL
+	client        *http.Client
+	refreshBefore time.Duration
+	now           func() time.Time
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time // zero when the token does not expire
+}
+
+// OAuth2Option configures an OAuth2ClientCredentials
+type OAuth2Option func(*OAuth2ClientCredentials)
+
+// WithTokenPath sets the path of the token endpoint, DefaultTokenPath by default
+func WithTokenPath(path string) OAuth2Option {
+	return func(o *OAuth2ClientCredentials) {
+		o.tokenURL = o.tokenURL.ResolveReference(&url.URL{Path: path})
+	}
+}
+
+// WithOAuth2HTTPClient sets the HTTP client used to request tokens
+func WithOAuth2HTTPClient(client *http.Client) OAuth2Option {
+	return func(o *OAuth2ClientCredentials) {
+		o.client = client
+	}
+}
+
+// WithRefreshBefore sets how long before expiry a cached token is replaced, DefaultRefreshBefore by default
+func WithRefreshBefore(d time.Duration) OAuth2Option {
+	return func(o *OAuth2ClientCredentials) {
+		o.refreshBefore = d
+	}
+}
+
+// NewOAuth2ClientCredentials creates a new OAuth2ClientCredentials authenticator for the management node at
+// baseURL. privateKeyPEM is the PEM encoded ECDSA or RSA private key of the OAuth2 client, as returned in
+// config.OAuth2Client.PrivateKeyJWT.
+func NewOAuth2ClientCredentials(baseURL, clientID, privateKeyPEM string, opts ...OAuth2Option) (*OAuth2ClientCredentials, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL: %q", baseURL)
+	}
+	if clientID == "" {
+		return nil, fmt.Errorf("client ID cannot be empty")
+	}
+	key, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	alg, hash, err := signingAlgorithm(key)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &OAuth2ClientCredentials{
+		clientID:      clientID,
+		key:           key,
+		alg:           alg,
+		hash:          hash,
+		tokenURL:      u.ResolveReference(&url.URL{Path: DefaultTokenPath}),
+		client:        &http.Client{Timeout: 30 * time.Second},
+		refreshBefore: DefaultRefreshBefore,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o, nil
+}
+
+// Authenticate adds the cached access token to the HTTP request, requesting a new one if there is none or
+// it is about to expire
+func (o *OAuth2ClientCredentials) Authenticate(req *http.Request) error {
+	o.mu.Lock()
+	if o.token == "" || (!o.expiry.IsZero() && !o.now().Before(o.expiry.Add(-o.refreshBefore))) {
+		if err := o.fetch(req.Context()); err != nil {
+			o.mu.Unlock()
+			return err
+		}
+	}
+	token := o.token
+	o.mu.Unlock()
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Reauthenticate requests a new access token when resp was rejected with 401. Concurrent callers that were
+// rejected with the same token share a single token request.
+func (o *OAuth2ClientCredentials) Reauthenticate(ctx context.Context, resp *http.Response) (bool, error) {
+	if resp.StatusCode != http.StatusUnauthorized {
+		return false, nil
+	}
+	var rejected string
+	if resp.Request != nil {
+		rejected = strings.TrimPrefix(resp.Request.Header.Get("Authorization"), "Bearer ")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.token != "" && o.token != rejected {
+		// Another request has already replaced the token
+		return true, nil
+	}
+	if err := o.fetch(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// tokenResponse is the token endpoint's response, or its error response
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// fetch requests a new access token. It must be called with mu held.
+func (o *OAuth2ClientCredentials) fetch(ctx context.Context) error {
+	o.token, o.expiry = "", time.Time{}
+
+	assertion, err := o.assertion()
+	if err != nil {
+		return err
+	}
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {clientAssertionType},
+		"client_assertion":      {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.tokenURL.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrTokenRequest, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%w: failed to read response: %v", ErrTokenRequest, err)
+	}
+
+	var token tokenResponse
+	_ = json.Unmarshal(body, &token)
+	switch {
+	case resp.StatusCode >= 400 && token.Error != "":
+		return fmt.Errorf("%w: %s: %s", ErrTokenRequest, token.Error, token.ErrorDescription)
+	case resp.StatusCode >= 400:
+		return fmt.Errorf("%w: token endpoint returned %d", ErrTokenRequest, resp.StatusCode)
+	case token.AccessToken == "":
+		return fmt.Errorf("%w: no access token in response", ErrTokenRequest)
+	}
+
+	o.token = token.AccessToken
+	if token.ExpiresIn > 0 {
+		o.expiry = o.now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return nil
+}
+
+// assertion returns a signed JWT that authenticates the client to the token endpoint
+func (o *OAuth2ClientCredentials) assertion() (string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", fmt.Errorf("failed to generate assertion ID: %w", err)
+	}
+	now := o.now()
+	header, _ := json.Marshal(map[string]string{"alg": o.alg, "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss": o.clientID,
+		"sub": o.clientID,
+		"aud": o.tokenURL.String(),
+		"iat": now.Unix(),
+		"exp": now.Add(assertionLifetime).Unix(),
+		"jti": hex.EncodeToString(jti),
+	})
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	h := o.hash.New()
+	h.Write([]byte(signingInput))
+	sig, err := o.key.Sign(rand.Reader, h.Sum(nil), o.hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign assertion: %w", err)
+	}
+	if key, ok := o.key.(*ecdsa.PrivateKey); ok {
+		if sig, err = rawECDSASignature(sig, key.Curve); err != nil {
+			return "", err
+		}
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// parsePrivateKey parses a PEM encoded PKCS#8, SEC 1 or PKCS#1 private key
+func parsePrivateKey(privateKeyPEM string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(privateKeyPEM))
+	if block == nil {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("failed to parse private key")
+}
+
+// signingAlgorithm returns the JWS algorithm and hash for key
+func signingAlgorithm(key crypto.Signer) (string, crypto.Hash, error) {
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return "ES256", crypto.SHA256, nil
+		case elliptic.P384():
+			return "ES384", crypto.SHA384, nil
+		case elliptic.P521():
+			return "ES512", crypto.SHA512, nil
+		}
+		return "", 0, fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+	case *rsa.PrivateKey:
+		return "RS256", crypto.SHA256, nil
+	default:
+		return "", 0, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// rawECDSASignature converts an ASN.1 ECDSA signature to the fixed-size r || s form used by JWS
+func rawECDSASignature(der []byte, curve elliptic.Curve) ([]byte, error) {
+	var sig struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+	size := (curve.Params().BitSize + 7) / 8
+	raw := make([]byte, 2*size)
+	sig.R.FillBytes(raw[:size])
+	sig.S.FillBytes(raw[size:])
+	return raw, nil
+}
@@ -0,0 +1,164 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func privateKeyPEM(t *testing.T, key crypto.Signer) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+// tokenServer mimics the management node's token endpoint, verifying ES256 client assertions
+func tokenServer(t *testing.T, public *ecdsa.PublicKey, tokens *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, DefaultTokenPath, r.URL.Path)
+		assert.Equal(t, "client_credentials", r.PostFormValue("grant_type"))
+		assert.Equal(t, clientAssertionType, r.PostFormValue("client_assertion_type"))
+
+		parts := strings.Split(r.PostFormValue("client_assertion"), ".")
+		require.Len(t, parts, 3)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		require.Len(t, sig, 64)
+		if !ecdsa.Verify(public, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_client","error_description":"bad signature"}`))
+			return
+		}
+
+		claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		var c map[string]interface{}
+		require.NoError(t, json.Unmarshal(claims, &c))
+		assert.Equal(t, "client-id", c["iss"])
+		assert.Equal(t, "client-id", c["sub"])
+		assert.Equal(t, "http://"+r.Host+DefaultTokenPath, c["aud"])
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("token-%d", tokens.Add(1)),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOAuth2ClientCredentials_Authenticate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	var tokens atomic.Int32
+	server := tokenServer(t, &key.PublicKey, &tokens)
+
+	auth, err := NewOAuth2ClientCredentials(server.URL, "client-id", privateKeyPEM(t, key))
+	require.NoError(t, err)
+	now := time.Now()
+	auth.now = func() time.Time { return now }
+
+	for range 2 {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/api/admin/status/v1/alarm/", nil)
+		require.NoError(t, err)
+		require.NoError(t, auth.Authenticate(req))
+		assert.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+	}
+
+	// The token is replaced shortly before it expires
+	now = now.Add(time.Hour - DefaultRefreshBefore)
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/admin/status/v1/alarm/", nil)
+	require.NoError(t, err)
+	require.NoError(t, auth.Authenticate(req))
+	assert.Equal(t, "Bearer token-2", req.Header.Get("Authorization"))
+}
+
+func TestOAuth2ClientCredentials_Reauthenticate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	var tokens atomic.Int32
+	server := tokenServer(t, &key.PublicKey, &tokens)
+
+	auth, err := NewOAuth2ClientCredentials(server.URL, "client-id", privateKeyPEM(t, key))
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/admin/status/v1/alarm/", nil)
+	require.NoError(t, err)
+	require.NoError(t, auth.Authenticate(req))
+
+	again, err := auth.Reauthenticate(t.Context(), &http.Response{StatusCode: http.StatusForbidden, Request: req})
+	require.NoError(t, err)
+	assert.False(t, again)
+
+	// Many requests rejected with the same token share one token request
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			again, err := auth.Reauthenticate(t.Context(), &http.Response{StatusCode: http.StatusUnauthorized, Request: req})
+			assert.NoError(t, err)
+			assert.True(t, again)
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), tokens.Load())
+}
+
+func TestOAuth2ClientCredentials_TokenError(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	var tokens atomic.Int32
+	server := tokenServer(t, &other.PublicKey, &tokens)
+
+	auth, err := NewOAuth2ClientCredentials(server.URL, "client-id", privateKeyPEM(t, key))
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/admin/status/v1/alarm/", nil)
+	require.NoError(t, err)
+	err = auth.Authenticate(req)
+	assert.ErrorIs(t, err, ErrTokenRequest)
+	assert.Contains(t, err.Error(), "invalid_client")
+}
+
+func TestNewOAuth2ClientCredentials(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	auth, err := NewOAuth2ClientCredentials("https://manager.example.com", "client-id", privateKeyPEM(t, rsaKey),
+		WithTokenPath("/custom/token/"))
+	require.NoError(t, err)
+	assert.Equal(t, "RS256", auth.alg)
+	assert.Equal(t, "https://manager.example.com/custom/token/", auth.tokenURL.String())
+
+	_, err = NewOAuth2ClientCredentials("https://manager.example.com", "client-id", "not a key")
+	assert.Error(t, err)
+	_, err = NewOAuth2ClientCredentials("https://manager.example.com", "", privateKeyPEM(t, rsaKey))
+	assert.Error(t, err)
+}
@@ -0,0 +1,241 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLoginPath is the path of the management node's login form
+	DefaultLoginPath = "/admin/login/"
+
+	sessionCookie = "sessionid"
+	csrfCookie    = "csrftoken"
+	csrfHeader    = "X-CSRFToken"
+)
+
+// ErrLoginFailed is returned when the management node rejects the session login
+var ErrLoginFailed = errors.New("session login failed")
+
+// SessionAuth implements session cookie authentication. It logs in with a username and password on first use,
+// keeps the session and CSRF cookies in a cookie jar, adds the CSRF header to unsafe requests and logs in
+// again when a request is rejected because the session has expired. It is safe for concurrent use.
+type SessionAuth struct {
+	baseURL  *url.URL
+	loginURL *url.URL
+	username string
+	password string
+	provider CredentialProvider
+	client   *http.Client
+	jar      http.CookieJar
+
+	mu      sync.Mutex
+	session string // current session cookie, empty until logged in
+}
+
+// SessionOption configures a SessionAuth
+type SessionOption func(*SessionAuth)
+
+// WithLoginPath sets the path of the login form, DefaultLoginPath by default
+func WithLoginPath(path string) SessionOption {
+	return func(s *SessionAuth) {
+		s.loginURL = s.baseURL.ResolveReference(&url.URL{Path: path})
+	}
+}
+
+// WithSessionHTTPClient sets the HTTP client used to log in, for example to share the TLS configuration of the
+// API client. Its cookie jar and redirect policy are replaced.
+func WithSessionHTTPClient(client *http.Client) SessionOption {
+	return func(s *SessionAuth) {
+		login := *client
+		s.client = &login
+	}
+}
+
+// WithSessionCredentials logs in with the current username and password of provider instead of fixed ones,
+// so a rotated password is used the next time the session expires
+func WithSessionCredentials(provider CredentialProvider) SessionOption {
+	return func(s *SessionAuth) {
+		s.provider = provider
+	}
+}
+
+// NewSessionAuth creates a new SessionAuth authenticator for the management node at baseURL
+func NewSessionAuth(baseURL, username, password string, opts ...SessionOption) (*SessionAuth, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL: %q", baseURL)
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cookie jar: %w", err)
+	}
+
+	s := &SessionAuth{
+		baseURL:  u,
+		loginURL: u.ResolveReference(&url.URL{Path: DefaultLoginPath}),
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		jar:      jar,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.client.Jar = jar
+	s.client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return s, nil
+}
+
+// Authenticate adds the session cookies to the HTTP request, logging in first if there is no session,
+// and the CSRF header to requests with unsafe methods
+func (s *SessionAuth) Authenticate(req *http.Request) error {
+	s.mu.Lock()
+	if s.session == "" {
+		if err := s.login(req.Context()); err != nil {
+			s.mu.Unlock()
+			return err
+		}
+	}
+	s.mu.Unlock()
+
+	for _, cookie := range s.jar.Cookies(req.URL) {
+		req.AddCookie(cookie)
+	}
+	if !isSafeMethod(req.Method) {
+		if token := s.cookie(req.URL, csrfCookie); token != "" {
+			req.Header.Set(csrfHeader, token)
+		}
+		if req.Header.Get("Referer") == "" {
+			req.Header.Set("Referer", s.baseURL.String())
+		}
+	}
+	return nil
+}
+
+// Reauthenticate logs in again when resp shows that the session was rejected. Concurrent callers that were
+// rejected with the same session share a single login.
+func (s *SessionAuth) Reauthenticate(ctx context.Context, resp *http.Response) (bool, error) {
+	if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden {
+		return false, nil
+	}
+	var rejected string
+	if resp.Request != nil {
+		if cookie, err := resp.Request.Cookie(sessionCookie); err == nil {
+			rejected = cookie.Value
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.session != "" && s.session != rejected {
+		// Another request has already logged in again
+		return true, nil
+	}
+	if err := s.login(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// login fetches the login form for its CSRF cookie and posts the credentials. It must be called with mu held.
+func (s *SessionAuth) login(ctx context.Context) error {
+	// Expire the old session cookie, so that a rejected login is not mistaken for a successful one
+	previous := s.cookie(s.baseURL, sessionCookie)
+	s.session = ""
+	s.jar.SetCookies(s.baseURL, []*http.Cookie{{Name: sessionCookie, Path: "/", MaxAge: -1}})
+	s.jar.SetCookies(s.loginURL, []*http.Cookie{{Name: sessionCookie, MaxAge: -1}})
+	username, password := s.username, s.password
+	if s.provider != nil {
+		creds, err := s.provider.Credentials(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get credentials: %w", err)
+		}
+		username, password = creds.Username, creds.Password
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.loginURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create login request: %w", err)
+	}
+	if err = s.send(req); err != nil {
+		return err
+	}
+
+	csrf := s.cookie(s.loginURL, csrfCookie)
+	form := url.Values{
+		"username":            {username},
+		"password":            {password},
+		"csrfmiddlewaretoken": {csrf},
+		"next":                {s.baseURL.Path},
+	}
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, s.loginURL.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create login request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Referer", s.loginURL.String())
+	req.Header.Set(csrfHeader, csrf)
+	if err = s.send(req); err != nil {
+		return err
+	}
+
+	session := s.cookie(s.baseURL, sessionCookie)
+	if session == "" || session == previous {
+		return fmt.Errorf("%w: no new session cookie was issued for %s", ErrLoginFailed, username)
+	}
+	s.session = session
+	return nil
+}
+
+// send performs a login request, storing any cookies it sets
+func (s *SessionAuth) send(req *http.Request) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrLoginFailed, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%w: %s %s returned %d", ErrLoginFailed, req.Method, req.URL.Path, resp.StatusCode)
+	}
+	return nil
+}
+
+// cookie returns the value of the named cookie the jar holds for u
+func (s *SessionAuth) cookie(u *url.URL, name string) string {
+	for _, cookie := range s.jar.Cookies(u) {
+		if cookie.Name == name {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
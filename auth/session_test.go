@@ -0,0 +1,147 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loginServer mimics the management node's login form and session handling
+type loginServer struct {
+	*httptest.Server
+	logins atomic.Int32
+}
+
+func newLoginServer(t *testing.T) *loginServer {
+	s := &loginServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/login/", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: csrfCookie, Value: "csrf-token", Path: "/"})
+	})
+	mux.HandleFunc("POST /admin/login/", func(w http.ResponseWriter, r *http.Request) {
+		csrf, err := r.Cookie(csrfCookie)
+		if err != nil || csrf.Value != r.PostFormValue("csrfmiddlewaretoken") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.PostFormValue("username") != "admin" || r.PostFormValue("password") != "secret" {
+			return // the form is shown again
+		}
+		session := fmt.Sprintf("session-%d", s.logins.Add(1))
+		http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: session, Path: "/"})
+		w.Header().Set("Location", "/")
+		w.WriteHeader(http.StatusFound)
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestSessionAuth_Authenticate(t *testing.T) {
+	server := newLoginServer(t)
+	auth, err := NewSessionAuth(server.URL, "admin", "secret")
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/admin/status/v1/alarm/", nil)
+	require.NoError(t, err)
+	require.NoError(t, auth.Authenticate(req))
+
+	session, err := req.Cookie(sessionCookie)
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", session.Value)
+	assert.Empty(t, req.Header.Get(csrfHeader))
+
+	req, err = http.NewRequest(http.MethodPost, server.URL+"/api/admin/configuration/v1/conference/", nil)
+	require.NoError(t, err)
+	require.NoError(t, auth.Authenticate(req))
+
+	assert.Equal(t, "csrf-token", req.Header.Get(csrfHeader))
+	assert.Equal(t, server.URL, req.Header.Get("Referer"))
+	assert.Equal(t, int32(1), server.logins.Load())
+}
+
+func TestSessionAuth_LoginFailed(t *testing.T) {
+	server := newLoginServer(t)
+	auth, err := NewSessionAuth(server.URL, "admin", "wrong")
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/admin/status/v1/alarm/", nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, auth.Authenticate(req), ErrLoginFailed)
+}
+
+func TestSessionAuth_Reauthenticate(t *testing.T) {
+	server := newLoginServer(t)
+	auth, err := NewSessionAuth(server.URL, "admin", "secret")
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/admin/status/v1/alarm/", nil)
+	require.NoError(t, err)
+	require.NoError(t, auth.Authenticate(req))
+
+	again, err := auth.Reauthenticate(t.Context(), &http.Response{StatusCode: http.StatusNotFound, Request: req})
+	require.NoError(t, err)
+	assert.False(t, again)
+
+	// Many requests rejected with the same expired session share one login
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			again, err := auth.Reauthenticate(t.Context(), &http.Response{StatusCode: http.StatusUnauthorized, Request: req})
+			assert.NoError(t, err)
+			assert.True(t, again)
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), server.logins.Load())
+
+	req, err = http.NewRequest(http.MethodGet, server.URL+"/api/admin/status/v1/alarm/", nil)
+	require.NoError(t, err)
+	require.NoError(t, auth.Authenticate(req))
+	session, err := req.Cookie(sessionCookie)
+	require.NoError(t, err)
+	assert.Equal(t, "session-2", session.Value)
+}
+
+func TestSessionAuth_ReauthenticateRejected(t *testing.T) {
+	server := newLoginServer(t)
+	password := "secret"
+	auth, err := NewSessionAuth(server.URL, "", "", WithSessionCredentials(CredentialProviderFunc(func(context.Context) (Credentials, error) {
+		return Credentials{Username: "admin", Password: password}, nil
+	})))
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/admin/status/v1/alarm/", nil)
+	require.NoError(t, err)
+	require.NoError(t, auth.Authenticate(req))
+
+	// The login form is shown again, leaving the rejected session as the only session cookie
+	password = "rotated"
+	again, err := auth.Reauthenticate(t.Context(), &http.Response{StatusCode: http.StatusUnauthorized, Request: req})
+	assert.ErrorIs(t, err, ErrLoginFailed)
+	assert.False(t, again)
+
+	req, err = http.NewRequest(http.MethodGet, server.URL+"/api/admin/status/v1/alarm/", nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, auth.Authenticate(req), ErrLoginFailed)
+	_, err = req.Cookie(sessionCookie)
+	assert.ErrorIs(t, err, http.ErrNoCookie)
+}
+
+func TestNewSessionAuth_InvalidURL(t *testing.T) {
+	_, err := NewSessionAuth("not a url", "admin", "secret")
+	assert.Error(t, err)
+}
@@ -0,0 +1,314 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package bundle exports the Pexip Infinity configuration to a portable archive and imports
+// it into another deployment.
+//
+// A bundle is a zip archive holding a manifest and one JSON file per resource type. Resource
+// IDs and URIs are replaced by natural-key references such as "$ref:conference/sales", so a
+// bundle taken from one cluster can be imported into another, where the references are
+// re-linked to the resources that exist or are created there. Secrets such as passwords and
+// private keys are either stripped or encrypted with a passphrase.
+package bundle
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pexip/go-infinity-sdk/v41/config"
+	"github.com/pexip/go-infinity-sdk/v41/internal/resource"
+)
+
+const (
+	// FormatName identifies a configuration bundle in its manifest
+	FormatName = "infinity-config-bundle"
+	// FormatVersion is the bundle format version written by Export and understood by Import
+	FormatVersion = 1
+
+	manifestFile = "manifest.json"
+	resourceDir  = "resources/"
+	refPrefix    = "$ref:"
+	uriPrefix    = "/api/admin/configuration/v1/"
+)
+
+// keyFields are the fields tried, in order, to find the natural key of a resource
+var keyFields = []string{"name", "alias", "primary_email_address", "address", "domain", "hostname", "username", "url"}
+
+// ErrInvalidBundle is returned when an archive is not a readable configuration bundle
+var ErrInvalidBundle = errors.New("invalid configuration bundle")
+
+// ErrResourceURI is returned by Import when a bundle refers to a resource by its URI rather than
+// its natural key, since the URI would link to whatever resource has that ID in the target
+var ErrResourceURI = errors.New("bundle refers to a resource by URI")
+
+// Manifest describes the contents of a bundle
+type Manifest struct {
+	Format    string     `json:"format"`
+	Version   int        `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	Secrets   SecretMode `json:"secrets"`
+	// Salt is the key derivation salt for encrypted secrets
+	Salt []byte `json:"salt,omitempty"`
+	// Resources maps each exported resource type to the number of resources exported
+	Resources map[string]int `json:"resources"`
+}
+
+// Entry is a single resource in a bundle
+type Entry struct {
+	// Key is the natural key of the resource, or "#<id>" if it has no unique natural key
+	Key    string                 `json:"key"`
+	Object map[string]interface{} `json:"object"`
+}
+
+// ExportOptions configures Export
+type ExportOptions struct {
+	// Kinds limits the export to the given resource types, for example "conference".
+	// All resource types are exported if empty.
+	Kinds []string
+	// Secrets controls how secret fields are written. Defaults to SecretsStrip.
+	Secrets SecretMode
+	// Passphrase is required when Secrets is SecretsEncrypt
+	Passphrase string
+}
+
+// Export reads every configuration resource and writes it to w as a bundle
+func Export(ctx context.Context, svc *config.Service, w io.Writer, opts *ExportOptions) error {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	all := newKinds(svc)
+	kinds, err := selectKinds(all, opts.Kinds)
+	if err != nil {
+		return err
+	}
+
+	manifest := &Manifest{
+		Format:    FormatName,
+		Version:   FormatVersion,
+		CreatedAt: time.Now().UTC(),
+		Secrets:   opts.Secrets,
+		Resources: make(map[string]int, len(kinds)),
+	}
+	if manifest.Secrets == "" {
+		manifest.Secrets = SecretsStrip
+	}
+	secrets, err := newSecretWriter(manifest, opts.Passphrase)
+	if err != nil {
+		return err
+	}
+
+	// Read everything first so that references to any exported resource can be rewritten
+	objects := make(map[string][]resource.Object, len(kinds))
+	keys := make(map[string][]string, len(kinds))
+	refs := make(map[string]string)
+	for _, k := range kinds {
+		list, err := k.list(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", k.name, err)
+		}
+		objects[k.name] = list
+		keys[k.name] = indexRefs(refs, k.name, list)
+	}
+	// Types that are not exported are listed too, so that references to them are written as
+	// natural keys rather than URIs whose IDs mean nothing in the target
+	referenced := make(map[string]bool)
+	for _, list := range objects {
+		for _, obj := range list {
+			referencedKinds(obj, referenced)
+		}
+	}
+	for _, k := range all {
+		if _, ok := objects[k.name]; ok || !referenced[k.name] {
+			continue
+		}
+		list, err := k.list(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", k.name, err)
+		}
+		indexRefs(refs, k.name, list)
+	}
+
+	zw := zip.NewWriter(w)
+	for _, k := range kinds {
+		entries := make([]Entry, 0, len(objects[k.name]))
+		for i, obj := range objects[k.name] {
+			out := replaceRefs(resource.WithoutMeta(resource.Flatten(obj)), refs).(resource.Object)
+			if err = secrets.write(out); err != nil {
+				return fmt.Errorf("failed to export %s %q: %w", k.name, keys[k.name][i], err)
+			}
+			entries = append(entries, Entry{Key: keys[k.name][i], Object: out})
+		}
+		slices.SortFunc(entries, func(a, b Entry) int { return strings.Compare(a.Key, b.Key) })
+		if err = writeJSON(zw, resourceDir+k.name+".json", entries); err != nil {
+			return err
+		}
+		manifest.Resources[k.name] = len(entries)
+	}
+	if err = writeJSON(zw, manifestFile, manifest); err != nil {
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+// selectKinds returns the kinds named in names, or all kinds if names is empty
+func selectKinds(all []kind, names []string) ([]kind, error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(all, func(k kind) bool { return k.name == name }) {
+			return nil, fmt.Errorf("unknown resource type %q", name)
+		}
+	}
+	return slices.DeleteFunc(slices.Clone(all), func(k kind) bool { return !slices.Contains(names, k.name) }), nil
+}
+
+// naturalKeys returns the natural key of each object. Objects without a natural key, or
+// whose natural key is shared with another object of the same type, are keyed by ID.
+func naturalKeys(objects []resource.Object) []string {
+	keys := make([]string, len(objects))
+	counts := make(map[string]int)
+	for i, obj := range objects {
+		for _, field := range keyFields {
+			if v, ok := obj[field].(string); ok && v != "" {
+				keys[i] = v
+				break
+			}
+		}
+		counts[keys[i]]++
+	}
+	for i, obj := range objects {
+		if keys[i] == "" || counts[keys[i]] > 1 {
+			keys[i] = "#" + strconv.Itoa(resource.ID(obj))
+		}
+	}
+	return keys
+}
+
+// indexRefs adds the reference of each object to refs, keyed by its resource URI, and returns
+// the natural keys of the objects
+func indexRefs(refs map[string]string, kind string, objects []resource.Object) []string {
+	keys := naturalKeys(objects)
+	for i, obj := range objects {
+		if uri, _ := obj["resource_uri"].(string); uri != "" {
+			refs[uri] = refPrefix + kind + "/" + keys[i]
+		}
+	}
+	return keys
+}
+
+// referencedKinds adds the type of every resource URI in value to found
+func referencedKinds(value interface{}, found map[string]bool) {
+	switch v := value.(type) {
+	case string:
+		if rest, ok := strings.CutPrefix(v, uriPrefix); ok {
+			if name, _, ok := strings.Cut(rest, "/"); ok {
+				found[name] = true
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			referencedKinds(item, found)
+		}
+	case []interface{}:
+		for _, item := range v {
+			referencedKinds(item, found)
+		}
+	}
+}
+
+// resourceURI returns the first resource URI in value, or "" if there is none
+func resourceURI(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		if strings.HasPrefix(v, "/api/admin/") {
+			return v
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			if uri := resourceURI(item); uri != "" {
+				return uri
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if uri := resourceURI(item); uri != "" {
+				return uri
+			}
+		}
+	}
+	return ""
+}
+
+// replaceRefs returns a copy of value with every resource URI in refs replaced by its reference
+func replaceRefs(value interface{}, refs map[string]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		if ref, ok := refs[v]; ok {
+			return ref
+		}
+		return v
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for field, item := range v {
+			out[field] = replaceRefs(item, refs)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = replaceRefs(item, refs)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// parseRef splits a "$ref:kind/key" reference into its kind and key
+func parseRef(s string) (kind, key string, ok bool) {
+	ref, found := strings.CutPrefix(s, refPrefix)
+	if !found {
+		return "", "", false
+	}
+	return strings.Cut(ref, "/")
+}
+
+func writeJSON(zw *zip.Writer, name string, v interface{}) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func readJSON(zr *zip.Reader, name string, v interface{}) error {
+	f, err := zr.Open(name)
+	if err != nil {
+		return fmt.Errorf("%w: failed to open %s: %w", ErrInvalidBundle, name, err)
+	}
+	defer f.Close()
+	if err = json.NewDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf("%w: failed to read %s: %w", ErrInvalidBundle, name, err)
+	}
+	return nil
+}
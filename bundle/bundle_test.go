@@ -0,0 +1,271 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/pexip/go-infinity-sdk/v41/config"
+	"github.com/pexip/go-infinity-sdk/v41/interfaces"
+	"github.com/pexip/go-infinity-sdk/v41/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testKinds = []string{"conference", "conference_alias", "device"}
+
+func mockList(m *interfaces.HTTPClientMock, endpoint string, body string) {
+	m.On("GetJSON", mock.Anything, endpoint, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		if err := json.Unmarshal([]byte(body), args.Get(3)); err != nil {
+			panic(err)
+		}
+	})
+}
+
+func mockSource(m *interfaces.HTTPClientMock) {
+	mockList(m, "configuration/v1/conference/", `{"meta": {}, "objects": [
+		{"id": 1, "name": "support", "service_type": "conference", "resource_uri": "/api/admin/configuration/v1/conference/1/",
+		 "aliases": [{"id": 11, "alias": "support@example.com", "conference": "/api/admin/configuration/v1/conference/1/", "resource_uri": "/api/admin/configuration/v1/conference_alias/11/"}]}
+	]}`)
+	mockList(m, "configuration/v1/conference_alias/", `{"meta": {}, "objects": [
+		{"id": 11, "alias": "support@example.com", "conference": "/api/admin/configuration/v1/conference/1/", "resource_uri": "/api/admin/configuration/v1/conference_alias/11/"}
+	]}`)
+	mockList(m, "configuration/v1/device/", `{"meta": {}, "objects": [
+		{"id": 5, "alias": "room@example.com", "username": "room", "password": "s3cret", "resource_uri": "/api/admin/configuration/v1/device/5/"}
+	]}`)
+}
+
+func export(t *testing.T, opts *ExportOptions) []byte {
+	t.Helper()
+	client := interfaces.NewHTTPClientMock()
+	mockSource(client)
+
+	var buf bytes.Buffer
+	require.NoError(t, Export(t.Context(), config.New(client), &buf, opts))
+	return buf.Bytes()
+}
+
+func readEntries(t *testing.T, data []byte, kind string) []Entry {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	var entries []Entry
+	require.NoError(t, readJSON(zr, resourceDir+kind+".json", &entries))
+	return entries
+}
+
+func TestExport(t *testing.T) {
+	data := export(t, &ExportOptions{Kinds: testKinds})
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	var manifest Manifest
+	require.NoError(t, readJSON(zr, manifestFile, &manifest))
+	assert.Equal(t, FormatName, manifest.Format)
+	assert.Equal(t, SecretsStrip, manifest.Secrets)
+	assert.Equal(t, map[string]int{"conference": 1, "conference_alias": 1, "device": 1}, manifest.Resources)
+
+	conferences := readEntries(t, data, "conference")
+	require.Len(t, conferences, 1)
+	assert.Equal(t, "support", conferences[0].Key)
+	assert.Equal(t, []interface{}{"$ref:conference_alias/support@example.com"}, conferences[0].Object["aliases"])
+	assert.NotContains(t, conferences[0].Object, "id")
+	assert.NotContains(t, conferences[0].Object, "resource_uri")
+
+	aliases := readEntries(t, data, "conference_alias")
+	require.Len(t, aliases, 1)
+	assert.Equal(t, "$ref:conference/support", aliases[0].Object["conference"])
+
+	devices := readEntries(t, data, "device")
+	require.Len(t, devices, 1)
+	assert.NotContains(t, devices[0].Object, "password")
+}
+
+func TestExport_ReferencedKinds(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+	mockSource(client)
+
+	var buf bytes.Buffer
+	require.NoError(t, Export(t.Context(), config.New(client), &buf, &ExportOptions{Kinds: []string{"conference_alias"}}))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	var manifest Manifest
+	require.NoError(t, readJSON(zr, manifestFile, &manifest))
+	assert.Equal(t, map[string]int{"conference_alias": 1}, manifest.Resources)
+
+	aliases := readEntries(t, buf.Bytes(), "conference_alias")
+	require.Len(t, aliases, 1)
+	assert.Equal(t, "$ref:conference/support", aliases[0].Object["conference"])
+	client.AssertNotCalled(t, "GetJSON", mock.Anything, "configuration/v1/device/", mock.Anything, mock.Anything)
+
+	target := interfaces.NewHTTPClientMock()
+	mockList(target, "configuration/v1/conference_alias/", `{"meta": {}, "objects": []}`)
+	mockList(target, "configuration/v1/conference/", `{"meta": {}, "objects": [
+		{"id": 40, "name": "support", "resource_uri": "/api/admin/configuration/v1/conference/40/"}
+	]}`)
+	target.On("PostWithResponse", mock.Anything, "configuration/v1/conference_alias/", &config.ConferenceAliasCreateRequest{
+		Alias:      "support@example.com",
+		Conference: "/api/admin/configuration/v1/conference/40/",
+	}, nil).Return(&types.PostResponse{ResourceURI: "/api/admin/configuration/v1/conference_alias/41/"}, nil)
+
+	result, err := Import(t.Context(), config.New(target), bytes.NewReader(buf.Bytes()), nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"conference_alias/support@example.com"}, result.Created)
+	assert.Empty(t, result.Unresolved)
+	target.AssertExpectations(t)
+}
+
+func TestExport_EncryptRequiresPassphrase(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+
+	err := Export(t.Context(), config.New(client), &bytes.Buffer{}, &ExportOptions{Secrets: SecretsEncrypt})
+
+	assert.ErrorIs(t, err, ErrPassphrase)
+	client.AssertNotCalled(t, "GetJSON", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestImport(t *testing.T) {
+	data := export(t, &ExportOptions{Kinds: testKinds, Secrets: SecretsEncrypt, Passphrase: "correct horse"})
+	devices := readEntries(t, data, "device")
+	require.Len(t, devices, 1)
+	assert.Contains(t, devices[0].Object["password"], encPrefix)
+
+	client := interfaces.NewHTTPClientMock()
+	mockList(client, "configuration/v1/conference/", `{"meta": {}, "objects": []}`)
+	mockList(client, "configuration/v1/conference_alias/", `{"meta": {}, "objects": []}`)
+	mockList(client, "configuration/v1/device/", `{"meta": {}, "objects": [
+		{"id": 7, "alias": "room@example.com", "resource_uri": "/api/admin/configuration/v1/device/7/"}
+	]}`)
+
+	var order []string
+	client.On("PostWithResponse", mock.Anything, "configuration/v1/conference/", mock.MatchedBy(func(req *config.ConferenceCreateRequest) bool {
+		return req.Name == "support" && (req.Aliases == nil || len(*req.Aliases) == 0)
+	}), nil).Return(&types.PostResponse{ResourceURI: "/api/admin/configuration/v1/conference/21/"}, nil).Run(func(mock.Arguments) {
+		order = append(order, "conference")
+	})
+	client.On("PostWithResponse", mock.Anything, "configuration/v1/conference_alias/", &config.ConferenceAliasCreateRequest{
+		Alias:      "support@example.com",
+		Conference: "/api/admin/configuration/v1/conference/21/",
+	}, nil).Return(&types.PostResponse{ResourceURI: "/api/admin/configuration/v1/conference_alias/31/"}, nil).Run(func(mock.Arguments) {
+		order = append(order, "conference_alias")
+	})
+	client.On("PutJSON", mock.Anything, "configuration/v1/conference/21/", mock.MatchedBy(func(req *config.ConferenceUpdateRequest) bool {
+		return req.Aliases != nil && len(*req.Aliases) == 1 && (*req.Aliases)[0] == "/api/admin/configuration/v1/conference_alias/31/"
+	}), mock.Anything).Return(nil)
+
+	result, err := Import(t.Context(), config.New(client), bytes.NewReader(data), &ImportOptions{Passphrase: "correct horse"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"conference", "conference_alias"}, order)
+	assert.Equal(t, []string{"conference/support", "conference_alias/support@example.com"}, result.Created)
+	assert.Equal(t, []string{"device/room@example.com"}, result.Existing)
+	assert.Empty(t, result.Unresolved)
+	client.AssertExpectations(t)
+}
+
+func TestImport_Unkeyed(t *testing.T) {
+	source := interfaces.NewHTTPClientMock()
+	mockList(source, "configuration/v1/device/", `{"meta": {}, "objects": [
+		{"id": 5, "alias": "room@example.com", "description": "east", "resource_uri": "/api/admin/configuration/v1/device/5/"},
+		{"id": 6, "alias": "room@example.com", "description": "west", "resource_uri": "/api/admin/configuration/v1/device/6/"}
+	]}`)
+	var buf bytes.Buffer
+	require.NoError(t, Export(t.Context(), config.New(source), &buf, &ExportOptions{Kinds: []string{"device"}}))
+
+	client := interfaces.NewHTTPClientMock()
+	mockList(client, "configuration/v1/device/", `{"meta": {}, "objects": []}`)
+	result, err := Import(t.Context(), config.New(client), bytes.NewReader(buf.Bytes()), nil)
+	require.NoError(t, err)
+	assert.Empty(t, result.Created)
+	assert.Equal(t, []string{"device/#5", "device/#6"}, result.Skipped)
+	client.AssertNotCalled(t, "PostWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	client.On("PostWithResponse", mock.Anything, "configuration/v1/device/", mock.Anything, nil).
+		Return(&types.PostResponse{ResourceURI: "/api/admin/configuration/v1/device/9/"}, nil).Twice()
+	result, err = Import(t.Context(), config.New(client), bytes.NewReader(buf.Bytes()), &ImportOptions{CreateUnkeyed: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"device/#5", "device/#6"}, result.Created)
+	client.AssertExpectations(t)
+}
+
+func TestImport_ResourceURI(t *testing.T) {
+	source := interfaces.NewHTTPClientMock()
+	mockList(source, "configuration/v1/conference_alias/", `{"meta": {}, "objects": [
+		{"id": 11, "alias": "support@example.com", "conference": "/api/admin/configuration/v1/conference/99/", "resource_uri": "/api/admin/configuration/v1/conference_alias/11/"}
+	]}`)
+	mockList(source, "configuration/v1/conference/", `{"meta": {}, "objects": []}`)
+	var buf bytes.Buffer
+	require.NoError(t, Export(t.Context(), config.New(source), &buf, &ExportOptions{Kinds: []string{"conference_alias"}}))
+
+	client := interfaces.NewHTTPClientMock()
+	_, err := Import(t.Context(), config.New(client), bytes.NewReader(buf.Bytes()), nil)
+	assert.ErrorIs(t, err, ErrResourceURI)
+	client.AssertNotCalled(t, "PostWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	mockList(client, "configuration/v1/conference_alias/", `{"meta": {}, "objects": []}`)
+	client.On("PostWithResponse", mock.Anything, "configuration/v1/conference_alias/", &config.ConferenceAliasCreateRequest{
+		Alias:      "support@example.com",
+		Conference: "/api/admin/configuration/v1/conference/99/",
+	}, nil).Return(&types.PostResponse{ResourceURI: "/api/admin/configuration/v1/conference_alias/12/"}, nil)
+	result, err := Import(t.Context(), config.New(client), bytes.NewReader(buf.Bytes()), &ImportOptions{AllowResourceURIs: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"conference_alias/support@example.com"}, result.Created)
+	client.AssertExpectations(t)
+}
+
+func TestImport_DecryptsSecrets(t *testing.T) {
+	data := export(t, &ExportOptions{Kinds: []string{"device"}, Secrets: SecretsEncrypt, Passphrase: "correct horse"})
+
+	client := interfaces.NewHTTPClientMock()
+	mockList(client, "configuration/v1/device/", `{"meta": {}, "objects": []}`)
+	client.On("PostWithResponse", mock.Anything, "configuration/v1/device/", mock.MatchedBy(func(req *config.DeviceCreateRequest) bool {
+		return req.Alias == "room@example.com" && req.Password == "s3cret"
+	}), nil).Return(&types.PostResponse{ResourceURI: "/api/admin/configuration/v1/device/8/"}, nil)
+
+	result, err := Import(t.Context(), config.New(client), bytes.NewReader(data), &ImportOptions{Passphrase: "correct horse"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"device/room@example.com"}, result.Created)
+	client.AssertExpectations(t)
+}
+
+func TestImport_Passphrase(t *testing.T) {
+	data := export(t, &ExportOptions{Kinds: []string{"device"}, Secrets: SecretsEncrypt, Passphrase: "correct horse"})
+
+	tests := []struct {
+		name       string
+		passphrase string
+	}{
+		{name: "missing", passphrase: ""},
+		{name: "wrong", passphrase: "battery staple"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := interfaces.NewHTTPClientMock()
+
+			_, err := Import(t.Context(), config.New(client), bytes.NewReader(data), &ImportOptions{Passphrase: tt.passphrase})
+
+			assert.ErrorIs(t, err, ErrPassphrase)
+			client.AssertNotCalled(t, "PostWithResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestImport_InvalidBundle(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+
+	_, err := Import(t.Context(), config.New(client), bytes.NewReader([]byte("not a zip")), nil)
+
+	assert.ErrorIs(t, err, ErrInvalidBundle)
+}
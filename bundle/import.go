@@ -0,0 +1,305 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/pexip/go-infinity-sdk/v41/config"
+	"github.com/pexip/go-infinity-sdk/v41/internal/resource"
+	"github.com/pexip/go-infinity-sdk/v41/types"
+)
+
+// ImportOptions configures Import
+type ImportOptions struct {
+	// Kinds limits the import to the given resource types. All resource types in the
+	// bundle are imported if empty; other types are still used to resolve references.
+	Kinds []string
+	// Passphrase decrypts secrets in bundles exported with SecretsEncrypt
+	Passphrase string
+	// CreateUnkeyed creates resources that have no unique natural key, which are keyed "#<id>" in the
+	// bundle. They cannot be matched against the target, so importing a bundle again creates them again;
+	// they are skipped unless this is set.
+	CreateUnkeyed bool
+	// AllowResourceURIs imports resources whose references were exported as resource URIs rather than
+	// natural keys, for example because they refer to a type the bundle cannot export. The URIs are
+	// kept as they are and link to whatever resource has that ID in the target; Import fails with
+	// ErrResourceURI unless this is set.
+	AllowResourceURIs bool
+}
+
+// ImportResult reports what Import did. Resources are identified as "kind/key".
+type ImportResult struct {
+	// Created lists the resources created in the target
+	Created []string
+	// Existing lists the resources left unchanged because their natural key already exists in the target
+	Existing []string
+	// Skipped lists the resources of types that cannot be imported, such as licences and file uploads,
+	// and the resources without a natural key unless ImportOptions.CreateUnkeyed is set
+	Skipped []string
+	// Unresolved lists the created resources with references that could not be re-linked
+	Unresolved []string
+}
+
+// pending is a created resource whose references must be re-linked once all resources exist
+type pending struct {
+	kind *kind
+	key  string
+	uri  string
+	obj  resource.Object
+}
+
+// Import reads a bundle from r and creates its resources in the deployment behind svc.
+// Resources whose natural key already exists in the target are left unchanged and their
+// references point at the existing resource; resources without a natural key are only
+// created if opts.CreateUnkeyed is set. Resources are created in dependency order;
+// references that cannot be resolved when a resource is created, such as cycles or
+// many-to-many links, are omitted and set by a second pass of updates.
+func Import(ctx context.Context, svc *config.Service, r io.Reader, opts *ImportOptions) (*ImportResult, error) {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+
+	var manifest Manifest
+	if err = readJSON(zr, manifestFile, &manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Format != FormatName || manifest.Version != FormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format %q version %d", ErrInvalidBundle, manifest.Format, manifest.Version)
+	}
+
+	all := newKinds(svc)
+	names := make([]string, 0, len(manifest.Resources))
+	for name := range manifest.Resources {
+		names = append(names, name)
+	}
+	kinds, err := selectKinds(all, names)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+	if len(opts.Kinds) > 0 {
+		if _, err = selectKinds(all, opts.Kinds); err != nil {
+			return nil, err
+		}
+	}
+
+	secrets := &secretReader{manifest: &manifest, passphrase: opts.Passphrase}
+	entries := make(map[string][]Entry, len(kinds))
+	for _, k := range kinds {
+		var list []Entry
+		if err = readJSON(zr, resourceDir+k.name+".json", &list); err != nil {
+			return nil, err
+		}
+		for _, e := range list {
+			if _, err = secrets.read(e.Object); err != nil {
+				return nil, fmt.Errorf("%s %q: %w", k.name, e.Key, err)
+			}
+		}
+		entries[k.name] = list
+	}
+	if !opts.AllowResourceURIs {
+		for _, k := range kinds {
+			if len(opts.Kinds) > 0 && !slices.Contains(opts.Kinds, k.name) {
+				continue
+			}
+			for _, e := range entries[k.name] {
+				if uri := resourceURI(e.Object); uri != "" {
+					return nil, fmt.Errorf("%w: %s %q refers to %s", ErrResourceURI, k.name, e.Key, uri)
+				}
+			}
+		}
+	}
+
+	// Index the target by natural key so that existing resources are matched and referenced,
+	// including resources of types that are referenced but not in the bundle
+	referenced := make(map[string]bool)
+	for _, list := range entries {
+		for _, e := range list {
+			refKinds(e.Object, referenced)
+		}
+	}
+	indexed := slices.Clone(kinds)
+	for _, k := range all {
+		if referenced[k.name] && !slices.ContainsFunc(kinds, func(b kind) bool { return b.name == k.name }) {
+			indexed = append(indexed, k)
+		}
+	}
+	uris := make(map[string]map[string]string, len(indexed))
+	for _, k := range indexed {
+		list, err := k.list(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", k.name, err)
+		}
+		uris[k.name] = make(map[string]string, len(list))
+		for i, key := range naturalKeys(list) {
+			if !strings.HasPrefix(key, "#") {
+				uris[k.name][key], _ = list[i]["resource_uri"].(string)
+			}
+		}
+	}
+
+	result := &ImportResult{}
+	var deferred []pending
+	for _, k := range importOrder(kinds, entries) {
+		if len(opts.Kinds) > 0 && !slices.Contains(opts.Kinds, k.name) {
+			continue
+		}
+		for _, e := range entries[k.name] {
+			id := k.name + "/" + e.Key
+			if _, ok := uris[k.name][e.Key]; ok {
+				result.Existing = append(result.Existing, id)
+				continue
+			}
+			if k.create == nil || (strings.HasPrefix(e.Key, "#") && !opts.CreateUnkeyed) {
+				result.Skipped = append(result.Skipped, id)
+				continue
+			}
+			obj, complete := resolveRefs(e.Object, uris)
+			uri, err := k.create(ctx, obj.(resource.Object))
+			if err != nil {
+				return result, fmt.Errorf("failed to create %s %q: %w", k.name, e.Key, err)
+			}
+			uris[k.name][e.Key] = uri
+			result.Created = append(result.Created, id)
+			if !complete {
+				deferred = append(deferred, pending{kind: k, key: e.Key, uri: uri, obj: e.Object})
+			}
+		}
+	}
+
+	for _, p := range deferred {
+		obj, complete := resolveRefs(p.obj, uris)
+		if p.kind.update == nil {
+			result.Unresolved = append(result.Unresolved, p.kind.name+"/"+p.key)
+			continue
+		}
+		id, err := (&types.PostResponse{ResourceURI: p.uri}).ResourceID()
+		if err != nil {
+			return result, err
+		}
+		if err = p.kind.update(ctx, id, obj.(resource.Object)); err != nil {
+			return result, fmt.Errorf("failed to update %s %q: %w", p.kind.name, p.key, err)
+		}
+		if !complete {
+			result.Unresolved = append(result.Unresolved, p.kind.name+"/"+p.key)
+		}
+	}
+	return result, nil
+}
+
+// resolveRefs returns a copy of value with references replaced by resource URIs in uris.
+// Unresolved references are omitted, and complete reports whether there were none.
+func resolveRefs(value interface{}, uris map[string]map[string]string) (resolved interface{}, complete bool) {
+	switch v := value.(type) {
+	case string:
+		kind, key, ok := parseRef(v)
+		if !ok {
+			return v, true
+		}
+		if uri := uris[kind][key]; uri != "" {
+			return uri, true
+		}
+		return nil, false
+	case map[string]interface{}:
+		complete = true
+		out := make(map[string]interface{}, len(v))
+		for field, item := range v {
+			r, c := resolveRefs(item, uris)
+			if c || r != nil {
+				out[field] = r
+			}
+			complete = complete && c
+		}
+		return out, complete
+	case []interface{}:
+		complete = true
+		out := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			r, c := resolveRefs(item, uris)
+			if c || r != nil {
+				out = append(out, r)
+			}
+			complete = complete && c
+		}
+		return out, complete
+	default:
+		return v, true
+	}
+}
+
+// refKinds adds the type of every reference in value to found
+func refKinds(value interface{}, found map[string]bool) {
+	switch v := value.(type) {
+	case string:
+		if kind, _, ok := parseRef(v); ok {
+			found[kind] = true
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			refKinds(item, found)
+		}
+	case []interface{}:
+		for _, item := range v {
+			refKinds(item, found)
+		}
+	}
+}
+
+// importOrder sorts kinds so that resources referenced by a single-valued field are created
+// before the resources that refer to them. List-valued references, which are usually
+// many-to-many or reverse relations, do not constrain the order and are re-linked afterwards.
+func importOrder(kinds []kind, entries map[string][]Entry) []*kind {
+	deps := make(map[string][]string, len(kinds))
+	for _, k := range kinds {
+		for _, e := range entries[k.name] {
+			for _, value := range e.Object {
+				s, _ := value.(string)
+				if dep, _, ok := parseRef(s); ok && dep != k.name && !slices.Contains(deps[k.name], dep) {
+					deps[k.name] = append(deps[k.name], dep)
+				}
+			}
+		}
+		slices.Sort(deps[k.name])
+	}
+
+	byName := make(map[string]*kind, len(kinds))
+	for i := range kinds {
+		byName[kinds[i].name] = &kinds[i]
+	}
+	order := make([]*kind, 0, len(kinds))
+	state := make(map[string]int) // 1 while visiting, 2 once ordered
+	var visit func(name string)
+	visit = func(name string) {
+		if state[name] != 0 || byName[name] == nil {
+			return // ordered already, or a cycle that the second pass resolves
+		}
+		state[name] = 1
+		for _, dep := range deps[name] {
+			visit(dep)
+		}
+		state[name] = 2
+		order = append(order, byName[name])
+	}
+	for _, k := range kinds {
+		visit(k.name)
+	}
+	return order
+}
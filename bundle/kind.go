@@ -0,0 +1,496 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bundle
+
+import (
+	"github.com/pexip/go-infinity-sdk/v41/config"
+	"github.com/pexip/go-infinity-sdk/v41/internal/resource"
+)
+
+// kind describes how one configuration resource type is read and written
+type kind struct {
+	name   string
+	list   resource.ListFunc
+	create resource.CreateFunc // nil for resources that cannot be imported
+	update resource.UpdateFunc // nil for resources that cannot be updated after import
+}
+
+// newKinds returns every listable configuration resource type. Resources created from file
+// uploads, such as IVR themes, media library entries and web app brandings, licences, which
+// are bound to the deployment they were activated on, and read-only resources are exported
+// but cannot be imported; on import they are only used to resolve references to matching
+// resources that already exist in the target.
+func newKinds(svc *config.Service) []kind {
+	return []kind{
+		{
+			name:   "adfs_auth_server",
+			list:   resource.ListWith(svc.AllADFSAuthServers),
+			create: resource.CreateWith(svc.CreateADFSAuthServer),
+			update: resource.UpdateWith(svc.UpdateADFSAuthServer),
+		},
+		{
+			name:   "adfs_auth_server_domain",
+			list:   resource.ListWith(svc.AllADFSAuthServerDomains),
+			create: resource.CreateWith(svc.CreateADFSAuthServerDomain),
+			update: resource.UpdateWith(svc.UpdateADFSAuthServerDomain),
+		},
+		{
+			name:   "automatic_participant",
+			list:   resource.ListWith(svc.AllAutomaticParticipants),
+			create: resource.CreateWith(svc.CreateAutomaticParticipant),
+			update: resource.UpdateWith(svc.UpdateAutomaticParticipant),
+		},
+		{
+			name:   "azure_tenant",
+			list:   resource.ListWith(svc.AllAzureTenants),
+			create: resource.CreateWith(svc.CreateAzureTenant),
+			update: resource.UpdateWith(svc.UpdateAzureTenant),
+		},
+		{
+			name:   "break_in_allow_list_address",
+			list:   resource.ListWith(svc.AllBreakInAllowListAddresses),
+			create: resource.CreateWith(svc.CreateBreakInAllowListAddress),
+			update: resource.UpdateWith(svc.UpdateBreakInAllowListAddress),
+		},
+		{
+			name:   "ca_certificate",
+			list:   resource.ListWith(svc.AllCACertificates),
+			create: resource.CreateWith(svc.CreateCACertificate),
+			update: resource.UpdateWith(svc.UpdateCACertificate),
+		},
+		{
+			name:   "certificate_signing_request",
+			list:   resource.ListWith(svc.AllCertificateSigningRequests),
+			create: resource.CreateWith(svc.CreateCertificateSigningRequest),
+			update: resource.UpdateWith(svc.UpdateCertificateSigningRequest),
+		},
+		{
+			name:   "conference",
+			list:   resource.ListWith(svc.AllConferences),
+			create: resource.CreateWith(svc.CreateConference),
+			update: resource.UpdateWith(svc.UpdateConference),
+		},
+		{
+			name:   "conference_alias",
+			list:   resource.ListWith(svc.AllConferenceAliases),
+			create: resource.CreateWith(svc.CreateConferenceAlias),
+			update: resource.UpdateWith(svc.UpdateConferenceAlias),
+		},
+		{
+			name:   "conference_sync_template",
+			list:   resource.ListWith(svc.AllConferenceSyncTemplates),
+			create: resource.CreateWith(svc.CreateConferenceSyncTemplate),
+			update: resource.UpdateWith(svc.UpdateConferenceSyncTemplate),
+		},
+		{
+			name:   "device",
+			list:   resource.ListWith(svc.AllDevices),
+			create: resource.CreateWith(svc.CreateDevice),
+			update: resource.UpdateWith(svc.UpdateDevice),
+		},
+		{
+			name:   "diagnostic_graphs",
+			list:   resource.ListWith(svc.AllDiagnosticGraphs),
+			create: resource.CreateWith(svc.CreateDiagnosticGraph),
+			update: resource.UpdateWith(svc.UpdateDiagnosticGraph),
+		},
+		{
+			name:   "dns_server",
+			list:   resource.ListWith(svc.AllDNSServers),
+			create: resource.CreateWith(svc.CreateDNSServer),
+			update: resource.UpdateWith(svc.UpdateDNSServer),
+		},
+		{
+			name:   "end_user",
+			list:   resource.ListWith(svc.AllEndUsers),
+			create: resource.CreateWith(svc.CreateEndUser),
+			update: resource.UpdateWith(svc.UpdateEndUser),
+		},
+		{
+			name:   "event_sink",
+			list:   resource.ListWith(svc.AllEventSinks),
+			create: resource.CreateWith(svc.CreateEventSink),
+			update: resource.UpdateWith(svc.UpdateEventSink),
+		},
+		{
+			name:   "exchange_domain",
+			list:   resource.ListWith(svc.AllExchangeDomains),
+			create: resource.CreateWith(svc.CreateExchangeDomain),
+			update: resource.UpdateWith(svc.UpdateExchangeDomain),
+		},
+		{
+			name:   "external_webapp_host",
+			list:   resource.ListWith(svc.AllExternalWebappHosts),
+			create: resource.CreateWith(svc.CreateExternalWebappHost),
+			update: resource.UpdateWith(svc.UpdateExternalWebappHost),
+		},
+		{
+			name:   "gateway_routing_rule",
+			list:   resource.ListWith(svc.AllGatewayRoutingRules),
+			create: resource.CreateWith(svc.CreateGatewayRoutingRule),
+			update: resource.UpdateWith(svc.UpdateGatewayRoutingRule),
+		},
+		{
+			name:   "gms_access_token",
+			list:   resource.ListWith(svc.AllGMSAccessTokens),
+			create: resource.CreateWith(svc.CreateGMSAccessToken),
+			update: resource.UpdateWith(svc.UpdateGMSAccessToken),
+		},
+		{
+			name:   "google_auth_server",
+			list:   resource.ListWith(svc.AllGoogleAuthServers),
+			create: resource.CreateWith(svc.CreateGoogleAuthServer),
+			update: resource.UpdateWith(svc.UpdateGoogleAuthServer),
+		},
+		{
+			name:   "google_auth_server_domain",
+			list:   resource.ListWith(svc.AllGoogleAuthServerDomains),
+			create: resource.CreateWith(svc.CreateGoogleAuthServerDomain),
+			update: resource.UpdateWith(svc.UpdateGoogleAuthServerDomain),
+		},
+		{
+			name:   "h323_gatekeeper",
+			list:   resource.ListWith(svc.AllH323Gatekeepers),
+			create: resource.CreateWith(svc.CreateH323Gatekeeper),
+			update: resource.UpdateWith(svc.UpdateH323Gatekeeper),
+		},
+		{
+			name:   "http_proxy",
+			list:   resource.ListWith(svc.AllHTTPProxies),
+			create: resource.CreateWith(svc.CreateHTTPProxy),
+			update: resource.UpdateWith(svc.UpdateHTTPProxy),
+		},
+		{
+			name:   "identity_provider",
+			list:   resource.ListWith(svc.AllIdentityProviders),
+			create: resource.CreateWith(svc.CreateIdentityProvider),
+			update: resource.UpdateWith(svc.UpdateIdentityProvider),
+		},
+		{
+			name:   "identity_provider_attribute",
+			list:   resource.ListWith(svc.AllIdentityProviderAttributes),
+			create: resource.CreateWith(svc.CreateIdentityProviderAttribute),
+			update: resource.UpdateWith(svc.UpdateIdentityProviderAttribute),
+		},
+		{
+			name:   "identity_provider_group",
+			list:   resource.ListWith(svc.AllIdentityProviderGroups),
+			create: resource.CreateWith(svc.CreateIdentityProviderGroup),
+			update: resource.UpdateWith(svc.UpdateIdentityProviderGroup),
+		},
+		{
+			name: "ivr_theme",
+			list: resource.ListWith(svc.AllIVRThemes),
+		},
+		{
+			name:   "ldap_role",
+			list:   resource.ListWith(svc.AllLdapRoles),
+			create: resource.CreateWith(svc.CreateLdapRole),
+			update: resource.UpdateWith(svc.UpdateLdapRole),
+		},
+		{
+			name:   "ldap_sync_field",
+			list:   resource.ListWith(svc.AllLdapSyncFields),
+			create: resource.CreateWith(svc.CreateLdapSyncField),
+			update: resource.UpdateWith(svc.UpdateLdapSyncField),
+		},
+		{
+			name:   "ldap_sync_source",
+			list:   resource.ListWith(svc.AllLdapSyncSources),
+			create: resource.CreateWith(svc.CreateLdapSyncSource),
+			update: resource.UpdateWith(svc.UpdateLdapSyncSource),
+		},
+		{
+			name: "licence",
+			list: resource.ListWith(svc.AllLicences),
+		},
+		{
+			name: "licence_request",
+			list: resource.ListWith(svc.AllLicenceRequests),
+		},
+		{
+			name:   "log_level",
+			list:   resource.ListWith(svc.AllLogLevels),
+			create: resource.CreateWith(svc.CreateLogLevel),
+			update: resource.UpdateWith(svc.UpdateLogLevel),
+		},
+		{
+			name: "management_vm",
+			list: resource.ListWith(svc.AllManagementVMs),
+		},
+		{
+			name: "media_library_entry",
+			list: resource.ListWith(svc.AllMediaLibraryEntries),
+		},
+		{
+			name:   "media_library_playlist",
+			list:   resource.ListWith(svc.AllMediaLibraryPlaylists),
+			create: resource.CreateWith(svc.CreateMediaLibraryPlaylist),
+			update: resource.UpdateWith(svc.UpdateMediaLibraryPlaylist),
+		},
+		{
+			name:   "media_library_playlist_entry",
+			list:   resource.ListWith(svc.AllMediaLibraryPlaylistEntries),
+			create: resource.CreateWith(svc.CreateMediaLibraryPlaylistEntry),
+			update: resource.UpdateWith(svc.UpdateMediaLibraryPlaylistEntry),
+		},
+		{
+			name:   "media_processing_server",
+			list:   resource.ListWith(svc.AllMediaProcessingServers),
+			create: resource.CreateWith(svc.CreateMediaProcessingServer),
+			update: resource.UpdateWith(svc.UpdateMediaProcessingServer),
+		},
+		{
+			name:   "mjx_endpoint",
+			list:   resource.ListWith(svc.AllMjxEndpoints),
+			create: resource.CreateWith(svc.CreateMjxEndpoint),
+			update: resource.UpdateWith(svc.UpdateMjxEndpoint),
+		},
+		{
+			name:   "mjx_endpoint_group",
+			list:   resource.ListWith(svc.AllMjxEndpointGroups),
+			create: resource.CreateWith(svc.CreateMjxEndpointGroup),
+			update: resource.UpdateWith(svc.UpdateMjxEndpointGroup),
+		},
+		{
+			name:   "mjx_exchange_autodiscover_url",
+			list:   resource.ListWith(svc.AllMjxExchangeAutodiscoverURLs),
+			create: resource.CreateWith(svc.CreateMjxExchangeAutodiscoverURL),
+			update: resource.UpdateWith(svc.UpdateMjxExchangeAutodiscoverURL),
+		},
+		{
+			name:   "mjx_exchange_deployment",
+			list:   resource.ListWith(svc.AllMjxExchangeDeployments),
+			create: resource.CreateWith(svc.CreateMjxExchangeDeployment),
+			update: resource.UpdateWith(svc.UpdateMjxExchangeDeployment),
+		},
+		{
+			name:   "mjx_google_deployment",
+			list:   resource.ListWith(svc.AllMjxGoogleDeployments),
+			create: resource.CreateWith(svc.CreateMjxGoogleDeployment),
+			update: resource.UpdateWith(svc.UpdateMjxGoogleDeployment),
+		},
+		{
+			name:   "mjx_graph_deployment",
+			list:   resource.ListWith(svc.AllMjxGraphDeployments),
+			create: resource.CreateWith(svc.CreateMjxGraphDeployment),
+			update: resource.UpdateWith(svc.UpdateMjxGraphDeployment),
+		},
+		{
+			name:   "mjx_integration",
+			list:   resource.ListWith(svc.AllMjxIntegrations),
+			create: resource.CreateWith(svc.CreateMjxIntegration),
+			update: resource.UpdateWith(svc.UpdateMjxIntegration),
+		},
+		{
+			name:   "mjx_meeting_processing_rule",
+			list:   resource.ListWith(svc.AllMjxMeetingProcessingRules),
+			create: resource.CreateWith(svc.CreateMjxMeetingProcessingRule),
+			update: resource.UpdateWith(svc.UpdateMjxMeetingProcessingRule),
+		},
+		{
+			name:   "ms_exchange_connector",
+			list:   resource.ListWith(svc.AllMsExchangeConnectors),
+			create: resource.CreateWith(svc.CreateMsExchangeConnector),
+			update: resource.UpdateWith(svc.UpdateMsExchangeConnector),
+		},
+		{
+			name:   "mssip_proxy",
+			list:   resource.ListWith(svc.AllMSSIPProxies),
+			create: resource.CreateWith(svc.CreateMSSIPProxy),
+			update: resource.UpdateWith(svc.UpdateMSSIPProxy),
+		},
+		{
+			name:   "ntp_server",
+			list:   resource.ListWith(svc.AllNTPServers),
+			create: resource.CreateWith(svc.CreateNTPServer),
+			update: resource.UpdateWith(svc.UpdateNTPServer),
+		},
+		{
+			name:   "oauth2_client",
+			list:   resource.ListWith(svc.AllOAuth2Clients),
+			create: resource.CreateWith(svc.CreateOAuth2Client),
+		},
+		{
+			name: "permission",
+			list: resource.ListWith(svc.AllPermissions),
+		},
+		{
+			name:   "pexip_streaming_credential",
+			list:   resource.ListWith(svc.AllPexipStreamingCredentials),
+			create: resource.CreateWith(svc.CreatePexipStreamingCredential),
+			update: resource.UpdateWith(svc.UpdatePexipStreamingCredential),
+		},
+		{
+			name:   "policy_server",
+			list:   resource.ListWith(svc.AllPolicyServers),
+			create: resource.CreateWith(svc.CreatePolicyServer),
+			update: resource.UpdateWith(svc.UpdatePolicyServer),
+		},
+		{
+			name:   "recurring_conference",
+			list:   resource.ListWith(svc.AllRecurringConferences),
+			create: resource.CreateWith(svc.CreateRecurringConference),
+			update: resource.UpdateWith(svc.UpdateRecurringConference),
+		},
+		{
+			name:   "role",
+			list:   resource.ListWith(svc.AllRoles),
+			create: resource.CreateWith(svc.CreateRole),
+			update: resource.UpdateWith(svc.UpdateRole),
+		},
+		{
+			name:   "role_mapping",
+			list:   resource.ListWith(svc.AllRoleMappings),
+			create: resource.CreateWith(svc.CreateRoleMapping),
+			update: resource.UpdateWith(svc.UpdateRoleMapping),
+		},
+		{
+			name:   "scheduled_alias",
+			list:   resource.ListWith(svc.AllScheduledAliases),
+			create: resource.CreateWith(svc.CreateScheduledAlias),
+			update: resource.UpdateWith(svc.UpdateScheduledAlias),
+		},
+		{
+			name:   "scheduled_conference",
+			list:   resource.ListWith(svc.AllScheduledConferences),
+			create: resource.CreateWith(svc.CreateScheduledConference),
+			update: resource.UpdateWith(svc.UpdateScheduledConference),
+		},
+		{
+			name:   "scheduled_scaling",
+			list:   resource.ListWith(svc.AllScheduledScalings),
+			create: resource.CreateWith(svc.CreateScheduledScaling),
+			update: resource.UpdateWith(svc.UpdateScheduledScaling),
+		},
+		{
+			name:   "sip_credential",
+			list:   resource.ListWith(svc.AllSIPCredentials),
+			create: resource.CreateWith(svc.CreateSIPCredential),
+			update: resource.UpdateWith(svc.UpdateSIPCredential),
+		},
+		{
+			name:   "sip_proxy",
+			list:   resource.ListWith(svc.AllSIPProxies),
+			create: resource.CreateWith(svc.CreateSIPProxy),
+			update: resource.UpdateWith(svc.UpdateSIPProxy),
+		},
+		{
+			name:   "smtp_server",
+			list:   resource.ListWith(svc.AllSMTPServers),
+			create: resource.CreateWith(svc.CreateSMTPServer),
+			update: resource.UpdateWith(svc.UpdateSMTPServer),
+		},
+		{
+			name:   "snmp_network_management_system",
+			list:   resource.ListWith(svc.AllSnmpNetworkManagementSystems),
+			create: resource.CreateWith(svc.CreateSnmpNetworkManagementSystem),
+			update: resource.UpdateWith(svc.UpdateSnmpNetworkManagementSystem),
+		},
+		{
+			name:   "software_bundle",
+			list:   resource.ListWith(svc.AllSoftwareBundles),
+			update: resource.UpdateWith(svc.UpdateSoftwareBundle),
+		},
+		{
+			name: "software_bundle_revision",
+			list: resource.ListWith(svc.AllSoftwareBundleRevisions),
+		},
+		{
+			name:   "ssh_authorized_key",
+			list:   resource.ListWith(svc.AllSSHAuthorizedKeys),
+			create: resource.CreateWith(svc.CreateSSHAuthorizedKey),
+			update: resource.UpdateWith(svc.UpdateSSHAuthorizedKey),
+		},
+		{
+			name:   "static_route",
+			list:   resource.ListWith(svc.AllStaticRoutes),
+			create: resource.CreateWith(svc.CreateStaticRoute),
+			update: resource.UpdateWith(svc.UpdateStaticRoute),
+		},
+		{
+			name:   "stun_server",
+			list:   resource.ListWith(svc.AllSTUNServers),
+			create: resource.CreateWith(svc.CreateSTUNServer),
+			update: resource.UpdateWith(svc.UpdateSTUNServer),
+		},
+		{
+			name:   "syslog_server",
+			list:   resource.ListWith(svc.AllSyslogServers),
+			create: resource.CreateWith(svc.CreateSyslogServer),
+			update: resource.UpdateWith(svc.UpdateSyslogServer),
+		},
+		{
+			name: "system_backup",
+			list: resource.ListWith(svc.AllSystemBackups),
+		},
+		{
+			name:   "system_location",
+			list:   resource.ListWith(svc.AllSystemLocations),
+			create: resource.CreateWith(svc.CreateSystemLocation),
+			update: resource.UpdateWith(svc.UpdateSystemLocation),
+		},
+		{
+			name:   "system_tuneable",
+			list:   resource.ListWith(svc.AllSystemTuneables),
+			create: resource.CreateWith(svc.CreateSystemTuneable),
+			update: resource.UpdateWith(svc.UpdateSystemTuneable),
+		},
+		{
+			name:   "teams_proxy",
+			list:   resource.ListWith(svc.AllTeamsProxies),
+			create: resource.CreateWith(svc.CreateTeamsProxy),
+			update: resource.UpdateWith(svc.UpdateTeamsProxy),
+		},
+		{
+			name:   "telehealth_profile",
+			list:   resource.ListWith(svc.AllTelehealthProfiles),
+			create: resource.CreateWith(svc.CreateTelehealthProfile),
+			update: resource.UpdateWith(svc.UpdateTelehealthProfile),
+		},
+		{
+			name:   "tls_certificate",
+			list:   resource.ListWith(svc.AllTLSCertificates),
+			create: resource.CreateWith(svc.CreateTLSCertificate),
+			update: resource.UpdateWith(svc.UpdateTLSCertificate),
+		},
+		{
+			name:   "turn_server",
+			list:   resource.ListWith(svc.AllTURNServers),
+			create: resource.CreateWith(svc.CreateTURNServer),
+			update: resource.UpdateWith(svc.UpdateTURNServer),
+		},
+		{
+			name:   "user_group",
+			list:   resource.ListWith(svc.AllUserGroups),
+			create: resource.CreateWith(svc.CreateUserGroup),
+			update: resource.UpdateWith(svc.UpdateUserGroup),
+		},
+		{
+			name:   "user_group_entity_mapping",
+			list:   resource.ListWith(svc.AllUserGroupEntityMappings),
+			create: resource.CreateWith(svc.CreateUserGroupEntityMapping),
+			update: resource.UpdateWith(svc.UpdateUserGroupEntityMapping),
+		},
+		{
+			name:   "webapp_alias",
+			list:   resource.ListWith(svc.AllWebappAliases),
+			create: resource.CreateWith(svc.CreateWebappAlias),
+			update: resource.UpdateWith(svc.UpdateWebappAlias),
+		},
+		{
+			name: "webapp_branding",
+			list: resource.ListWith(svc.AllWebappBrandings),
+		},
+		{
+			name:   "worker_vm",
+			list:   resource.ListWith(svc.AllWorkerVMs),
+			create: resource.CreateWith(svc.CreateWorkerVM),
+			update: resource.UpdateWith(svc.UpdateWorkerVM),
+		},
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bundle
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pexip/go-infinity-sdk/v41/internal/redact"
+)
+
+// SecretMode controls how secret fields such as passwords and private keys are exported
+type SecretMode string
+
+const (
+	// SecretsStrip removes secret fields from the bundle
+	SecretsStrip SecretMode = "strip"
+	// SecretsEncrypt encrypts secret fields with AES-256-GCM using a key derived from a passphrase
+	SecretsEncrypt SecretMode = "encrypt"
+)
+
+const (
+	encPrefix     = "$enc:"
+	kdfIterations = 600000
+	saltSize      = 16
+)
+
+// ErrPassphrase is returned when a passphrase is missing or cannot decrypt the bundle's secrets
+var ErrPassphrase = errors.New("invalid or missing passphrase")
+
+// secretWriter strips or encrypts secret fields on export
+type secretWriter struct {
+	gcm cipher.AEAD // nil when secrets are stripped
+}
+
+func newSecretWriter(manifest *Manifest, passphrase string) (*secretWriter, error) {
+	switch manifest.Secrets {
+	case SecretsStrip:
+		return &secretWriter{}, nil
+	case SecretsEncrypt:
+		if passphrase == "" {
+			return nil, fmt.Errorf("%w: encrypting secrets requires a passphrase", ErrPassphrase)
+		}
+		manifest.Salt = make([]byte, saltSize)
+		if _, err := rand.Read(manifest.Salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+		gcm, err := newGCM(passphrase, manifest.Salt)
+		if err != nil {
+			return nil, err
+		}
+		return &secretWriter{gcm: gcm}, nil
+	default:
+		return nil, fmt.Errorf("unknown secret mode %q", manifest.Secrets)
+	}
+}
+
+// write strips or encrypts the non-empty secret fields of obj and of any nested objects in place
+func (s *secretWriter) write(obj map[string]interface{}) error {
+	for field, value := range obj {
+		switch v := value.(type) {
+		case string:
+			if v == "" || !redact.IsSecret(field) {
+				continue
+			}
+			if s.gcm == nil {
+				delete(obj, field)
+				continue
+			}
+			nonce := make([]byte, s.gcm.NonceSize())
+			if _, err := rand.Read(nonce); err != nil {
+				return fmt.Errorf("failed to generate nonce: %w", err)
+			}
+			obj[field] = encPrefix + base64.StdEncoding.EncodeToString(s.gcm.Seal(nonce, nonce, []byte(v), nil))
+		case map[string]interface{}:
+			if err := s.write(v); err != nil {
+				return err
+			}
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					if err := s.write(m); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// secretReader decrypts secret fields on import
+type secretReader struct {
+	manifest   *Manifest
+	passphrase string
+	gcm        cipher.AEAD // derived on first use
+}
+
+// read decrypts every encrypted value in value and returns the result
+func (s *secretReader) read(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		data, ok := strings.CutPrefix(v, encPrefix)
+		if !ok {
+			return v, nil
+		}
+		return s.decrypt(data)
+	case map[string]interface{}:
+		for field, item := range v {
+			plain, err := s.read(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", field, err)
+			}
+			v[field] = plain
+		}
+		return v, nil
+	case []interface{}:
+		for i, item := range v {
+			plain, err := s.read(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = plain
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+func (s *secretReader) decrypt(data string) (string, error) {
+	if s.gcm == nil {
+		if s.passphrase == "" {
+			return "", fmt.Errorf("%w: bundle contains encrypted secrets", ErrPassphrase)
+		}
+		gcm, err := newGCM(s.passphrase, s.manifest.Salt)
+		if err != nil {
+			return "", err
+		}
+		s.gcm = gcm
+	}
+	sealed, err := base64.StdEncoding.DecodeString(data)
+	if err != nil || len(sealed) < s.gcm.NonceSize() {
+		return "", fmt.Errorf("%w: malformed encrypted value", ErrInvalidBundle)
+	}
+	nonce, ciphertext := sealed[:s.gcm.NonceSize()], sealed[s.gcm.NonceSize():]
+	plain, err := s.gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to decrypt secret", ErrPassphrase)
+	}
+	return string(plain), nil
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, kdfIterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
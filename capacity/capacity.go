@@ -0,0 +1,344 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package capacity plans conferencing capacity from worker VM and system location status. It computes
+// how many more calls of each type every location can take, taking overflow and transcoding locations
+// and nodes in maintenance mode into account, for checks ahead of large events.
+package capacity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	infinity "github.com/pexip/go-infinity-sdk/v41"
+	"github.com/pexip/go-infinity-sdk/v41/config"
+	"github.com/pexip/go-infinity-sdk/v41/status"
+)
+
+// ErrUnknownLocation is returned when a check names a location that is not in the report
+var ErrUnknownLocation = errors.New("unknown system location")
+
+// CallType is a kind of call that capacity is counted in
+type CallType string
+
+// Call types, matching the max_*_calls fields of worker VM status
+const (
+	HD     CallType = "hd"
+	FullHD CallType = "full_hd"
+	SD     CallType = "sd"
+	Audio  CallType = "audio"
+)
+
+// Calls is a number of calls of each type. As calls of different types share the same media tokens,
+// each field is the number of calls of that type alone that fit, not an amount that can be combined.
+type Calls struct {
+	HD     int `json:"hd"`
+	FullHD int `json:"full_hd"`
+	SD     int `json:"sd"`
+	Audio  int `json:"audio"`
+}
+
+// Get returns the number of calls of type t
+func (c Calls) Get(t CallType) int {
+	switch t {
+	case HD:
+		return c.HD
+	case FullHD:
+		return c.FullHD
+	case SD:
+		return c.SD
+	case Audio:
+		return c.Audio
+	}
+	return 0
+}
+
+func (c Calls) add(o Calls) Calls {
+	return Calls{HD: c.HD + o.HD, FullHD: c.FullHD + o.FullHD, SD: c.SD + o.SD, Audio: c.Audio + o.Audio}
+}
+
+// Node is the capacity of a single conferencing node
+type Node struct {
+	Name              string `json:"name"`
+	Location          string `json:"location"`
+	MaintenanceMode   bool   `json:"maintenance_mode"`
+	MaintenanceReason string `json:"maintenance_reason,omitempty"`
+	MediaLoad         int    `json:"media_load"`
+	MediaTokensUsed   int    `json:"media_tokens_used"`
+	MaxMediaTokens    int    `json:"max_media_tokens"`
+	// Capacity is the number of calls the node can hold when idle
+	Capacity Calls `json:"capacity"`
+	// Headroom is the number of further calls the node can take, zero while in maintenance mode
+	Headroom Calls `json:"headroom"`
+}
+
+// Location is the capacity of a system location
+type Location struct {
+	Name string `json:"name"`
+	// TranscodingLocation is where the media of calls into this location is handled, if not here
+	TranscodingLocation string `json:"transcoding_location,omitempty"`
+	// OverflowLocations are used in order when the media location is full
+	OverflowLocations []string `json:"overflow_locations,omitempty"`
+	// MediaLoad is the media load of the location as reported by Infinity
+	MediaLoad       float64 `json:"media_load"`
+	Nodes           []Node  `json:"nodes"`
+	MediaTokensUsed int     `json:"media_tokens_used"`
+	// MaxMediaTokens counts only nodes that are not in maintenance mode
+	MaxMediaTokens int `json:"max_media_tokens"`
+	// Capacity and Headroom are the totals of the location's nodes
+	Capacity Calls `json:"capacity"`
+	Headroom Calls `json:"headroom"`
+	// Warnings describe conditions that reduce or put the location's capacity at risk
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Report is the capacity of every system location at a point in time
+type Report struct {
+	Time      time.Time  `json:"time"`
+	Locations []Location `json:"locations"`
+}
+
+// Location returns the named location of the report
+func (r *Report) Location(name string) (*Location, bool) {
+	for i := range r.Locations {
+		if r.Locations[i].Name == name {
+			return &r.Locations[i], true
+		}
+	}
+	return nil, false
+}
+
+// Check is the answer to whether a location can take a number of further calls
+type Check struct {
+	Location  string   `json:"location"`
+	CallType  CallType `json:"call_type"`
+	Requested int      `json:"requested"`
+	// MediaLocation is where the calls are hosted: the location itself or its transcoding location
+	MediaLocation string `json:"media_location"`
+	// Local is how many of the calls fit in the media location
+	Local int `json:"local"`
+	// Overflow is how many of the remaining calls fit in each overflow location, in order, counted in the
+	// overflow location's media location. Overflow locations whose media location is already used are left out.
+	Overflow map[string]int `json:"overflow,omitempty"`
+	// Shortfall is how many of the calls do not fit anywhere
+	Shortfall int  `json:"shortfall"`
+	OK        bool `json:"ok"`
+	// Warnings are those of the locations the calls would use
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// CanAbsorb reports whether location can take n more calls of type t, filling its media location first
+// and then its overflow locations. Calls overflowing to a location are hosted in that location's media
+// location, and a media location is counted once even if it is reached several ways. Capacity in
+// transcoding and overflow locations is shared with every location that uses them.
+func (r *Report) CanAbsorb(location string, t CallType, n int) (*Check, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("invalid number of calls: %d", n)
+	}
+	loc, ok := r.Location(location)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLocation, location)
+	}
+	check := &Check{Location: location, CallType: t, Requested: n, MediaLocation: loc.mediaLocation()}
+	check.Warnings = append(check.Warnings, loc.Warnings...)
+
+	remaining := n
+	used := map[string]bool{check.MediaLocation: true}
+	if media, ok := r.Location(check.MediaLocation); ok {
+		check.Local = min(remaining, media.Headroom.Get(t))
+		remaining -= check.Local
+		if media != loc {
+			check.Warnings = append(check.Warnings, media.Warnings...)
+		}
+	} else {
+		check.Warnings = append(check.Warnings, fmt.Sprintf("media location %s has no status", check.MediaLocation))
+	}
+	for _, name := range loc.OverflowLocations {
+		if remaining == 0 {
+			break
+		}
+		overflow, ok := r.Location(name)
+		if !ok {
+			continue
+		}
+		media, ok := r.Location(overflow.mediaLocation())
+		if !ok || used[media.Name] {
+			continue
+		}
+		used[media.Name] = true
+		if check.Overflow == nil {
+			check.Overflow = make(map[string]int)
+		}
+		check.Overflow[name] = min(remaining, media.Headroom.Get(t))
+		remaining -= check.Overflow[name]
+	}
+	check.Shortfall = remaining
+	check.OK = remaining == 0
+	return check, nil
+}
+
+// mediaLocation returns the name of the location that hosts the media of calls into l
+func (l *Location) mediaLocation() string {
+	if l.TranscodingLocation != "" {
+		return l.TranscodingLocation
+	}
+	return l.Name
+}
+
+// Plan reads the status of conferencing nodes and system locations and the system location
+// configuration, and builds a capacity report
+func Plan(ctx context.Context, client *infinity.Client) (*Report, error) {
+	var workers []status.WorkerVM
+	for w, err := range client.Status().AllWorkerVMs(ctx, nil) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list worker VMs: %w", err)
+		}
+		workers = append(workers, w)
+	}
+	var statuses []status.SystemLocation
+	for l, err := range client.Status().AllSystemLocations(ctx, nil) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list system location status: %w", err)
+		}
+		statuses = append(statuses, l)
+	}
+	var locations []config.SystemLocation
+	for l, err := range client.Config().AllSystemLocations(ctx, nil) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list system locations: %w", err)
+		}
+		locations = append(locations, l)
+	}
+	return Build(workers, statuses, locations, time.Now()), nil
+}
+
+// Build builds a capacity report from status and configuration that has already been read
+func Build(workers []status.WorkerVM, statuses []status.SystemLocation, locations []config.SystemLocation, now time.Time) *Report {
+	byName := make(map[string]*Location)
+	get := func(name string) *Location {
+		if loc, ok := byName[name]; ok {
+			return loc
+		}
+		loc := &Location{Name: name}
+		byName[name] = loc
+		return loc
+	}
+
+	for _, l := range locations {
+		loc := get(l.Name)
+		for _, ref := range []*string{l.OverflowLocation1, l.OverflowLocation2} {
+			if ref == nil || *ref == "" {
+				continue
+			}
+			if name, ok := resolve(locations, *ref); ok {
+				loc.OverflowLocations = append(loc.OverflowLocations, name)
+			} else {
+				loc.Warnings = append(loc.Warnings, fmt.Sprintf("overflow location %s does not exist", *ref))
+			}
+		}
+		if l.TranscodingLocation != nil && *l.TranscodingLocation != "" {
+			if name, ok := resolve(locations, *l.TranscodingLocation); ok && name != l.Name {
+				loc.TranscodingLocation = name
+			} else if !ok {
+				loc.Warnings = append(loc.Warnings, fmt.Sprintf("transcoding location %s does not exist", *l.TranscodingLocation))
+			}
+		}
+	}
+	for _, s := range statuses {
+		get(s.Name).MediaLoad = s.MediaLoad
+	}
+
+	for _, w := range workers {
+		node := newNode(w)
+		if node.MaxMediaTokens == 0 {
+			// Proxying edge nodes host no media
+			continue
+		}
+		loc := get(w.SystemLocation)
+		loc.Nodes = append(loc.Nodes, node)
+		loc.MediaTokensUsed += node.MediaTokensUsed
+		if node.MaintenanceMode {
+			warning := fmt.Sprintf("node %s is in maintenance mode", node.Name)
+			if node.MaintenanceReason != "" {
+				warning += ": " + node.MaintenanceReason
+			}
+			loc.Warnings = append(loc.Warnings, warning)
+			continue
+		}
+		loc.MaxMediaTokens += node.MaxMediaTokens
+		loc.Capacity = loc.Capacity.add(node.Capacity)
+		loc.Headroom = loc.Headroom.add(node.Headroom)
+	}
+
+	report := &Report{Time: now}
+	for _, loc := range byName {
+		if len(loc.Nodes) > 0 && loc.MaxMediaTokens == 0 {
+			loc.Warnings = append(loc.Warnings, "every conferencing node is in maintenance mode")
+		} else if len(loc.Nodes) == 0 && loc.TranscodingLocation == "" {
+			loc.Warnings = append(loc.Warnings, "no conferencing nodes")
+		}
+		slices.SortFunc(loc.Nodes, func(a, b Node) int { return strings.Compare(a.Name, b.Name) })
+		report.Locations = append(report.Locations, *loc)
+	}
+	slices.SortFunc(report.Locations, func(a, b Location) int { return strings.Compare(a.Name, b.Name) })
+	return report
+}
+
+// newNode computes the capacity of a node. Infinity reports how many calls of each type an idle node
+// holds and the media tokens it has, so the free tokens are converted to calls in the same proportion.
+func newNode(w status.WorkerVM) Node {
+	node := Node{
+		Name:              w.Name,
+		Location:          w.SystemLocation,
+		MaintenanceMode:   w.MaintenanceMode,
+		MaintenanceReason: w.MaintenanceModeReason,
+		MediaLoad:         w.MediaLoad,
+		MediaTokensUsed:   w.MediaTokensUsed,
+		MaxMediaTokens:    w.MaxMediaTokens,
+		Capacity: Calls{
+			HD:     w.MaxHDCalls,
+			FullHD: w.MaxFullHDCalls,
+			SD:     w.MaxSDCalls,
+			Audio:  w.MaxAudioCalls,
+		},
+	}
+	if node.MaintenanceMode || node.MaxMediaTokens == 0 {
+		return node
+	}
+	free := max(node.MaxMediaTokens-node.MediaTokensUsed, 0)
+	fit := func(max int) int { return max * free / node.MaxMediaTokens }
+	node.Headroom = Calls{
+		HD:     fit(node.Capacity.HD),
+		FullHD: fit(node.Capacity.FullHD),
+		SD:     fit(node.Capacity.SD),
+		Audio:  fit(node.Capacity.Audio),
+	}
+	return node
+}
+
+// resolve returns the name of the location ref refers to. Infinity refers to locations by resource
+// URI, so ref is matched against resource URIs and IDs before names.
+func resolve(locations []config.SystemLocation, ref string) (string, bool) {
+	id, idErr := strconv.Atoi(path.Base(strings.TrimSuffix(ref, "/")))
+	for _, l := range locations {
+		if l.ResourceURI != "" && strings.TrimSuffix(l.ResourceURI, "/") == strings.TrimSuffix(ref, "/") ||
+			idErr == nil && l.ID == id && strings.Contains(ref, "system_location") {
+			return l.Name, true
+		}
+	}
+	for _, l := range locations {
+		if l.Name == ref {
+			return l.Name, true
+		}
+	}
+	return "", false
+}
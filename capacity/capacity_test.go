@@ -0,0 +1,170 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package capacity
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pexip/go-infinity-sdk/v41/config"
+	"github.com/pexip/go-infinity-sdk/v41/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string { return &s }
+
+func worker(name, location string, used int) status.WorkerVM {
+	return status.WorkerVM{
+		Name:            name,
+		SystemLocation:  location,
+		MaxMediaTokens:  100,
+		MediaTokensUsed: used,
+		MaxHDCalls:      20,
+		MaxFullHDCalls:  10,
+		MaxSDCalls:      40,
+		MaxAudioCalls:   200,
+	}
+}
+
+func testReport() *Report {
+	workers := []status.WorkerVM{
+		worker("oslo-1", "Oslo", 50),
+		worker("oslo-2", "Oslo", 100),
+		worker("london-1", "London", 0),
+		{Name: "edge-1", SystemLocation: "Oslo"},
+	}
+	maintenance := worker("london-2", "London", 0)
+	maintenance.MaintenanceMode = true
+	maintenance.MaintenanceModeReason = "patching"
+	workers = append(workers, maintenance)
+
+	locations := []config.SystemLocation{
+		{ID: 1, Name: "Oslo", ResourceURI: "/api/admin/configuration/v1/system_location/1/", OverflowLocation1: strPtr("/api/admin/configuration/v1/system_location/2/")},
+		{ID: 2, Name: "London", ResourceURI: "/api/admin/configuration/v1/system_location/2/"},
+		{ID: 3, Name: "Edge", ResourceURI: "/api/admin/configuration/v1/system_location/3/", TranscodingLocation: strPtr("/api/admin/configuration/v1/system_location/1/")},
+	}
+	statuses := []status.SystemLocation{{Name: "Oslo", MediaLoad: 75}}
+	return Build(workers, statuses, locations, time.Unix(0, 0))
+}
+
+func TestBuild(t *testing.T) {
+	report := testReport()
+	require.Len(t, report.Locations, 3)
+
+	oslo, ok := report.Location("Oslo")
+	require.True(t, ok)
+	assert.Equal(t, []string{"London"}, oslo.OverflowLocations)
+	assert.Equal(t, 75.0, oslo.MediaLoad)
+	assert.Len(t, oslo.Nodes, 2, "nodes without media tokens are not counted")
+	assert.Equal(t, 200, oslo.MaxMediaTokens)
+	assert.Equal(t, 150, oslo.MediaTokensUsed)
+	assert.Equal(t, Calls{HD: 40, FullHD: 20, SD: 80, Audio: 400}, oslo.Capacity)
+	assert.Equal(t, Calls{HD: 10, FullHD: 5, SD: 20, Audio: 100}, oslo.Headroom)
+
+	london, _ := report.Location("London")
+	assert.Equal(t, 100, london.MaxMediaTokens, "nodes in maintenance are not counted")
+	assert.Equal(t, Calls{HD: 20, FullHD: 10, SD: 40, Audio: 200}, london.Headroom)
+	assert.Equal(t, []string{"node london-2 is in maintenance mode: patching"}, london.Warnings)
+
+	edge, _ := report.Location("Edge")
+	assert.Equal(t, "Oslo", edge.TranscodingLocation)
+	assert.Empty(t, edge.Warnings)
+}
+
+func TestBuild_Warnings(t *testing.T) {
+	down := worker("down-1", "Down", 0)
+	down.MaintenanceMode = true
+	locations := []config.SystemLocation{
+		{Name: "Down", OverflowLocation1: strPtr("/api/admin/configuration/v1/system_location/9/")},
+		{Name: "Empty"},
+	}
+	report := Build([]status.WorkerVM{down}, nil, locations, time.Now())
+
+	downLoc, _ := report.Location("Down")
+	assert.Contains(t, downLoc.Warnings, "overflow location /api/admin/configuration/v1/system_location/9/ does not exist")
+	assert.Contains(t, downLoc.Warnings, "every conferencing node is in maintenance mode")
+	empty, _ := report.Location("Empty")
+	assert.Equal(t, []string{"no conferencing nodes"}, empty.Warnings)
+}
+
+func TestReport_CanAbsorb(t *testing.T) {
+	report := testReport()
+
+	tests := []struct {
+		name     string
+		location string
+		callType CallType
+		n        int
+		want     Check
+	}{
+		{
+			name:     "local",
+			location: "Oslo", callType: HD, n: 8,
+			want: Check{Location: "Oslo", CallType: HD, Requested: 8, MediaLocation: "Oslo", Local: 8, OK: true},
+		},
+		{
+			name:     "overflow",
+			location: "Oslo", callType: HD, n: 25,
+			want: Check{Location: "Oslo", CallType: HD, Requested: 25, MediaLocation: "Oslo", Local: 10, Overflow: map[string]int{"London": 15}, OK: true},
+		},
+		{
+			name:     "shortfall",
+			location: "Oslo", callType: FullHD, n: 20,
+			want: Check{Location: "Oslo", CallType: FullHD, Requested: 20, MediaLocation: "Oslo", Local: 5, Overflow: map[string]int{"London": 10}, Shortfall: 5},
+		},
+		{
+			name:     "transcoding location",
+			location: "Edge", callType: Audio, n: 50,
+			want: Check{Location: "Edge", CallType: Audio, Requested: 50, MediaLocation: "Oslo", Local: 50, OK: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := report.CanAbsorb(tt.location, tt.callType, tt.n)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *check)
+		})
+	}
+
+	_, err := report.CanAbsorb("Nowhere", HD, 1)
+	assert.ErrorIs(t, err, ErrUnknownLocation)
+	_, err = report.CanAbsorb("Oslo", HD, -1)
+	assert.EqualError(t, err, "invalid number of calls: -1")
+}
+
+func TestReport_CanAbsorb_SharedMediaLocations(t *testing.T) {
+	uri := func(id int) *string {
+		return strPtr(fmt.Sprintf("/api/admin/configuration/v1/system_location/%d/", id))
+	}
+	workers := []status.WorkerVM{worker("oslo-1", "Oslo", 50), worker("london-1", "London", 0)}
+	locations := []config.SystemLocation{
+		{Name: "Oslo", ResourceURI: *uri(1)},
+		{Name: "London", ResourceURI: *uri(2)},
+		{Name: "Edge", ResourceURI: *uri(3), TranscodingLocation: uri(1), OverflowLocation1: uri(1)},
+		{Name: "Proxy", ResourceURI: *uri(4), TranscodingLocation: uri(2)},
+		{Name: "Branch", ResourceURI: *uri(5), TranscodingLocation: uri(1), OverflowLocation1: uri(4), OverflowLocation2: uri(2)},
+	}
+	report := Build(workers, nil, locations, time.Unix(0, 0))
+
+	// Oslo hosts Edge's media and is its overflow location, but its headroom is counted once
+	check, err := report.CanAbsorb("Edge", HD, 15)
+	require.NoError(t, err)
+	assert.Equal(t, 10, check.Local)
+	assert.Nil(t, check.Overflow)
+	assert.Equal(t, 5, check.Shortfall)
+
+	// Proxy has no nodes of its own, so calls overflowing to it are hosted in London, which is not counted again
+	check, err = report.CanAbsorb("Branch", HD, 40)
+	require.NoError(t, err)
+	assert.Equal(t, "Oslo", check.MediaLocation)
+	assert.Equal(t, 10, check.Local)
+	assert.Equal(t, map[string]int{"Proxy": 20}, check.Overflow)
+	assert.Equal(t, 10, check.Shortfall)
+	assert.False(t, check.OK)
+}
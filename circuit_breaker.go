@@ -0,0 +1,241 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package infinity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of the circuit breaker
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // Requests are sent normally
+	CircuitOpen                         // Requests fail fast with ErrCircuitOpen
+	CircuitHalfOpen                     // A single probe request is sent to test recovery
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerConfig defines when the circuit breaker trips and recovers. Transport errors and
+// 5xx responses count as failures; other responses count as successes.
+type CircuitBreakerConfig struct {
+	ConsecutiveFailures int           // Trip after this many consecutive failures (0 = disabled)
+	FailureRatio        float64       // Trip when this ratio of requests in the window fail (0 = disabled)
+	MinRequests         int           // Minimum requests in the window before FailureRatio applies
+	Window              time.Duration // Interval after which the failure ratio counts are reset (0 = never)
+	OpenTimeout         time.Duration // How long to fail fast before probing the management node again
+
+	// OnStateChange is called after every state change, for example to raise an alert
+	OnStateChange func(from, to CircuitState)
+}
+
+// DefaultCircuitBreakerConfig returns a circuit breaker that trips after 5 consecutive failures
+// and probes again after 30 seconds
+func DefaultCircuitBreakerConfig() *CircuitBreakerConfig {
+	return &CircuitBreakerConfig{
+		ConsecutiveFailures: 5,
+		OpenTimeout:         30 * time.Second,
+	}
+}
+
+// circuitOutcome is the result of a request as seen by the circuit breaker
+type circuitOutcome int
+
+const (
+	outcomeSuccess circuitOutcome = iota
+	outcomeFailure
+	outcomeIgnored // the caller gave up, which says nothing about the management node
+)
+
+type circuitBreaker struct {
+	config CircuitBreakerConfig
+	now    func() time.Time
+
+	mu          sync.Mutex
+	state       CircuitState
+	consecutive int
+	requests    int
+	failures    int
+	windowStart time.Time
+	openedAt    time.Time
+	probing     bool
+	// generation is incremented on every state change, so that outcomes of requests let through
+	// in an earlier state are not counted in the current one
+	generation uint64
+}
+
+func newCircuitBreaker(config *CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{config: *config, now: time.Now, windowStart: time.Now()}
+}
+
+// allow reports whether a request may be sent. If it may, done must be called with its outcome.
+func (b *circuitBreaker) allow() (done func(circuitOutcome), err error) {
+	b.mu.Lock()
+	var changes [][2]CircuitState
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.config.OpenTimeout {
+		changes = append(changes, b.setState(CircuitHalfOpen))
+	}
+	probe := false
+	switch {
+	case b.state == CircuitOpen, b.state == CircuitHalfOpen && b.probing:
+		err = ErrCircuitOpen
+	case b.state == CircuitHalfOpen:
+		b.probing = true
+		probe = true
+	}
+	generation := b.generation
+	b.mu.Unlock()
+	b.notify(changes)
+
+	if err != nil {
+		return nil, err
+	}
+	return func(outcome circuitOutcome) { b.record(generation, probe, outcome) }, nil
+}
+
+// record counts the outcome of a request let through in the given generation. Outcomes from an
+// earlier generation are ignored, and only the probe decides the half-open state.
+func (b *circuitBreaker) record(generation uint64, probe bool, outcome circuitOutcome) {
+	b.mu.Lock()
+	var changes [][2]CircuitState
+	switch {
+	case generation != b.generation:
+	case probe:
+		b.probing = false
+		switch outcome {
+		case outcomeSuccess:
+			changes = append(changes, b.setState(CircuitClosed))
+		case outcomeFailure:
+			changes = append(changes, b.setState(CircuitOpen))
+		}
+	case b.state == CircuitClosed:
+		if outcome != outcomeIgnored && b.tripped(outcome == outcomeFailure) {
+			changes = append(changes, b.setState(CircuitOpen))
+		}
+	}
+	b.mu.Unlock()
+	b.notify(changes)
+}
+
+// tripped counts a request in the closed state and reports whether the breaker should open
+func (b *circuitBreaker) tripped(failed bool) bool {
+	now := b.now()
+	if b.config.Window > 0 && now.Sub(b.windowStart) >= b.config.Window {
+		b.requests, b.failures, b.windowStart = 0, 0, now
+	}
+	b.requests++
+	if !failed {
+		b.consecutive = 0
+		return false
+	}
+	b.consecutive++
+	b.failures++
+
+	if b.config.ConsecutiveFailures > 0 && b.consecutive >= b.config.ConsecutiveFailures {
+		return true
+	}
+	return b.config.FailureRatio > 0 && b.requests >= b.config.MinRequests &&
+		float64(b.failures)/float64(b.requests) >= b.config.FailureRatio
+}
+
+// setState changes the state and resets the counters of the new state. The caller must hold b.mu.
+func (b *circuitBreaker) setState(state CircuitState) [2]CircuitState {
+	change := [2]CircuitState{b.state, state}
+	b.state = state
+	b.generation++
+	switch state {
+	case CircuitOpen:
+		b.openedAt = b.now()
+	case CircuitClosed:
+		b.consecutive, b.requests, b.failures, b.windowStart = 0, 0, 0, b.now()
+	}
+	return change
+}
+
+// reset closes the breaker, for example after the management node has passed a health check
+func (b *circuitBreaker) reset() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	var changes [][2]CircuitState
+	if b.state != CircuitClosed {
+		b.probing = false
+		changes = append(changes, b.setState(CircuitClosed))
+	}
+	b.mu.Unlock()
+	b.notify(changes)
+}
+
+// currentState returns the state of the breaker, which is closed if b is nil
+func (b *circuitBreaker) currentState() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *circuitBreaker) notify(changes [][2]CircuitState) {
+	if b.config.OnStateChange == nil {
+		return
+	}
+	for _, change := range changes {
+		b.config.OnStateChange(change[0], change[1])
+	}
+}
+
+// circuitOutcomeOf classifies the result of an attempt
+func circuitOutcomeOf(resp *Response, err error) circuitOutcome {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return outcomeIgnored
+	case err != nil, resp.statusCode() >= 500:
+		return outcomeFailure
+	default:
+		return outcomeSuccess
+	}
+}
+
+// CircuitState returns the state of the circuit breaker, which is always closed if none is configured.
+// With WithBaseURLs each node has its own breaker, and this is the least restrictive of their states,
+// as requests go to any node whose breaker lets them through; Nodes reports the state of each.
+func (c *Client) CircuitState() CircuitState {
+	if c.nodes == nil {
+		return c.breaker.currentState()
+	}
+	state := CircuitOpen
+	for _, n := range c.nodes.nodes {
+		switch n.breaker.currentState() {
+		case CircuitClosed:
+			return CircuitClosed
+		case CircuitHalfOpen:
+			state = CircuitHalfOpen
+		}
+	}
+	return state
+}
@@ -0,0 +1,159 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package infinity
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBreaker returns a circuit breaker with a fake clock and a log of state changes
+func testBreaker(config *CircuitBreakerConfig) (*circuitBreaker, *time.Time, *[]string) {
+	now := time.Unix(0, 0)
+	var changes []string
+	config.OnStateChange = func(from, to CircuitState) {
+		changes = append(changes, from.String()+"->"+to.String())
+	}
+	b := newCircuitBreaker(config)
+	b.now = func() time.Time { return now }
+	b.windowStart = now
+	return b, &now, &changes
+}
+
+func call(t *testing.T, b *circuitBreaker, outcome circuitOutcome) {
+	t.Helper()
+	done, err := b.allow()
+	require.NoError(t, err)
+	done(outcome)
+}
+
+func TestCircuitBreaker_ConsecutiveFailures(t *testing.T) {
+	b, now, changes := testBreaker(&CircuitBreakerConfig{ConsecutiveFailures: 3, OpenTimeout: time.Minute})
+
+	call(t, b, outcomeFailure)
+	call(t, b, outcomeFailure)
+	call(t, b, outcomeSuccess)
+	call(t, b, outcomeFailure)
+	call(t, b, outcomeFailure)
+	assert.Equal(t, CircuitClosed, b.state)
+
+	call(t, b, outcomeFailure)
+	assert.Equal(t, CircuitOpen, b.state)
+	_, err := b.allow()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	// Only a single probe is sent once the open timeout has passed
+	*now = now.Add(time.Minute)
+	probe, err := b.allow()
+	require.NoError(t, err)
+	_, err = b.allow()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	probe(outcomeSuccess)
+	assert.Equal(t, CircuitClosed, b.state)
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, *changes)
+}
+
+func TestCircuitBreaker_ProbeFailure(t *testing.T) {
+	b, now, changes := testBreaker(&CircuitBreakerConfig{ConsecutiveFailures: 1, OpenTimeout: time.Minute})
+
+	call(t, b, outcomeFailure)
+	*now = now.Add(time.Minute)
+	call(t, b, outcomeFailure)
+
+	assert.Equal(t, CircuitOpen, b.state)
+	_, err := b.allow()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->open"}, *changes)
+}
+
+func TestCircuitBreaker_StaleOutcome(t *testing.T) {
+	b, now, changes := testBreaker(&CircuitBreakerConfig{ConsecutiveFailures: 1, OpenTimeout: time.Minute})
+
+	slow, err := b.allow()
+	require.NoError(t, err)
+	call(t, b, outcomeFailure)
+	*now = now.Add(time.Minute)
+	probe, err := b.allow()
+	require.NoError(t, err)
+
+	// A request let through while closed finishes during the probe and must not decide the half-open state
+	slow(outcomeSuccess)
+	assert.Equal(t, CircuitHalfOpen, b.state)
+	_, err = b.allow()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	probe(outcomeFailure)
+	assert.Equal(t, CircuitOpen, b.state)
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->open"}, *changes)
+}
+
+func TestCircuitBreaker_FailureRatio(t *testing.T) {
+	b, now, _ := testBreaker(&CircuitBreakerConfig{FailureRatio: 0.5, MinRequests: 4, Window: time.Minute, OpenTimeout: time.Minute})
+
+	call(t, b, outcomeFailure)
+	call(t, b, outcomeSuccess)
+	call(t, b, outcomeFailure)
+	assert.Equal(t, CircuitClosed, b.state, "below MinRequests")
+
+	// The window resets the counts
+	*now = now.Add(time.Minute)
+	call(t, b, outcomeSuccess)
+	call(t, b, outcomeSuccess)
+	call(t, b, outcomeFailure)
+	call(t, b, outcomeIgnored)
+	assert.Equal(t, CircuitClosed, b.state)
+
+	call(t, b, outcomeFailure)
+	assert.Equal(t, CircuitOpen, b.state)
+}
+
+func TestClient_WithCircuitBreaker(t *testing.T) {
+	var calls int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := New(
+		WithBaseURL(server.URL),
+		WithNoRetries(),
+		WithCircuitBreaker(&CircuitBreakerConfig{ConsecutiveFailures: 2, OpenTimeout: time.Minute}),
+	)
+	require.NoError(t, err)
+
+	for range 2 {
+		err = client.GetJSON(t.Context(), "status/v1/worker_vm/", nil, nil)
+		assert.ErrorContains(t, err, "API error 503")
+	}
+	assert.Equal(t, CircuitOpen, client.CircuitState())
+
+	err = client.GetJSON(t.Context(), "status/v1/worker_vm/", nil, nil)
+
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.EqualError(t, err, "GET status/v1/worker_vm/: circuit breaker is open")
+	assert.Equal(t, int64(2), atomic.LoadInt64(&calls))
+}
+
+func TestWithCircuitBreaker_Invalid(t *testing.T) {
+	_, err := New(WithCircuitBreaker(nil))
+	assert.EqualError(t, err, "circuit breaker config cannot be nil")
+
+	_, err = New(WithCircuitBreaker(&CircuitBreakerConfig{OpenTimeout: time.Second}))
+	assert.EqualError(t, err, "circuit breaker requires ConsecutiveFailures or FailureRatio")
+
+	client, err := New(WithCircuitBreaker(DefaultCircuitBreakerConfig()))
+	require.NoError(t, err)
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+}
@@ -16,6 +16,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -28,6 +29,8 @@ import (
 	"github.com/pexip/go-infinity-sdk/v41/history"
 	"github.com/pexip/go-infinity-sdk/v41/status"
 	"github.com/pexip/go-infinity-sdk/v41/types"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -44,10 +47,22 @@ const (
 // Client represents a Pexip Infinity Management API client
 type Client struct {
 	baseURL     *url.URL
+	nodes       *nodeSet // set by WithBaseURLs
 	httpClient  *http.Client
 	auth        auth.Authenticator
 	retryConfig *RetryConfig
+	retryPolicy RetryPolicy
+	limiter     *requestLimiter
+	breaker     *circuitBreaker
 	userAgent   string
+	logger      *slog.Logger
+	logBodies   bool
+
+	interceptors   []Interceptor
+	handler        Handler // interceptors wrapped around doRequest
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	telemetry      *telemetry
 
 	// API services
 	config  *config.Service
@@ -77,6 +92,22 @@ func New(options ...ClientOption) (*Client, error) {
 		}
 	}
 
+	// Each management node has its own circuit breaker, so that a failed node does not block the others
+	if c.breaker != nil && c.nodes != nil {
+		for _, n := range c.nodes.nodes {
+			n.breaker = newCircuitBreaker(&c.breaker.config)
+		}
+		c.breaker = nil
+	}
+
+	c.handler = chain(c.interceptors, c.doRequest)
+	if c.tracerProvider != nil || c.meterProvider != nil {
+		if c.telemetry, err = newTelemetry(c.tracerProvider, c.meterProvider); err != nil {
+			return nil, err
+		}
+		c.handler = c.telemetry.intercept(c.handler)
+	}
+
 	// Initialize API services
 	c.config = config.New(c)
 	c.status = status.New(c)
@@ -100,15 +131,26 @@ type Response struct {
 	StatusCode int
 	Body       []byte
 	Headers    http.Header
+	ServedBy   string // base URL of the management node that served the request
+}
+
+func (r *Response) statusCode() int {
+	if r == nil {
+		return 0
+	}
+	return r.StatusCode
 }
 
-// DoRequest performs an HTTP request to the Infinity API with retry logic
+// DoRequest performs an HTTP request to the Infinity API through the interceptor chain, with retry logic
 func (c *Client) DoRequest(ctx context.Context, req *Request) (*Response, error) {
-	fullURL := c.baseURL.JoinPath(APIPrefix, strings.TrimPrefix(req.Endpoint, "/"))
-	if req.QueryParams != nil {
-		fullURL.RawQuery = req.QueryParams.Encode()
+	if c.handler == nil {
+		return c.doRequest(ctx, req)
 	}
+	return c.handler(ctx, req)
+}
 
+// doRequest sends the request, retrying failed attempts as the retry policy allows
+func (c *Client) doRequest(ctx context.Context, req *Request) (*Response, error) {
 	// Pre-marshal request body once for all retries
 	var err error
 	var bodyReader io.Reader
@@ -131,12 +173,37 @@ func (c *Client) DoRequest(ctx context.Context, req *Request) (*Response, error)
 		}
 	}
 
+	// Request bodies that cannot be rewound are sent once. Seekable bodies such as files are rewound to
+	// where they started and hidden from the transport, which would otherwise close them after one attempt.
+	seeker, _ := bodyReader.(io.Seeker)
+	var bodyStart int64
+	if seeker != nil {
+		if bodyStart, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			seeker = nil
+		} else {
+			bodyReader = io.NopCloser(bodyReader)
+		}
+	}
+	replayable := bodyReader == nil || bodyBytes != nil || seeker != nil
+
+	policy := c.retryPolicy
+	if policy == nil {
+		policy = c.retryConfig
+	}
+	// Health checks pinned to a node are sent once and bypass its circuit breaker
+	pinned, _ := ctx.Value(pinnedNodeKey{}).(*node)
+	if pinned != nil {
+		policy = &RetryConfig{}
+	}
+
 	// Perform the request with retry logic
-	var lastError error
-	var lastResponse *Response
+	var retries []RetryAttempt
 	var httpReq *http.Request
 	var resp *http.Response
-	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
+	var reauthenticated bool
+	tried := make(map[*node]bool)
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
 		// Check context cancellation before each attempt
 		select {
 		case <-ctx.Done():
@@ -144,6 +211,34 @@ func (c *Client) DoRequest(ctx context.Context, req *Request) (*Response, error)
 		default:
 		}
 
+		// Rewind the request body for retries
+		if attempt > 1 {
+			if bodyBytes != nil {
+				bodyReader = bytes.NewReader(bodyBytes)
+			} else if seeker != nil {
+				if _, err = seeker.Seek(bodyStart, io.SeekStart); err != nil {
+					return nil, fmt.Errorf("failed to rewind request body: %w", err)
+				}
+			}
+		}
+
+		// Route the attempt to a healthy management node
+		baseURL := c.baseURL
+		var current *node
+		if c.nodes != nil {
+			if current = pinned; current == nil {
+				if current = c.nodes.pick(tried); current == nil {
+					clear(tried)
+					current = c.nodes.pick(tried)
+				}
+			}
+			baseURL = current.url
+		}
+		fullURL := baseURL.JoinPath(APIPrefix, strings.TrimPrefix(req.Endpoint, "/"))
+		if req.QueryParams != nil {
+			fullURL.RawQuery = req.QueryParams.Encode()
+		}
+
 		if httpReq, err = http.NewRequestWithContext(ctx, req.Method, fullURL.String(), bodyReader); err != nil {
 			return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 		}
@@ -173,64 +268,142 @@ func (c *Client) DoRequest(ctx context.Context, req *Request) (*Response, error)
 			}
 		}
 
-		// Perform the HTTP request
-		if resp, err = c.httpClient.Do(httpReq); err != nil {
-			lastError = err
-
-			// Check if this error should be retried
-			if attempt < c.retryConfig.MaxRetries && c.retryConfig.IsRetriable(0, err) {
-				if !c.sleepWithContext(ctx, c.retryConfig.CalculateBackoff(attempt+1)) {
-					return nil, ctx.Err()
+		// Fail fast while the management node is known to be unhealthy, or try the next node
+		record := func(circuitOutcome) {}
+		breaker := c.breaker
+		if current != nil && pinned == nil {
+			breaker = current.breaker
+		}
+		if breaker != nil {
+			if record, err = breaker.allow(); err != nil {
+				if current != nil {
+					tried[current] = true
+					if c.nodes.pick(tried) != nil {
+						attempt-- // no attempt was made
+						continue
+					}
 				}
-				continue
+				return nil, fmt.Errorf("%s %s: %w", req.Method, req.Endpoint, err)
 			}
-			// No more retries or error is not retriable
-			return nil, fmt.Errorf("failed to perform HTTP request after %d attempts: %w", attempt+1, err)
 		}
 
-		// Read response body
-		var respBody []byte
-		respBody, err = io.ReadAll(resp.Body)
-		resp.Body.Close() // Always close the body
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response body: %w", err)
+		// Wait for the client-side rate and concurrency limits
+		release := func() {}
+		if c.limiter != nil {
+			if release, err = c.limiter.acquire(ctx, categoryOf(req.Endpoint)); err != nil {
+				record(outcomeIgnored)
+				return nil, err
+			}
 		}
 
-		response := &Response{
-			StatusCode: resp.StatusCode,
-			Body:       respBody,
-			Headers:    resp.Header,
-		}
+		// Perform the HTTP request
+		c.logAttempt(ctx, req, attempt, httpReq.Header, bodyBytes)
+		var response *Response
+		var endAttempt func(*Response, error)
+		httpReq, endAttempt = c.telemetry.startAttempt(httpReq, attempt)
+		attemptStart := time.Now()
+		resp, err = c.httpClient.Do(httpReq)
+		if err != nil {
+			release()
+			record(circuitOutcomeOf(nil, err))
+			endAttempt(nil, err)
+			c.logResponse(ctx, req, attempt, nil, err, time.Since(attemptStart))
+
+			// Fail over to another management node straight away if the request never left the client.
+			// Any other failure may have reached the node, so the request is only sent again, to the next
+			// node, if the retry policy allows it.
+			if current != nil && ctx.Err() == nil {
+				current.markDown(c.nodes.now(), c.nodes.cooldown, err)
+				tried[current] = true
+				if pinned == nil && replayable && notSent(err) && c.nodes.pick(tried) != nil {
+					retries = append(retries, RetryAttempt{Attempt: attempt, Error: err.Error()})
+					c.logRetry(ctx, req, attempt, nil, err, 0)
+					continue
+				}
+			}
+		} else {
+			// Read response body
+			var respBody []byte
+			respBody, err = io.ReadAll(resp.Body)
+			resp.Body.Close() // Always close the body
+			release()
+			if err != nil {
+				record(circuitOutcomeOf(nil, err))
+				endAttempt(nil, err)
+				return nil, fmt.Errorf("failed to read response body: %w", err)
+			}
 
-		// Check if we should retry based on status code
-		if resp.StatusCode >= 400 {
-			lastResponse = response
+			response = &Response{
+				StatusCode: resp.StatusCode,
+				Body:       respBody,
+				Headers:    resp.Header,
+				ServedBy:   baseURL.String(),
+			}
+			if current != nil && resp.StatusCode < 500 {
+				current.markUp(c.nodes.now())
+			}
+			record(circuitOutcomeOf(response, nil))
+			endAttempt(response, nil)
+			c.logResponse(ctx, req, attempt, response, nil, time.Since(attemptStart))
+
+			// Success - return the response
+			if resp.StatusCode < 400 {
+				c.logOutcome(ctx, req, attempt, response, nil, time.Since(start))
+				return response, nil
+			}
 
-			// Check if this status code should be retried
-			if attempt < c.retryConfig.MaxRetries && c.retryConfig.IsRetriable(resp.StatusCode, nil) {
-				if !c.sleepWithContext(ctx, c.retryConfig.CalculateBackoff(attempt+1)) {
-					return nil, ctx.Err()
+			// Refresh expired credentials and send the request once more
+			if reauth, ok := c.auth.(auth.Reauthenticator); ok && !reauthenticated && replayable {
+				var again bool
+				if again, err = reauth.Reauthenticate(ctx, resp); err != nil {
+					err = fmt.Errorf("failed to reauthenticate request: %w", err)
+					c.logOutcome(ctx, req, attempt, response, err, time.Since(start))
+					return nil, err
+				}
+				if again {
+					reauthenticated = true
+					continue
 				}
-				continue
 			}
-
-			// No more retries or status code is not retriable - return error
-			return response, c.handleAPIError(response)
 		}
 
-		// Success - return the response
-		return response, nil
-	}
+		// Ask the retry policy whether and when to try again
+		backoff, retry := policy.Next(&RetryState{
+			Request:  req,
+			Attempt:  attempt,
+			Response: response,
+			Err:      err,
+			Elapsed:  time.Since(start),
+		})
+		if !retry || !replayable {
+			if err != nil {
+				err = fmt.Errorf("failed to perform HTTP request after %d attempts: %w", attempt, err)
+				c.logOutcome(ctx, req, attempt, nil, err, time.Since(start))
+				return nil, err
+			}
+			apiErr := c.handleAPIError(response)
+			apiErr.Method = req.Method
+			apiErr.Endpoint = req.Endpoint
+			apiErr.Attempts = attempt
+			apiErr.Retries = retries
+			c.logOutcome(ctx, req, attempt, response, apiErr, time.Since(start))
+			return response, apiErr
+		}
 
-	// This should not be reached, but handle it just in case
-	if lastResponse != nil {
-		return lastResponse, c.handleAPIError(lastResponse)
+		history := RetryAttempt{Attempt: attempt, StatusCode: response.statusCode(), Backoff: backoff}
+		if err != nil {
+			history.Error = err.Error()
+		}
+		retries = append(retries, history)
+		c.logRetry(ctx, req, attempt, response, err, backoff)
+		if !c.sleepWithContext(ctx, backoff) {
+			return nil, ctx.Err()
+		}
 	}
-	return nil, fmt.Errorf("request failed after %d attempts: %w", c.retryConfig.MaxRetries+1, lastError)
 }
 
 // handleAPIError processes API error responses
-func (c *Client) handleAPIError(resp *Response) error {
+func (c *Client) handleAPIError(resp *Response) *APIError {
 	details := ""
 	if len(resp.Body) > 0 {
 		details = string(resp.Body)
@@ -9,6 +9,9 @@ package infinity
 import (
 	"fmt"
 	"github.com/pexip/go-infinity-sdk/v41/auth"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
 	"net/http"
 	"net/url"
 )
@@ -24,6 +27,28 @@ func WithBaseURL(baseURL string) ClientOption {
 			return fmt.Errorf("failed to parse base URL: %w", err)
 		}
 		c.baseURL = u
+		c.nodes = nil
+		return nil
+	}
+}
+
+// WithBaseURLs sets the base URLs of several management node addresses, such as a primary and a standby.
+// Requests go to the first healthy node and fail over to the next one on connection errors.
+func WithBaseURLs(baseURLs ...string) ClientOption {
+	return func(c *Client) error {
+		if len(baseURLs) == 0 {
+			return fmt.Errorf("at least one base URL is required")
+		}
+		urls := make([]*url.URL, 0, len(baseURLs))
+		for _, baseURL := range baseURLs {
+			u, err := url.Parse(baseURL)
+			if err != nil {
+				return fmt.Errorf("failed to parse base URL: %w", err)
+			}
+			urls = append(urls, u)
+		}
+		c.baseURL = urls[0]
+		c.nodes = newNodeSet(urls)
 		return nil
 	}
 }
@@ -80,6 +105,29 @@ func WithTokenAuth(token string) ClientOption {
 	}
 }
 
+// WithBasicAuthProvider sets basic authentication with the current username and password of provider,
+// so credentials can be rotated without rebuilding the client
+func WithBasicAuthProvider(provider auth.CredentialProvider) ClientOption {
+	return func(c *Client) error {
+		if provider == nil {
+			return fmt.Errorf("credential provider cannot be nil")
+		}
+		c.auth = auth.NewBasicAuthWithProvider(provider)
+		return nil
+	}
+}
+
+// WithTokenAuthProvider sets token-based authentication with the current token of provider
+func WithTokenAuthProvider(provider auth.CredentialProvider) ClientOption {
+	return func(c *Client) error {
+		if provider == nil {
+			return fmt.Errorf("credential provider cannot be nil")
+		}
+		c.auth = auth.NewTokenAuthWithProvider(provider)
+		return nil
+	}
+}
+
 // WithRetryConfig sets the retry configuration for the client
 func WithRetryConfig(config *RetryConfig) ClientOption {
 	return func(c *Client) error {
@@ -91,6 +139,17 @@ func WithRetryConfig(config *RetryConfig) ClientOption {
 	}
 }
 
+// WithRetryPolicy sets a custom retry policy, which replaces the retry configuration
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		if policy == nil {
+			return fmt.Errorf("retry policy cannot be nil")
+		}
+		c.retryPolicy = policy
+		return nil
+	}
+}
+
 // WithMaxRetries sets the maximum number of retries (convenience function)
 func WithMaxRetries(maxRetries int) ClientOption {
 	return func(c *Client) error {
@@ -117,3 +176,121 @@ func WithUserAgent(userAgent string) ClientOption {
 		return nil
 	}
 }
+
+// WithRateLimit limits the client to rps requests per second with bursts of up to burst requests.
+// Every attempt, including retries, takes a token; callers wait for one or until their context is done.
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(c *Client) error {
+		if rps <= 0 || burst < 1 {
+			return fmt.Errorf("rate limit must be positive and burst at least 1")
+		}
+		c.requestLimiter().global.bucket = newTokenBucket(rps, burst)
+		return nil
+	}
+}
+
+// WithCategoryRateLimit limits requests to one API category, in addition to any client-wide rate limit
+func WithCategoryRateLimit(category APICategory, rps float64, burst int) ClientOption {
+	return func(c *Client) error {
+		if rps <= 0 || burst < 1 {
+			return fmt.Errorf("rate limit must be positive and burst at least 1")
+		}
+		c.requestLimiter().category(category).bucket = newTokenBucket(rps, burst)
+		return nil
+	}
+}
+
+// WithMaxConcurrentRequests limits the number of requests the client has in flight at once
+func WithMaxConcurrentRequests(n int) ClientOption {
+	return func(c *Client) error {
+		if n < 1 {
+			return fmt.Errorf("max concurrent requests must be at least 1")
+		}
+		c.requestLimiter().global.sem = make(chan struct{}, n)
+		return nil
+	}
+}
+
+// WithCategoryMaxConcurrentRequests limits the number of requests to one API category in flight at once,
+// in addition to any client-wide concurrency limit
+func WithCategoryMaxConcurrentRequests(category APICategory, n int) ClientOption {
+	return func(c *Client) error {
+		if n < 1 {
+			return fmt.Errorf("max concurrent requests must be at least 1")
+		}
+		c.requestLimiter().category(category).sem = make(chan struct{}, n)
+		return nil
+	}
+}
+
+// WithCircuitBreaker stops sending requests to a management node that keeps failing. While the
+// breaker is open, requests fail fast with ErrCircuitOpen instead of waiting through retries. With
+// WithBaseURLs each node has its own breaker, and requests go to the next node whose breaker is closed.
+func WithCircuitBreaker(config *CircuitBreakerConfig) ClientOption {
+	return func(c *Client) error {
+		if config == nil {
+			return fmt.Errorf("circuit breaker config cannot be nil")
+		}
+		if config.ConsecutiveFailures <= 0 && config.FailureRatio <= 0 {
+			return fmt.Errorf("circuit breaker requires ConsecutiveFailures or FailureRatio")
+		}
+		c.breaker = newCircuitBreaker(config)
+		return nil
+	}
+}
+
+// WithInterceptor adds an interceptor around every request. Interceptors run in the order they are
+// added, each around the ones after it, and see the request before it is encoded and retried.
+func WithInterceptor(interceptor Interceptor) ClientOption {
+	return func(c *Client) error {
+		if interceptor == nil {
+			return fmt.Errorf("interceptor cannot be nil")
+		}
+		c.interceptors = append(c.interceptors, interceptor)
+		return nil
+	}
+}
+
+// WithTracerProvider enables OpenTelemetry tracing. Each SDK call gets a span with a child span per
+// HTTP attempt, and the trace context is propagated with the global propagator.
+func WithTracerProvider(provider trace.TracerProvider) ClientOption {
+	return func(c *Client) error {
+		if provider == nil {
+			return fmt.Errorf("tracer provider cannot be nil")
+		}
+		c.tracerProvider = provider
+		return nil
+	}
+}
+
+// WithMeterProvider enables OpenTelemetry metrics for call latency, errors and retries
+func WithMeterProvider(provider metric.MeterProvider) ClientOption {
+	return func(c *Client) error {
+		if provider == nil {
+			return fmt.Errorf("meter provider cannot be nil")
+		}
+		c.meterProvider = provider
+		return nil
+	}
+}
+
+// WithLogger logs each attempt and response at debug level, retries at warn level and failed requests
+// at warn or error level. Credentials and secret fields are redacted.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) error {
+		if logger == nil {
+			return fmt.Errorf("logger cannot be nil")
+		}
+		c.logger = logger
+		return nil
+	}
+}
+
+// WithBodyLogging adds request and response headers and bodies to the debug logs of WithLogger,
+// with credentials and secret fields redacted
+func WithBodyLogging() ClientOption {
+	return func(c *Client) error {
+		c.logBodies = true
+		return nil
+	}
+}
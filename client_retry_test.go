@@ -9,9 +9,12 @@ package infinity
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -210,6 +213,36 @@ func TestClient_RetryOnServerError(t *testing.T) {
 	assert.Equal(t, 3, attemptCount) // Should have tried 3 times
 }
 
+func TestClient_RetryFileBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "body.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"name": "sales"}`), 0o600))
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	client, err := New(
+		WithBaseURL(server.URL),
+		WithRetryConfig(&RetryConfig{MaxRetries: 1, BackoffMin: time.Millisecond, BackoffMax: time.Millisecond, Multiplier: 1}),
+	)
+	require.NoError(t, err)
+
+	_, err = client.DoRequest(t.Context(), &Request{Method: http.MethodPut, Endpoint: "configuration/v1/conference/1/", Body: f})
+	require.NoError(t, err)
+	assert.Equal(t, []string{`{"name": "sales"}`, `{"name": "sales"}`}, bodies)
+}
+
 func TestClient_RetryOnNetworkError(t *testing.T) {
 	// Create a server that immediately closes connections to simulate network errors
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -292,6 +325,13 @@ func TestClient_RetryExhaustion(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Equal(t, 3, attemptCount) // Should try 3 times total (1 initial + 2 retries)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 3, apiErr.Attempts)
+	require.Len(t, apiErr.Retries, 2)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.Retries[0].StatusCode)
+	assert.Equal(t, 2, apiErr.Retries[1].Attempt)
 }
 
 func TestClient_ContextCancellation(t *testing.T) {
@@ -557,3 +597,188 @@ func TestClient_RetryWithContextCancellationDuringBackoff(t *testing.T) {
 	// Should only have made one attempt because context was canceled during backoff
 	assert.Equal(t, 1, attemptCount)
 }
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "seconds", header: "5", want: 5 * time.Second, wantOK: true},
+		{name: "date in the past", header: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0, wantOK: true},
+		{name: "missing", header: "", wantOK: false},
+		{name: "invalid", header: "soon", wantOK: false},
+		{name: "negative", header: "-1", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &Response{StatusCode: http.StatusTooManyRequests, Headers: http.Header{}}
+			if tt.header != "" {
+				resp.Headers.Set("Retry-After", tt.header)
+			}
+
+			got, ok := RetryAfter(resp)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("future date", func(t *testing.T) {
+		resp := &Response{Headers: http.Header{}}
+		resp.Headers.Set("Retry-After", time.Now().Add(10*time.Second).UTC().Format(http.TimeFormat))
+
+		got, ok := RetryAfter(resp)
+		assert.True(t, ok)
+		assert.InDelta(t, 10*time.Second, got, float64(2*time.Second))
+	})
+}
+
+func TestRetryConfig_Next(t *testing.T) {
+	config := &RetryConfig{
+		MaxRetries:   3,
+		BackoffMin:   10 * time.Millisecond,
+		BackoffMax:   100 * time.Millisecond,
+		Multiplier:   2.0,
+		JitterFactor: 0,
+	}
+	get := &Request{Method: http.MethodGet, Endpoint: "status/v1/conference/"}
+	command := &Request{Method: http.MethodPost, Endpoint: "command/v1/participant/disconnect/"}
+	unavailable := func(retryAfter string) *Response {
+		resp := &Response{StatusCode: http.StatusServiceUnavailable, Headers: http.Header{}}
+		if retryAfter != "" {
+			resp.Headers.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+
+	tests := []struct {
+		name      string
+		config    func(*RetryConfig)
+		state     *RetryState
+		wantDelay time.Duration
+		wantRetry bool
+	}{
+		{
+			name:      "exponential backoff",
+			state:     &RetryState{Request: get, Attempt: 2, Response: unavailable("")},
+			wantDelay: 20 * time.Millisecond,
+			wantRetry: true,
+		},
+		{
+			name:      "retry after is longer than backoff",
+			config:    func(rc *RetryConfig) { rc.BackoffMax = 5 * time.Second },
+			state:     &RetryState{Request: get, Attempt: 1, Response: unavailable("2")},
+			wantDelay: 2 * time.Second,
+			wantRetry: true,
+		},
+		{
+			name:      "retry after is longer than max backoff",
+			state:     &RetryState{Request: get, Attempt: 1, Response: unavailable("3600")},
+			wantRetry: false,
+		},
+		{
+			name:      "max retries reached",
+			state:     &RetryState{Request: get, Attempt: 4, Response: unavailable("")},
+			wantRetry: false,
+		},
+		{
+			name:      "command not retried",
+			state:     &RetryState{Request: command, Attempt: 1, Response: unavailable("")},
+			wantRetry: false,
+		},
+		{
+			name:      "command retried when opted in",
+			config:    func(rc *RetryConfig) { rc.RetryCommand = true },
+			state:     &RetryState{Request: command, Attempt: 1, Response: unavailable("")},
+			wantDelay: 10 * time.Millisecond,
+			wantRetry: true,
+		},
+		{
+			name:      "retry budget exhausted",
+			config:    func(rc *RetryConfig) { rc.BackoffMax, rc.MaxElapsed = 5*time.Second, time.Second },
+			state:     &RetryState{Request: get, Attempt: 1, Response: unavailable("2")},
+			wantRetry: false,
+		},
+		{
+			name:      "not retriable",
+			state:     &RetryState{Request: get, Attempt: 1, Response: &Response{StatusCode: http.StatusNotFound}},
+			wantRetry: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := *config
+			if tt.config != nil {
+				tt.config(&rc)
+			}
+
+			delay, retry := rc.Next(tt.state)
+			assert.Equal(t, tt.wantRetry, retry)
+			assert.Equal(t, tt.wantDelay, delay)
+		})
+	}
+}
+
+func TestClient_RetryAfterHeader(t *testing.T) {
+	var attempts []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts = append(attempts, time.Now())
+		if len(attempts) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := New(
+		WithBaseURL(server.URL),
+		WithRetryConfig(&RetryConfig{MaxRetries: 1, BackoffMin: time.Millisecond, BackoffMax: 2 * time.Second, Multiplier: 1}),
+	)
+	require.NoError(t, err)
+
+	err = client.GetJSON(t.Context(), "test", nil, nil)
+
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	assert.GreaterOrEqual(t, attempts[1].Sub(attempts[0]), time.Second)
+}
+
+func TestClient_WithRetryPolicy(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	var states []RetryState
+	client, err := New(
+		WithBaseURL(server.URL),
+		WithRetryPolicy(RetryPolicyFunc(func(state *RetryState) (time.Duration, bool) {
+			states = append(states, *state)
+			return time.Millisecond, state.Attempt < 2
+		})),
+	)
+	require.NoError(t, err)
+
+	err = client.PostJSON(t.Context(), "configuration/v1/conference/", map[string]string{"name": "sales"}, nil)
+
+	assert.True(t, IsConflict(err))
+	assert.Equal(t, []string{`{"name":"sales"}`, `{"name":"sales"}`}, bodies)
+	require.Len(t, states, 2)
+	assert.Equal(t, http.MethodPost, states[0].Request.Method)
+	assert.Equal(t, http.StatusConflict, states[0].StatusCode())
+	assert.Equal(t, 2, states[1].Attempt)
+}
+
+func TestWithRetryPolicy_Nil(t *testing.T) {
+	_, err := New(WithRetryPolicy(nil))
+	assert.EqualError(t, err, "retry policy cannot be nil")
+}
@@ -159,6 +159,15 @@ func TestWithAuth(t *testing.T) {
 	assert.Equal(t, authenticator, client.auth)
 }
 
+func TestWithBasicAuthProvider(t *testing.T) {
+	client, err := New(WithBasicAuthProvider(auth.NewEnvCredentials("INFINITY_USERNAME", "INFINITY_PASSWORD")))
+	require.NoError(t, err)
+	assert.IsType(t, &auth.BasicAuth{}, client.auth)
+
+	_, err = New(WithTokenAuthProvider(nil))
+	assert.Error(t, err)
+}
+
 func TestWithUserAgent(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -488,6 +497,43 @@ func TestClientWithAuthentication(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
+func TestClientWithSessionAuth_Reauthenticate(t *testing.T) {
+	var logins, calls int
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/login/", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "csrftoken", Value: "csrf", Path: "/"})
+	})
+	mux.HandleFunc("POST /admin/login/", func(w http.ResponseWriter, r *http.Request) {
+		logins++
+		http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: fmt.Sprintf("session-%d", logins), Path: "/"})
+		w.WriteHeader(http.StatusFound)
+	})
+	mux.HandleFunc("POST /api/admin/configuration/v1/conference/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		session, err := r.Cookie("sessionid")
+		require.NoError(t, err)
+		assert.Equal(t, "csrf", r.Header.Get("X-CSRFToken"))
+		if session.Value == "session-1" {
+			// The first session has expired
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	sessionAuth, err := auth.NewSessionAuth(server.URL, "admin", "password")
+	require.NoError(t, err)
+	client, err := New(WithBaseURL(server.URL), WithAuth(sessionAuth), WithNoRetries())
+	require.NoError(t, err)
+
+	_, err = client.PostWithResponse(t.Context(), "configuration/v1/conference/", map[string]string{"name": "sales"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, logins)
+	assert.Equal(t, 2, calls)
+}
+
 func TestClientWithUserAgent(t *testing.T) {
 	expectedUserAgent := "TestApp/1.0 (test environment)"
 
@@ -686,6 +732,104 @@ func TestAPIError_UnmarshalJSON_InvalidJSON(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestAPIError_WithRequest(t *testing.T) {
+	err := &APIError{
+		StatusCode: 404,
+		Message:    "Not Found",
+		Method:     http.MethodGet,
+		Endpoint:   "configuration/v1/conference/1/",
+	}
+
+	assert.Equal(t, "GET configuration/v1/conference/1/: API error 404: Not Found", err.Error())
+}
+
+func TestAPIError_Is(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		check      func(error) bool
+	}{
+		{statusCode: http.StatusNotFound, check: IsNotFound},
+		{statusCode: http.StatusConflict, check: IsConflict},
+		{statusCode: http.StatusBadRequest, check: IsValidation},
+		{statusCode: http.StatusUnprocessableEntity, check: IsValidation},
+		{statusCode: http.StatusUnauthorized, check: IsUnauthorized},
+		{statusCode: http.StatusTooManyRequests, check: IsRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			err := fmt.Errorf("failed to create conference: %w", &APIError{StatusCode: tt.statusCode})
+
+			assert.True(t, tt.check(err))
+			assert.False(t, tt.check(&APIError{StatusCode: http.StatusInternalServerError}))
+		})
+	}
+
+	assert.False(t, IsNotFound(errors.New("not found")))
+	assert.False(t, IsNotFound(nil))
+}
+
+func TestAPIError_UnmarshalJSON_FieldErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		jsonData string
+		expected []FieldError
+	}{
+		{
+			name:     "keyed by resource",
+			jsonData: `{"conference": {"name": ["Conference with this Name already exists."], "pin": "Invalid PIN"}}`,
+			expected: []FieldError{
+				{Resource: "conference", Field: "name", Messages: []string{"Conference with this Name already exists."}},
+				{Resource: "conference", Field: "pin", Messages: []string{"Invalid PIN"}},
+			},
+		},
+		{
+			name:     "keyed by field",
+			jsonData: `{"alias": ["This field is required."], "error": "Bad Request"}`,
+			expected: []FieldError{
+				{Field: "alias", Messages: []string{"This field is required."}},
+			},
+		},
+		{
+			name:     "no field errors",
+			jsonData: `{"error_message": "Internal error", "traceback": "..."}`,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var apiErr APIError
+			err := json.Unmarshal([]byte(tt.jsonData), &apiErr)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, apiErr.FieldErrors)
+		})
+	}
+}
+
+func TestClient_DoRequest_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"conference": {"name": ["Conference with this Name already exists."]}}`))
+	}))
+	defer server.Close()
+
+	client, err := New(WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	err = client.PostJSON(t.Context(), "configuration/v1/conference/", map[string]string{"name": "sales"}, nil)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, IsValidation(err))
+	assert.Equal(t, http.MethodPost, apiErr.Method)
+	assert.Equal(t, "configuration/v1/conference/", apiErr.Endpoint)
+	assert.Equal(t, 1, apiErr.Attempts)
+	assert.Empty(t, apiErr.Retries)
+	assert.Equal(t, []string{"Conference with this Name already exists."}, apiErr.FieldErrorsFor("name"))
+}
+
 func TestClient_handleAPIError(t *testing.T) {
 	client, err := New()
 	require.NoError(t, err)
@@ -0,0 +1,88 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Command infinity-exporter serves the status of a Pexip Infinity deployment as Prometheus metrics.
+//
+// Credentials are read from the INFINITY_USERNAME and INFINITY_PASSWORD environment variables, or
+// INFINITY_TOKEN for token authentication, so they do not appear in the process list.
+//
+//	INFINITY_USERNAME=admin INFINITY_PASSWORD=secret infinity-exporter -url https://manager.example.com
+package main
+
+import (
+	"errors"
+	"flag"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	infinity "github.com/pexip/go-infinity-sdk/v41"
+	"github.com/pexip/go-infinity-sdk/v41/exporter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func main() {
+	var (
+		listen      = flag.String("listen", ":9898", "address to serve metrics on")
+		path        = flag.String("path", "/metrics", "path to serve metrics on")
+		urls        = flag.String("url", "", "comma-separated management node URLs")
+		timeout     = flag.Duration("timeout", exporter.DefaultTimeout, "time allowed for a scrape")
+		concurrency = flag.Int("concurrency", exporter.DefaultConcurrency, "status endpoints called at the same time")
+	)
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	opts, err := clientOptions(*urls)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts = append(opts, infinity.WithLogger(logger))
+	client, err := infinity.New(opts...)
+	if err != nil {
+		log.Fatal("Failed to create client: ", err)
+	}
+
+	exp, err := exporter.New(client,
+		exporter.WithTimeout(*timeout),
+		exporter.WithConcurrency(*concurrency),
+		exporter.WithLogger(logger),
+	)
+	if err != nil {
+		log.Fatal("Failed to create exporter: ", err)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(exp, collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	mux := http.NewServeMux()
+	mux.Handle(*path, promhttp.HandlerFor(registry, promhttp.HandlerOpts{ErrorLog: slog.NewLogLogger(logger.Handler(), slog.LevelError)}))
+
+	logger.Info("serving metrics", slog.String("address", *listen), slog.String("path", *path))
+	server := &http.Server{Addr: *listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	log.Fatal(server.ListenAndServe())
+}
+
+// clientOptions returns the base URL and authentication options from the flags and environment
+func clientOptions(urls string) ([]infinity.ClientOption, error) {
+	if urls == "" {
+		return nil, errors.New("-url is required")
+	}
+	opts := []infinity.ClientOption{infinity.WithBaseURLs(strings.Split(urls, ",")...)}
+
+	if token := os.Getenv("INFINITY_TOKEN"); token != "" {
+		return append(opts, infinity.WithTokenAuth(token)), nil
+	}
+	username, password := os.Getenv("INFINITY_USERNAME"), os.Getenv("INFINITY_PASSWORD")
+	if username == "" || password == "" {
+		return nil, errors.New("set INFINITY_USERNAME and INFINITY_PASSWORD, or INFINITY_TOKEN")
+	}
+	return append(opts, infinity.WithBasicAuth(username, password)), nil
+}
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListADFSAuthServers(ctx context.Context, opts *ListOptions) (*ADFSAuthServerListResponse, error) {
 	endpoint := "configuration/v1/adfs_auth_server/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result ADFSAuthServerListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllADFSAuthServers returns an iterator over all AD FS OAuth 2.0 Clients, fetching further pages as needed
+func (s *Service) AllADFSAuthServers(ctx context.Context, opts *ListOptions) iter.Seq2[ADFSAuthServer, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ADFSAuthServer, options.Meta, error) {
+		result, err := s.ListADFSAuthServers(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetADFSAuthServer retrieves a specific AD FS OAuth 2.0 Client by ID
 func (s *Service) GetADFSAuthServer(ctx context.Context, id int) (*ADFSAuthServer, error) {
 	endpoint := fmt.Sprintf("configuration/v1/adfs_auth_server/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListADFSAuthServerDomains(ctx context.Context, opts *ListOptions) (*ADFSAuthServerDomainListResponse, error) {
 	endpoint := "configuration/v1/adfs_auth_server_domain/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result ADFSAuthServerDomainListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllADFSAuthServerDomains returns an iterator over all AD FS OAuth 2.0 Client domains, fetching further pages as needed
+func (s *Service) AllADFSAuthServerDomains(ctx context.Context, opts *ListOptions) iter.Seq2[ADFSAuthServerDomain, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ADFSAuthServerDomain, options.Meta, error) {
+		result, err := s.ListADFSAuthServerDomains(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetADFSAuthServerDomain retrieves a specific AD FS OAuth 2.0 Client domain by ID
 func (s *Service) GetADFSAuthServerDomain(ctx context.Context, id int) (*ADFSAuthServerDomain, error) {
 	endpoint := fmt.Sprintf("configuration/v1/adfs_auth_server_domain/%d/", id)
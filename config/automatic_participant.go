@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListAutomaticParticipants(ctx context.Context, opts *ListOptions) (*AutomaticParticipantListResponse, error) {
 	endpoint := "configuration/v1/automatic_participant/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result AutomaticParticipantListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllAutomaticParticipants returns an iterator over all automatic participants, fetching further pages as needed
+func (s *Service) AllAutomaticParticipants(ctx context.Context, opts *ListOptions) iter.Seq2[AutomaticParticipant, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]AutomaticParticipant, options.Meta, error) {
+		result, err := s.ListAutomaticParticipants(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetAutomaticParticipant retrieves a specific automatic participant by ID
 func (s *Service) GetAutomaticParticipant(ctx context.Context, id int) (*AutomaticParticipant, error) {
 	endpoint := fmt.Sprintf("configuration/v1/automatic_participant/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListAzureTenants(ctx context.Context, opts *ListOptions) (*AzureTenantListResponse, error) {
 	endpoint := "configuration/v1/azure_tenant/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result AzureTenantListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllAzureTenants returns an iterator over all Microsoft Teams tenants, fetching further pages as needed
+func (s *Service) AllAzureTenants(ctx context.Context, opts *ListOptions) iter.Seq2[AzureTenant, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]AzureTenant, options.Meta, error) {
+		result, err := s.ListAzureTenants(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetAzureTenant retrieves a specific Microsoft Teams tenant by ID
 func (s *Service) GetAzureTenant(ctx context.Context, id int) (*AzureTenant, error) {
 	endpoint := fmt.Sprintf("configuration/v1/azure_tenant/%d/", id)
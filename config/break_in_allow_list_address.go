@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListBreakInAllowListAddresses(ctx context.Context, opts *ListOptions) (*BreakInAllowListAddressListResponse, error) {
 	endpoint := "configuration/v1/break_in_allow_list_address/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result BreakInAllowListAddressListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllBreakInAllowListAddresses returns an iterator over all break-in attempt IP allow list entries, fetching further pages as needed
+func (s *Service) AllBreakInAllowListAddresses(ctx context.Context, opts *ListOptions) iter.Seq2[BreakInAllowListAddress, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]BreakInAllowListAddress, options.Meta, error) {
+		result, err := s.ListBreakInAllowListAddresses(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetBreakInAllowListAddress retrieves a specific break-in attempt IP allow list entry by ID
 func (s *Service) GetBreakInAllowListAddress(ctx context.Context, id int) (*BreakInAllowListAddress, error) {
 	endpoint := fmt.Sprintf("configuration/v1/break_in_allow_list_address/%d/", id)
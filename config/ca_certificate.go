@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListCACertificates(ctx context.Context, opts *ListOptions) (*CACertificateListResponse, error) {
 	endpoint := "configuration/v1/ca_certificate/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result CACertificateListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllCACertificates returns an iterator over all CA certificates, fetching further pages as needed
+func (s *Service) AllCACertificates(ctx context.Context, opts *ListOptions) iter.Seq2[CACertificate, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]CACertificate, options.Meta, error) {
+		result, err := s.ListCACertificates(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetCACertificate retrieves a specific CA certificate by ID
 func (s *Service) GetCACertificate(ctx context.Context, id int) (*CACertificate, error) {
 	endpoint := fmt.Sprintf("configuration/v1/ca_certificate/%d/", id)
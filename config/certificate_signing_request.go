@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListCertificateSigningRequests(ctx context.Context, opts *ListOptions) (*CertificateSigningRequestListResponse, error) {
 	endpoint := "configuration/v1/certificate_signing_request/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result CertificateSigningRequestListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllCertificateSigningRequests returns an iterator over all certificate signing requests, fetching further pages as needed
+func (s *Service) AllCertificateSigningRequests(ctx context.Context, opts *ListOptions) iter.Seq2[CertificateSigningRequest, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]CertificateSigningRequest, options.Meta, error) {
+		result, err := s.ListCertificateSigningRequests(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetCertificateSigningRequest retrieves a specific certificate signing request by ID
 func (s *Service) GetCertificateSigningRequest(ctx context.Context, id int) (*CertificateSigningRequest, error) {
 	endpoint := fmt.Sprintf("configuration/v1/certificate_signing_request/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListConferences(ctx context.Context, opts *ListOptions) (*ConferenceListResponse, error) {
 	endpoint := "configuration/v1/conference/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result ConferenceListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllConferences returns an iterator over all conferences, fetching further pages as needed
+func (s *Service) AllConferences(ctx context.Context, opts *ListOptions) iter.Seq2[Conference, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]Conference, options.Meta, error) {
+		result, err := s.ListConferences(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetConference retrieves a specific conference by ID
 func (s *Service) GetConference(ctx context.Context, id int) (*Conference, error) {
 	endpoint := fmt.Sprintf("configuration/v1/conference/%d/", id)
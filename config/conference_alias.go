@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListConferenceAliases(ctx context.Context, opts *ListOptions) (*ConferenceAliasListResponse, error) {
 	endpoint := "configuration/v1/conference_alias/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result ConferenceAliasListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllConferenceAliases returns an iterator over all conference aliases, fetching further pages as needed
+func (s *Service) AllConferenceAliases(ctx context.Context, opts *ListOptions) iter.Seq2[ConferenceAlias, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ConferenceAlias, options.Meta, error) {
+		result, err := s.ListConferenceAliases(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetConferenceAlias retrieves a specific conference alias by ID
 func (s *Service) GetConferenceAlias(ctx context.Context, id int) (*ConferenceAlias, error) {
 	endpoint := fmt.Sprintf("configuration/v1/conference_alias/%d/", id)
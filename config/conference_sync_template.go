@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListConferenceSyncTemplates(ctx context.Context, opts *ListOptions) (*ConferenceSyncTemplateListResponse, error) {
 	endpoint := "configuration/v1/conference_sync_template/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result ConferenceSyncTemplateListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllConferenceSyncTemplates returns an iterator over all conference sync templates, fetching further pages as needed
+func (s *Service) AllConferenceSyncTemplates(ctx context.Context, opts *ListOptions) iter.Seq2[ConferenceSyncTemplate, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ConferenceSyncTemplate, options.Meta, error) {
+		result, err := s.ListConferenceSyncTemplates(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetConferenceSyncTemplate retrieves a specific conference sync template by ID
 func (s *Service) GetConferenceSyncTemplate(ctx context.Context, id int) (*ConferenceSyncTemplate, error) {
 	endpoint := fmt.Sprintf("configuration/v1/conference_sync_template/%d/", id)
@@ -8,6 +8,7 @@ package config
 
 import (
 	"errors"
+	"net/url"
 	"testing"
 
 	"github.com/pexip/go-infinity-sdk/v41/interfaces"
@@ -15,6 +16,7 @@ import (
 	"github.com/pexip/go-infinity-sdk/v41/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestService_ListConferences(t *testing.T) {
@@ -292,3 +294,114 @@ func TestService_ConferenceErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestService_AllConferences(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+
+	firstPage := url.Values{"limit": []string{"2"}, "name__icontains": []string{"test"}}
+	secondPage := url.Values{"limit": []string{"2"}, "offset": []string{"2"}, "name__icontains": []string{"test"}}
+
+	client.On("GetJSON", t.Context(), "configuration/v1/conference/", &firstPage, mock.AnythingOfType("*config.ConferenceListResponse")).Return(nil).Run(func(args mock.Arguments) {
+		result := args.Get(3).(*ConferenceListResponse)
+		result.Meta.Limit = 2
+		result.Meta.Next = "/api/admin/configuration/v1/conference/?limit=2&offset=2"
+		result.Meta.TotalCount = 3
+		result.Objects = []Conference{{ID: 1, Name: "test-1"}, {ID: 2, Name: "test-2"}}
+	})
+	client.On("GetJSON", t.Context(), "configuration/v1/conference/", &secondPage, mock.AnythingOfType("*config.ConferenceListResponse")).Return(nil).Run(func(args mock.Arguments) {
+		result := args.Get(3).(*ConferenceListResponse)
+		result.Meta.Limit = 2
+		result.Meta.Offset = 2
+		result.Meta.TotalCount = 3
+		result.Objects = []Conference{{ID: 3, Name: "test-3"}}
+	})
+
+	opts := &ListOptions{
+		BaseListOptions: options.BaseListOptions{Limit: 2},
+		Search:          "test",
+	}
+
+	service := New(client)
+	var ids []int
+	for conference, err := range service.AllConferences(t.Context(), opts) {
+		require.NoError(t, err)
+		ids = append(ids, conference.ID)
+	}
+
+	assert.Equal(t, []int{1, 2, 3}, ids)
+	assert.Equal(t, 0, opts.Offset)
+	client.AssertExpectations(t)
+}
+
+func TestService_AllConferences_Error(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+	client.On("GetJSON", t.Context(), "configuration/v1/conference/", mock.AnythingOfType("*url.Values"), mock.AnythingOfType("*config.ConferenceListResponse")).Return(errors.New("server error"))
+
+	service := New(client)
+	count := 0
+	var lastErr error
+	for _, err := range service.AllConferences(t.Context(), nil) {
+		count++
+		lastErr = err
+	}
+
+	assert.Equal(t, 1, count)
+	assert.EqualError(t, lastErr, "failed to fetch page at offset 0: server error")
+	client.AssertExpectations(t)
+}
+
+func TestService_ListConferences_WithFilter(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+
+	expectedParams := url.Values{
+		"limit":      []string{"5"},
+		"tag__exact": []string{"x"},
+		"order_by":   []string{"-creation_time"},
+	}
+	client.On("GetJSON", t.Context(), "configuration/v1/conference/", &expectedParams, mock.AnythingOfType("*config.ConferenceListResponse")).Return(nil)
+
+	opts := &ListOptions{
+		BaseListOptions: options.BaseListOptions{
+			Limit:  5,
+			Filter: options.NewFilter().Where("tag", options.Exact, "x").OrderBy("-creation_time"),
+		},
+	}
+
+	service := New(client)
+	_, err := service.ListConferences(t.Context(), opts)
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
+
+func TestService_ListConferences_InvalidFilter(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+
+	opts := &ListOptions{
+		BaseListOptions: options.BaseListOptions{
+			Filter: options.NewFilter().Where("pin", options.Exact, "1234"),
+		},
+	}
+
+	service := New(client)
+	_, err := service.ListConferences(t.Context(), opts)
+
+	assert.ErrorIs(t, err, options.ErrInvalidFilter)
+	client.AssertNotCalled(t, "GetJSON", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_ListLicences_FilterWithoutSchema(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+
+	opts := &ListOptions{
+		BaseListOptions: options.BaseListOptions{
+			Filter: options.NewFilter().Where("entitlement_id", options.Exact, "E1"),
+		},
+	}
+
+	service := New(client)
+	_, err := service.ListLicences(t.Context(), opts)
+
+	assert.ErrorIs(t, err, options.ErrInvalidFilter)
+	client.AssertNotCalled(t, "GetJSON", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -10,6 +10,9 @@
 package config
 
 import (
+	"context"
+	"net/url"
+
 	"github.com/pexip/go-infinity-sdk/v41/interfaces"
 )
 
@@ -24,3 +27,15 @@ func New(client interfaces.HTTPClient) *Service {
 		client: client,
 	}
 }
+
+func (s *Service) listEndpoint(ctx context.Context, endpoint string, opts *ListOptions, result interface{}) error {
+	var params *url.Values
+	if opts != nil {
+		if err := validateFilter(endpoint, opts.Filter); err != nil {
+			return err
+		}
+		urlValues := opts.ToURLValues()
+		params = &urlValues
+	}
+	return s.client.GetJSON(ctx, endpoint, params, result)
+}
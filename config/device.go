@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListDevices(ctx context.Context, opts *ListOptions) (*DeviceListResponse, error) {
 	endpoint := "configuration/v1/device/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result DeviceListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllDevices returns an iterator over all devices, fetching further pages as needed
+func (s *Service) AllDevices(ctx context.Context, opts *ListOptions) iter.Seq2[Device, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]Device, options.Meta, error) {
+		result, err := s.ListDevices(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetDevice retrieves a specific device by ID
 func (s *Service) GetDevice(ctx context.Context, id int) (*Device, error) {
 	endpoint := fmt.Sprintf("configuration/v1/device/%d/", id)
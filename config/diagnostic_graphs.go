@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListDiagnosticGraphs(ctx context.Context, opts *ListOptions) (*DiagnosticGraphListResponse, error) {
 	endpoint := "configuration/v1/diagnostic_graphs/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result DiagnosticGraphListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllDiagnosticGraphs returns an iterator over all diagnostic graphs, fetching further pages as needed
+func (s *Service) AllDiagnosticGraphs(ctx context.Context, opts *ListOptions) iter.Seq2[DiagnosticGraph, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]DiagnosticGraph, options.Meta, error) {
+		result, err := s.ListDiagnosticGraphs(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetDiagnosticGraph retrieves a specific diagnostic graph by ID
 func (s *Service) GetDiagnosticGraph(ctx context.Context, id int) (*DiagnosticGraph, error) {
 	endpoint := fmt.Sprintf("configuration/v1/diagnostic_graphs/%d/", id)
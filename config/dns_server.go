@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListDNSServers(ctx context.Context, opts *ListOptions) (*DNSServerListResponse, error) {
 	endpoint := "configuration/v1/dns_server/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result DNSServerListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllDNSServers returns an iterator over all DNS servers, fetching further pages as needed
+func (s *Service) AllDNSServers(ctx context.Context, opts *ListOptions) iter.Seq2[DNSServer, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]DNSServer, options.Meta, error) {
+		result, err := s.ListDNSServers(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetDNSServer retrieves a specific DNS server by ID
 func (s *Service) GetDNSServer(ctx context.Context, id int) (*DNSServer, error) {
 	endpoint := fmt.Sprintf("configuration/v1/dns_server/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListEndUsers(ctx context.Context, opts *ListOptions) (*EndUserListResponse, error) {
 	endpoint := "configuration/v1/end_user/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result EndUserListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllEndUsers returns an iterator over all end users, fetching further pages as needed
+func (s *Service) AllEndUsers(ctx context.Context, opts *ListOptions) iter.Seq2[EndUser, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]EndUser, options.Meta, error) {
+		result, err := s.ListEndUsers(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetEndUser retrieves a specific end user by ID
 func (s *Service) GetEndUser(ctx context.Context, id int) (*EndUser, error) {
 	endpoint := fmt.Sprintf("configuration/v1/end_user/%d/", id)
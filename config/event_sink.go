@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListEventSinks(ctx context.Context, opts *ListOptions) (*EventSinkListResponse, error) {
 	endpoint := "configuration/v1/event_sink/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result EventSinkListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllEventSinks returns an iterator over all event sinks, fetching further pages as needed
+func (s *Service) AllEventSinks(ctx context.Context, opts *ListOptions) iter.Seq2[EventSink, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]EventSink, options.Meta, error) {
+		result, err := s.ListEventSinks(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetEventSink retrieves a specific event sink by ID
 func (s *Service) GetEventSink(ctx context.Context, id int) (*EventSink, error) {
 	endpoint := fmt.Sprintf("configuration/v1/event_sink/%d/", id)
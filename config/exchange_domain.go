@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListExchangeDomains(ctx context.Context, opts *ListOptions) (*ExchangeDomainListResponse, error) {
 	endpoint := "configuration/v1/exchange_domain/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result ExchangeDomainListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllExchangeDomains returns an iterator over all Exchange Metadata Domains, fetching further pages as needed
+func (s *Service) AllExchangeDomains(ctx context.Context, opts *ListOptions) iter.Seq2[ExchangeDomain, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ExchangeDomain, options.Meta, error) {
+		result, err := s.ListExchangeDomains(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetExchangeDomain retrieves a specific Exchange Metadata Domain by ID
 func (s *Service) GetExchangeDomain(ctx context.Context, id int) (*ExchangeDomain, error) {
 	endpoint := fmt.Sprintf("configuration/v1/exchange_domain/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListExternalWebappHosts(ctx context.Context, opts *ListOptions) (*ExternalWebappHostListResponse, error) {
 	endpoint := "configuration/v1/external_webapp_host/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result ExternalWebappHostListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllExternalWebappHosts returns an iterator over all external web app hosts, fetching further pages as needed
+func (s *Service) AllExternalWebappHosts(ctx context.Context, opts *ListOptions) iter.Seq2[ExternalWebappHost, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ExternalWebappHost, options.Meta, error) {
+		result, err := s.ListExternalWebappHosts(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetExternalWebappHost retrieves a specific external web app host by ID
 func (s *Service) GetExternalWebappHost(ctx context.Context, id int) (*ExternalWebappHost, error) {
 	endpoint := fmt.Sprintf("configuration/v1/external_webapp_host/%d/", id)
@@ -0,0 +1,113 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
+)
+
+// filterSchemas lists the filterable and orderable fields of configuration resources,
+// keyed by list endpoint. Filters on endpoints without a schema are rejected.
+var filterSchemas = map[string]*options.FilterSchema{
+	"configuration/v1/conference/": {
+		Fields: map[string][]options.Operator{
+			"id":                          options.NumericOperators,
+			"name":                        options.TextOperators,
+			"description":                 options.TextOperators,
+			"service_type":                options.TextOperators,
+			"call_type":                   options.TextOperators,
+			"tag":                         options.TextOperators,
+			"sync_tag":                    options.TextOperators,
+			"primary_owner_email_address": options.TextOperators,
+			"allow_guests":                options.BoolOperators,
+			"creation_time":               options.TimeOperators,
+		},
+		Ordering: []string{"id", "name", "service_type", "tag", "creation_time"},
+	},
+	"configuration/v1/conference_alias/": {
+		Fields: map[string][]options.Operator{
+			"id":            options.NumericOperators,
+			"alias":         options.TextOperators,
+			"conference":    options.NumericOperators,
+			"description":   options.TextOperators,
+			"creation_time": options.TimeOperators,
+		},
+		Ordering: []string{"id", "alias", "conference", "creation_time"},
+	},
+	"configuration/v1/device/": {
+		Fields: map[string][]options.Operator{
+			"id":                          options.NumericOperators,
+			"alias":                       options.TextOperators,
+			"description":                 options.TextOperators,
+			"username":                    options.TextOperators,
+			"primary_owner_email_address": options.TextOperators,
+			"tag":                         options.TextOperators,
+			"sync_tag":                    options.TextOperators,
+			"creation_time":               options.TimeOperators,
+		},
+		Ordering: []string{"id", "alias", "username", "tag", "creation_time"},
+	},
+	"configuration/v1/end_user/": {
+		Fields: map[string][]options.Operator{
+			"id":                    options.NumericOperators,
+			"primary_email_address": options.TextOperators,
+			"first_name":            options.TextOperators,
+			"last_name":             options.TextOperators,
+			"display_name":          options.TextOperators,
+			"department":            options.TextOperators,
+			"uuid":                  options.TextOperators,
+			"sync_tag":              options.TextOperators,
+		},
+		Ordering: []string{"id", "primary_email_address", "first_name", "last_name", "display_name"},
+	},
+	"configuration/v1/gateway_routing_rule/": {
+		Fields: map[string][]options.Operator{
+			"id":            options.NumericOperators,
+			"name":          options.TextOperators,
+			"description":   options.TextOperators,
+			"priority":      options.NumericOperators,
+			"enable":        options.BoolOperators,
+			"match_string":  options.TextOperators,
+			"tag":           options.TextOperators,
+			"creation_time": options.TimeOperators,
+		},
+		Ordering: []string{"id", "name", "priority", "tag", "creation_time"},
+	},
+	"configuration/v1/system_location/": {
+		Fields: map[string][]options.Operator{
+			"id":          options.NumericOperators,
+			"name":        options.TextOperators,
+			"description": options.TextOperators,
+		},
+		Ordering: []string{"id", "name"},
+	},
+	"configuration/v1/worker_vm/": {
+		Fields: map[string][]options.Operator{
+			"id":               options.NumericOperators,
+			"name":             options.TextOperators,
+			"hostname":         options.TextOperators,
+			"address":          options.TextOperators,
+			"node_type":        options.TextOperators,
+			"maintenance_mode": options.BoolOperators,
+			"system_location":  options.NumericOperators,
+		},
+		Ordering: []string{"id", "name", "hostname", "address"},
+	},
+}
+
+// validateFilter checks filter against the schema of endpoint. Endpoints without a schema
+// fail closed: a filter or ordering on them cannot be checked, so it returns an error
+// wrapping options.ErrInvalidFilter rather than being sent as is.
+func validateFilter(endpoint string, filter *options.Filter) error {
+	schema, ok := filterSchemas[endpoint]
+	if !ok && filter != nil && (len(filter.Conditions) > 0 || len(filter.Ordering) > 0) {
+		return fmt.Errorf("%w: filtering and ordering are not supported on %s", options.ErrInvalidFilter, endpoint)
+	}
+	return filter.Validate(schema)
+}
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListGatewayRoutingRules(ctx context.Context, opts *ListOptions) (*GatewayRoutingRuleListResponse, error) {
 	endpoint := "configuration/v1/gateway_routing_rule/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result GatewayRoutingRuleListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllGatewayRoutingRules returns an iterator over all gateway routing rules, fetching further pages as needed
+func (s *Service) AllGatewayRoutingRules(ctx context.Context, opts *ListOptions) iter.Seq2[GatewayRoutingRule, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]GatewayRoutingRule, options.Meta, error) {
+		result, err := s.ListGatewayRoutingRules(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetGatewayRoutingRule retrieves a specific gateway routing rule by ID
 func (s *Service) GetGatewayRoutingRule(ctx context.Context, id int) (*GatewayRoutingRule, error) {
 	endpoint := fmt.Sprintf("configuration/v1/gateway_routing_rule/%d/", id)
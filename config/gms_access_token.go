@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListGMSAccessTokens(ctx context.Context, opts *ListOptions) (*GMSAccessTokenListResponse, error) {
 	endpoint := "configuration/v1/gms_access_token/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result GMSAccessTokenListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllGMSAccessTokens returns an iterator over all Google Meet access tokens, fetching further pages as needed
+func (s *Service) AllGMSAccessTokens(ctx context.Context, opts *ListOptions) iter.Seq2[GMSAccessToken, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]GMSAccessToken, options.Meta, error) {
+		result, err := s.ListGMSAccessTokens(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetGMSAccessToken retrieves a specific Google Meet access token by ID
 func (s *Service) GetGMSAccessToken(ctx context.Context, id int) (*GMSAccessToken, error) {
 	endpoint := fmt.Sprintf("configuration/v1/gms_access_token/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListGoogleAuthServers(ctx context.Context, opts *ListOptions) (*GoogleAuthServerListResponse, error) {
 	endpoint := "configuration/v1/google_auth_server/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result GoogleAuthServerListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllGoogleAuthServers returns an iterator over all Google OAuth 2.0 Credentials, fetching further pages as needed
+func (s *Service) AllGoogleAuthServers(ctx context.Context, opts *ListOptions) iter.Seq2[GoogleAuthServer, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]GoogleAuthServer, options.Meta, error) {
+		result, err := s.ListGoogleAuthServers(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetGoogleAuthServer retrieves a specific Google OAuth 2.0 Credential by ID
 func (s *Service) GetGoogleAuthServer(ctx context.Context, id int) (*GoogleAuthServer, error) {
 	endpoint := fmt.Sprintf("configuration/v1/google_auth_server/%d/", id)
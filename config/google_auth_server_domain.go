@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListGoogleAuthServerDomains(ctx context.Context, opts *ListOptions) (*GoogleAuthServerDomainListResponse, error) {
 	endpoint := "configuration/v1/google_auth_server_domain/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result GoogleAuthServerDomainListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllGoogleAuthServerDomains returns an iterator over all Google OAuth 2.0 Credential domains, fetching further pages as needed
+func (s *Service) AllGoogleAuthServerDomains(ctx context.Context, opts *ListOptions) iter.Seq2[GoogleAuthServerDomain, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]GoogleAuthServerDomain, options.Meta, error) {
+		result, err := s.ListGoogleAuthServerDomains(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetGoogleAuthServerDomain retrieves a specific Google OAuth 2.0 Credential domain by ID
 func (s *Service) GetGoogleAuthServerDomain(ctx context.Context, id int) (*GoogleAuthServerDomain, error) {
 	endpoint := fmt.Sprintf("configuration/v1/google_auth_server_domain/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListH323Gatekeepers(ctx context.Context, opts *ListOptions) (*H323GatekeeperListResponse, error) {
 	endpoint := "configuration/v1/h323_gatekeeper/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result H323GatekeeperListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllH323Gatekeepers returns an iterator over all H.323 gatekeepers, fetching further pages as needed
+func (s *Service) AllH323Gatekeepers(ctx context.Context, opts *ListOptions) iter.Seq2[H323Gatekeeper, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]H323Gatekeeper, options.Meta, error) {
+		result, err := s.ListH323Gatekeepers(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetH323Gatekeeper retrieves a specific H.323 gatekeeper by ID
 func (s *Service) GetH323Gatekeeper(ctx context.Context, id int) (*H323Gatekeeper, error) {
 	endpoint := fmt.Sprintf("configuration/v1/h323_gatekeeper/%d/", id)
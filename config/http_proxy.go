@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListHTTPProxies(ctx context.Context, opts *ListOptions) (*HTTPProxyListResponse, error) {
 	endpoint := "configuration/v1/http_proxy/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result HTTPProxyListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllHTTPProxies returns an iterator over all HTTP proxies, fetching further pages as needed
+func (s *Service) AllHTTPProxies(ctx context.Context, opts *ListOptions) iter.Seq2[HTTPProxy, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]HTTPProxy, options.Meta, error) {
+		result, err := s.ListHTTPProxies(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetHTTPProxy retrieves a specific HTTP proxy by ID
 func (s *Service) GetHTTPProxy(ctx context.Context, id int) (*HTTPProxy, error) {
 	endpoint := fmt.Sprintf("configuration/v1/http_proxy/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListIdentityProviders(ctx context.Context, opts *ListOptions) (*IdentityProviderListResponse, error) {
 	endpoint := "configuration/v1/identity_provider/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result IdentityProviderListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllIdentityProviders returns an iterator over all identity providers, fetching further pages as needed
+func (s *Service) AllIdentityProviders(ctx context.Context, opts *ListOptions) iter.Seq2[IdentityProvider, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]IdentityProvider, options.Meta, error) {
+		result, err := s.ListIdentityProviders(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetIdentityProvider retrieves a specific identity provider by ID
 func (s *Service) GetIdentityProvider(ctx context.Context, id int) (*IdentityProvider, error) {
 	endpoint := fmt.Sprintf("configuration/v1/identity_provider/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListIdentityProviderAttributes(ctx context.Context, opts *ListOptions) (*IdentityProviderAttributeListResponse, error) {
 	endpoint := "configuration/v1/identity_provider_attribute/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result IdentityProviderAttributeListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllIdentityProviderAttributes returns an iterator over all identity provider attributes, fetching further pages as needed
+func (s *Service) AllIdentityProviderAttributes(ctx context.Context, opts *ListOptions) iter.Seq2[IdentityProviderAttribute, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]IdentityProviderAttribute, options.Meta, error) {
+		result, err := s.ListIdentityProviderAttributes(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetIdentityProviderAttribute retrieves a specific identity provider attribute by ID
 func (s *Service) GetIdentityProviderAttribute(ctx context.Context, id int) (*IdentityProviderAttribute, error) {
 	endpoint := fmt.Sprintf("configuration/v1/identity_provider_attribute/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListIdentityProviderGroups(ctx context.Context, opts *ListOptions) (*IdentityProviderGroupListResponse, error) {
 	endpoint := "configuration/v1/identity_provider_group/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result IdentityProviderGroupListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllIdentityProviderGroups returns an iterator over all identity provider groups, fetching further pages as needed
+func (s *Service) AllIdentityProviderGroups(ctx context.Context, opts *ListOptions) iter.Seq2[IdentityProviderGroup, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]IdentityProviderGroup, options.Meta, error) {
+		result, err := s.ListIdentityProviderGroups(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetIdentityProviderGroup retrieves a specific identity provider group by ID
 func (s *Service) GetIdentityProviderGroup(ctx context.Context, id int) (*IdentityProviderGroup, error) {
 	endpoint := fmt.Sprintf("configuration/v1/identity_provider_group/%d/", id)
@@ -11,8 +11,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -20,17 +21,22 @@ import (
 func (s *Service) ListIVRThemes(ctx context.Context, opts *ListOptions) (*IVRThemeListResponse, error) {
 	endpoint := "configuration/v1/ivr_theme/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result IVRThemeListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllIVRThemes returns an iterator over all IVR themes, fetching further pages as needed
+func (s *Service) AllIVRThemes(ctx context.Context, opts *ListOptions) iter.Seq2[IVRTheme, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]IVRTheme, options.Meta, error) {
+		result, err := s.ListIVRThemes(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetIVRTheme retrieves a specific IVR theme by ID
 func (s *Service) GetIVRTheme(ctx context.Context, id int) (*IVRTheme, error) {
 	endpoint := fmt.Sprintf("configuration/v1/ivr_theme/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListLdapRoles(ctx context.Context, opts *ListOptions) (*LdapRoleListResponse, error) {
 	endpoint := "configuration/v1/ldap_role/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result LdapRoleListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllLdapRoles returns an iterator over all LDAP roles, fetching further pages as needed
+func (s *Service) AllLdapRoles(ctx context.Context, opts *ListOptions) iter.Seq2[LdapRole, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]LdapRole, options.Meta, error) {
+		result, err := s.ListLdapRoles(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetLdapRole retrieves a specific LDAP role by ID
 func (s *Service) GetLdapRole(ctx context.Context, id int) (*LdapRole, error) {
 	endpoint := fmt.Sprintf("configuration/v1/ldap_role/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListLdapSyncFields(ctx context.Context, opts *ListOptions) (*LdapSyncFieldListResponse, error) {
 	endpoint := "configuration/v1/ldap_sync_field/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result LdapSyncFieldListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllLdapSyncFields returns an iterator over all LDAP sync fields, fetching further pages as needed
+func (s *Service) AllLdapSyncFields(ctx context.Context, opts *ListOptions) iter.Seq2[LdapSyncField, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]LdapSyncField, options.Meta, error) {
+		result, err := s.ListLdapSyncFields(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetLdapSyncField retrieves a specific LDAP sync field by ID
 func (s *Service) GetLdapSyncField(ctx context.Context, id int) (*LdapSyncField, error) {
 	endpoint := fmt.Sprintf("configuration/v1/ldap_sync_field/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListLdapSyncSources(ctx context.Context, opts *ListOptions) (*LdapSyncSourceListResponse, error) {
 	endpoint := "configuration/v1/ldap_sync_source/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result LdapSyncSourceListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllLdapSyncSources returns an iterator over all LDAP sync sources, fetching further pages as needed
+func (s *Service) AllLdapSyncSources(ctx context.Context, opts *ListOptions) iter.Seq2[LdapSyncSource, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]LdapSyncSource, options.Meta, error) {
+		result, err := s.ListLdapSyncSources(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetLdapSyncSource retrieves a specific LDAP sync source by ID
 func (s *Service) GetLdapSyncSource(ctx context.Context, id int) (*LdapSyncSource, error) {
 	endpoint := fmt.Sprintf("configuration/v1/ldap_sync_source/%d/", id)
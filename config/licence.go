@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListLicences(ctx context.Context, opts *ListOptions) (*LicenceListResponse, error) {
 	endpoint := "configuration/v1/licence/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result LicenceListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllLicences returns an iterator over all licences, fetching further pages as needed
+func (s *Service) AllLicences(ctx context.Context, opts *ListOptions) iter.Seq2[Licence, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]Licence, options.Meta, error) {
+		result, err := s.ListLicences(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetLicence retrieves a specific licence by fulfillment ID
 func (s *Service) GetLicence(ctx context.Context, fulfillmentID string) (*Licence, error) {
 	endpoint := fmt.Sprintf("configuration/v1/licence/%s/", fulfillmentID)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListLicenceRequests(ctx context.Context, opts *ListOptions) (*LicenceRequestListResponse, error) {
 	endpoint := "configuration/v1/licence_request/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result LicenceRequestListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllLicenceRequests returns an iterator over all licence requests, fetching further pages as needed
+func (s *Service) AllLicenceRequests(ctx context.Context, opts *ListOptions) iter.Seq2[LicenceRequest, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]LicenceRequest, options.Meta, error) {
+		result, err := s.ListLicenceRequests(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetLicenceRequest retrieves a specific licence request by sequence number
 func (s *Service) GetLicenceRequest(ctx context.Context, sequenceNumber string) (*LicenceRequest, error) {
 	endpoint := fmt.Sprintf("configuration/v1/licence_request/%s/", sequenceNumber)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListLogLevels(ctx context.Context, opts *ListOptions) (*LogLevelListResponse, error) {
 	endpoint := "configuration/v1/log_level/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result LogLevelListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllLogLevels returns an iterator over all log levels, fetching further pages as needed
+func (s *Service) AllLogLevels(ctx context.Context, opts *ListOptions) iter.Seq2[LogLevel, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]LogLevel, options.Meta, error) {
+		result, err := s.ListLogLevels(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetLogLevel retrieves a specific log level by ID
 func (s *Service) GetLogLevel(ctx context.Context, id int) (*LogLevel, error) {
 	endpoint := fmt.Sprintf("configuration/v1/log_level/%d/", id)
@@ -9,24 +9,31 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListManagementVMs retrieves a list of management VMs.
 func (s *Service) ListManagementVMs(ctx context.Context, opts *ListOptions) (*ManagementVMListResponse, error) {
 	endpoint := "configuration/v1/management_vm/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result ManagementVMListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllManagementVMs returns an iterator over all management VMs, fetching further pages as needed
+func (s *Service) AllManagementVMs(ctx context.Context, opts *ListOptions) iter.Seq2[ManagementVM, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ManagementVM, options.Meta, error) {
+		result, err := s.ListManagementVMs(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetManagementVM retrieves a management VM by ID. If id is omitted, defaults to 1.
 func (s *Service) GetManagementVM(ctx context.Context, id ...int) (*ManagementVM, error) {
 	vmID := 1
@@ -10,8 +10,9 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -19,17 +20,22 @@ import (
 func (s *Service) ListMediaLibraryEntries(ctx context.Context, opts *ListOptions) (*MediaLibraryEntryListResponse, error) {
 	endpoint := "configuration/v1/media_library_entry/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result MediaLibraryEntryListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllMediaLibraryEntries returns an iterator over all media library entries, fetching further pages as needed
+func (s *Service) AllMediaLibraryEntries(ctx context.Context, opts *ListOptions) iter.Seq2[MediaLibraryEntry, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MediaLibraryEntry, options.Meta, error) {
+		result, err := s.ListMediaLibraryEntries(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMediaLibraryEntry retrieves a specific media library entry by ID
 func (s *Service) GetMediaLibraryEntry(ctx context.Context, id int) (*MediaLibraryEntry, error) {
 	endpoint := fmt.Sprintf("configuration/v1/media_library_entry/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListMediaLibraryPlaylists(ctx context.Context, opts *ListOptions) (*MediaLibraryPlaylistListResponse, error) {
 	endpoint := "configuration/v1/media_library_playlist/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result MediaLibraryPlaylistListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllMediaLibraryPlaylists returns an iterator over all media library playlists, fetching further pages as needed
+func (s *Service) AllMediaLibraryPlaylists(ctx context.Context, opts *ListOptions) iter.Seq2[MediaLibraryPlaylist, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MediaLibraryPlaylist, options.Meta, error) {
+		result, err := s.ListMediaLibraryPlaylists(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMediaLibraryPlaylist retrieves a specific media library playlist by ID
 func (s *Service) GetMediaLibraryPlaylist(ctx context.Context, id int) (*MediaLibraryPlaylist, error) {
 	endpoint := fmt.Sprintf("configuration/v1/media_library_playlist/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListMediaLibraryPlaylistEntries(ctx context.Context, opts *ListOptions) (*MediaLibraryPlaylistEntryListResponse, error) {
 	endpoint := "configuration/v1/media_library_playlist_entry/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result MediaLibraryPlaylistEntryListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllMediaLibraryPlaylistEntries returns an iterator over all media library playlist entries, fetching further pages as needed
+func (s *Service) AllMediaLibraryPlaylistEntries(ctx context.Context, opts *ListOptions) iter.Seq2[MediaLibraryPlaylistEntry, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MediaLibraryPlaylistEntry, options.Meta, error) {
+		result, err := s.ListMediaLibraryPlaylistEntries(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMediaLibraryPlaylistEntry retrieves a specific media library playlist entry by ID
 func (s *Service) GetMediaLibraryPlaylistEntry(ctx context.Context, id int) (*MediaLibraryPlaylistEntry, error) {
 	endpoint := fmt.Sprintf("configuration/v1/media_library_playlist_entry/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListMediaProcessingServers(ctx context.Context, opts *ListOptions) (*MediaProcessingServerListResponse, error) {
 	endpoint := "configuration/v1/media_processing_server/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result MediaProcessingServerListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllMediaProcessingServers returns an iterator over all media processing servers, fetching further pages as needed
+func (s *Service) AllMediaProcessingServers(ctx context.Context, opts *ListOptions) iter.Seq2[MediaProcessingServer, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MediaProcessingServer, options.Meta, error) {
+		result, err := s.ListMediaProcessingServers(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMediaProcessingServer retrieves a specific media processing server by ID
 func (s *Service) GetMediaProcessingServer(ctx context.Context, id int) (*MediaProcessingServer, error) {
 	endpoint := fmt.Sprintf("configuration/v1/media_processing_server/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListMjxEndpoints(ctx context.Context, opts *ListOptions) (*MjxEndpointListResponse, error) {
 	endpoint := "configuration/v1/mjx_endpoint/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result MjxEndpointListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllMjxEndpoints returns an iterator over all MJX endpoints, fetching further pages as needed
+func (s *Service) AllMjxEndpoints(ctx context.Context, opts *ListOptions) iter.Seq2[MjxEndpoint, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MjxEndpoint, options.Meta, error) {
+		result, err := s.ListMjxEndpoints(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMjxEndpoint retrieves a specific MJX endpoint by ID
 func (s *Service) GetMjxEndpoint(ctx context.Context, id int) (*MjxEndpoint, error) {
 	endpoint := fmt.Sprintf("configuration/v1/mjx_endpoint/%d/", id)
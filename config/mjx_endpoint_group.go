@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListMjxEndpointGroups(ctx context.Context, opts *ListOptions) (*MjxEndpointGroupListResponse, error) {
 	endpoint := "configuration/v1/mjx_endpoint_group/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result MjxEndpointGroupListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllMjxEndpointGroups returns an iterator over all MJX endpoint groups, fetching further pages as needed
+func (s *Service) AllMjxEndpointGroups(ctx context.Context, opts *ListOptions) iter.Seq2[MjxEndpointGroup, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MjxEndpointGroup, options.Meta, error) {
+		result, err := s.ListMjxEndpointGroups(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMjxEndpointGroup retrieves a specific MJX endpoint group by ID
 func (s *Service) GetMjxEndpointGroup(ctx context.Context, id int) (*MjxEndpointGroup, error) {
 	endpoint := fmt.Sprintf("configuration/v1/mjx_endpoint_group/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListMjxExchangeAutodiscoverURLs(ctx context.Context, opts *ListOptions) (*MjxExchangeAutodiscoverURLListResponse, error) {
 	endpoint := "configuration/v1/mjx_exchange_autodiscover_url/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result MjxExchangeAutodiscoverURLListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllMjxExchangeAutodiscoverURLs returns an iterator over all MJX Exchange autodiscover URLs, fetching further pages as needed
+func (s *Service) AllMjxExchangeAutodiscoverURLs(ctx context.Context, opts *ListOptions) iter.Seq2[MjxExchangeAutodiscoverURL, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MjxExchangeAutodiscoverURL, options.Meta, error) {
+		result, err := s.ListMjxExchangeAutodiscoverURLs(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMjxExchangeAutodiscoverURL retrieves a specific MJX Exchange autodiscover URL by ID
 func (s *Service) GetMjxExchangeAutodiscoverURL(ctx context.Context, id int) (*MjxExchangeAutodiscoverURL, error) {
 	endpoint := fmt.Sprintf("configuration/v1/mjx_exchange_autodiscover_url/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListMjxExchangeDeployments(ctx context.Context, opts *ListOptions) (*MjxExchangeDeploymentListResponse, error) {
 	endpoint := "configuration/v1/mjx_exchange_deployment/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result MjxExchangeDeploymentListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllMjxExchangeDeployments returns an iterator over all MJX Exchange deployments, fetching further pages as needed
+func (s *Service) AllMjxExchangeDeployments(ctx context.Context, opts *ListOptions) iter.Seq2[MjxExchangeDeployment, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MjxExchangeDeployment, options.Meta, error) {
+		result, err := s.ListMjxExchangeDeployments(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMjxExchangeDeployment retrieves a specific MJX Exchange deployment by ID
 func (s *Service) GetMjxExchangeDeployment(ctx context.Context, id int) (*MjxExchangeDeployment, error) {
 	endpoint := fmt.Sprintf("configuration/v1/mjx_exchange_deployment/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListMjxGoogleDeployments(ctx context.Context, opts *ListOptions) (*MjxGoogleDeploymentListResponse, error) {
 	endpoint := "configuration/v1/mjx_google_deployment/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result MjxGoogleDeploymentListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllMjxGoogleDeployments returns an iterator over all MJX Google deployments, fetching further pages as needed
+func (s *Service) AllMjxGoogleDeployments(ctx context.Context, opts *ListOptions) iter.Seq2[MjxGoogleDeployment, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MjxGoogleDeployment, options.Meta, error) {
+		result, err := s.ListMjxGoogleDeployments(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMjxGoogleDeployment retrieves a specific MJX Google deployment by ID
 func (s *Service) GetMjxGoogleDeployment(ctx context.Context, id int) (*MjxGoogleDeployment, error) {
 	endpoint := fmt.Sprintf("configuration/v1/mjx_google_deployment/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListMjxGraphDeployments(ctx context.Context, opts *ListOptions) (*MjxGraphDeploymentListResponse, error) {
 	endpoint := "configuration/v1/mjx_graph_deployment/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result MjxGraphDeploymentListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllMjxGraphDeployments returns an iterator over all MJX Graph deployments, fetching further pages as needed
+func (s *Service) AllMjxGraphDeployments(ctx context.Context, opts *ListOptions) iter.Seq2[MjxGraphDeployment, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MjxGraphDeployment, options.Meta, error) {
+		result, err := s.ListMjxGraphDeployments(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMjxGraphDeployment retrieves a specific MJX Graph deployment by ID
 func (s *Service) GetMjxGraphDeployment(ctx context.Context, id int) (*MjxGraphDeployment, error) {
 	endpoint := fmt.Sprintf("configuration/v1/mjx_graph_deployment/%d/", id)
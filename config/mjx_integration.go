@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListMjxIntegrations(ctx context.Context, opts *ListOptions) (*MjxIntegrationListResponse, error) {
 	endpoint := "configuration/v1/mjx_integration/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result MjxIntegrationListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllMjxIntegrations returns an iterator over all MJX integrations, fetching further pages as needed
+func (s *Service) AllMjxIntegrations(ctx context.Context, opts *ListOptions) iter.Seq2[MjxIntegration, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MjxIntegration, options.Meta, error) {
+		result, err := s.ListMjxIntegrations(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMjxIntegration retrieves a specific MJX integration by ID
 func (s *Service) GetMjxIntegration(ctx context.Context, id int) (*MjxIntegration, error) {
 	endpoint := fmt.Sprintf("configuration/v1/mjx_integration/%d/", id)
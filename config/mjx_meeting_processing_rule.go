@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListMjxMeetingProcessingRules(ctx context.Context, opts *ListOptions) (*MjxMeetingProcessingRuleListResponse, error) {
 	endpoint := "configuration/v1/mjx_meeting_processing_rule/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result MjxMeetingProcessingRuleListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllMjxMeetingProcessingRules returns an iterator over all MJX meeting processing rules, fetching further pages as needed
+func (s *Service) AllMjxMeetingProcessingRules(ctx context.Context, opts *ListOptions) iter.Seq2[MjxMeetingProcessingRule, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MjxMeetingProcessingRule, options.Meta, error) {
+		result, err := s.ListMjxMeetingProcessingRules(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMjxMeetingProcessingRule retrieves a specific MJX meeting processing rule by ID
 func (s *Service) GetMjxMeetingProcessingRule(ctx context.Context, id int) (*MjxMeetingProcessingRule, error) {
 	endpoint := fmt.Sprintf("configuration/v1/mjx_meeting_processing_rule/%d/", id)
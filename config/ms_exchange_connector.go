@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListMsExchangeConnectors(ctx context.Context, opts *ListOptions) (*MsExchangeConnectorListResponse, error) {
 	endpoint := "configuration/v1/ms_exchange_connector/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result MsExchangeConnectorListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllMsExchangeConnectors returns an iterator over all Microsoft Exchange connectors, fetching further pages as needed
+func (s *Service) AllMsExchangeConnectors(ctx context.Context, opts *ListOptions) iter.Seq2[MsExchangeConnector, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MsExchangeConnector, options.Meta, error) {
+		result, err := s.ListMsExchangeConnectors(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMsExchangeConnector retrieves a specific Microsoft Exchange connector by ID
 func (s *Service) GetMsExchangeConnector(ctx context.Context, id int) (*MsExchangeConnector, error) {
 	endpoint := fmt.Sprintf("configuration/v1/ms_exchange_connector/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListMSSIPProxies(ctx context.Context, opts *ListOptions) (*MSSIPProxyListResponse, error) {
 	endpoint := "configuration/v1/mssip_proxy/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result MSSIPProxyListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllMSSIPProxies returns an iterator over all MS-SIP proxies, fetching further pages as needed
+func (s *Service) AllMSSIPProxies(ctx context.Context, opts *ListOptions) iter.Seq2[MSSIPProxy, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MSSIPProxy, options.Meta, error) {
+		result, err := s.ListMSSIPProxies(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMSSIPProxy retrieves a specific MS-SIP proxy by ID
 func (s *Service) GetMSSIPProxy(ctx context.Context, id int) (*MSSIPProxy, error) {
 	endpoint := fmt.Sprintf("configuration/v1/mssip_proxy/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListNTPServers(ctx context.Context, opts *ListOptions) (*NTPServerListResponse, error) {
 	endpoint := "configuration/v1/ntp_server/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result NTPServerListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllNTPServers returns an iterator over all NTP servers, fetching further pages as needed
+func (s *Service) AllNTPServers(ctx context.Context, opts *ListOptions) iter.Seq2[NTPServer, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]NTPServer, options.Meta, error) {
+		result, err := s.ListNTPServers(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetNTPServer retrieves a specific NTP server by ID
 func (s *Service) GetNTPServer(ctx context.Context, id int) (*NTPServer, error) {
 	endpoint := fmt.Sprintf("configuration/v1/ntp_server/%d/", id)
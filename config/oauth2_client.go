@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListOAuth2Clients(ctx context.Context, opts *ListOptions) (*OAuth2ClientListResponse, error) {
 	endpoint := "configuration/v1/oauth2_client/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result OAuth2ClientListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllOAuth2Clients returns an iterator over all OAuth2 clients, fetching further pages as needed
+func (s *Service) AllOAuth2Clients(ctx context.Context, opts *ListOptions) iter.Seq2[OAuth2Client, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]OAuth2Client, options.Meta, error) {
+		result, err := s.ListOAuth2Clients(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetOAuth2Client retrieves a specific OAuth2 client by client ID
 func (s *Service) GetOAuth2Client(ctx context.Context, clientID string) (*OAuth2Client, error) {
 	endpoint := fmt.Sprintf("configuration/v1/oauth2_client/%s/", clientID)
@@ -9,24 +9,31 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListPermissions retrieves a list of permissions (read-only)
 func (s *Service) ListPermissions(ctx context.Context, opts *ListOptions) (*PermissionListResponse, error) {
 	endpoint := "configuration/v1/permission/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result PermissionListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllPermissions returns an iterator over all permissions, fetching further pages as needed
+func (s *Service) AllPermissions(ctx context.Context, opts *ListOptions) iter.Seq2[Permission, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]Permission, options.Meta, error) {
+		result, err := s.ListPermissions(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetPermission retrieves a specific permission by ID (read-only)
 func (s *Service) GetPermission(ctx context.Context, id int) (*Permission, error) {
 	endpoint := fmt.Sprintf("configuration/v1/permission/%d/", id)
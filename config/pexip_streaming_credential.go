@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListPexipStreamingCredentials(ctx context.Context, opts *ListOptions) (*PexipStreamingCredentialListResponse, error) {
 	endpoint := "configuration/v1/pexip_streaming_credential/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result PexipStreamingCredentialListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllPexipStreamingCredentials returns an iterator over all Pexip Streaming credentials, fetching further pages as needed
+func (s *Service) AllPexipStreamingCredentials(ctx context.Context, opts *ListOptions) iter.Seq2[PexipStreamingCredential, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]PexipStreamingCredential, options.Meta, error) {
+		result, err := s.ListPexipStreamingCredentials(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetPexipStreamingCredential retrieves a specific Pexip Streaming credential by ID
 func (s *Service) GetPexipStreamingCredential(ctx context.Context, id int) (*PexipStreamingCredential, error) {
 	endpoint := fmt.Sprintf("configuration/v1/pexip_streaming_credential/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListPolicyServers(ctx context.Context, opts *ListOptions) (*PolicyServerListResponse, error) {
 	endpoint := "configuration/v1/policy_server/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result PolicyServerListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllPolicyServers returns an iterator over all policy servers, fetching further pages as needed
+func (s *Service) AllPolicyServers(ctx context.Context, opts *ListOptions) iter.Seq2[PolicyServer, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]PolicyServer, options.Meta, error) {
+		result, err := s.ListPolicyServers(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetPolicyServer retrieves a specific policy server by ID
 func (s *Service) GetPolicyServer(ctx context.Context, id int) (*PolicyServer, error) {
 	endpoint := fmt.Sprintf("configuration/v1/policy_server/%d/", id)
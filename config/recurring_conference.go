@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListRecurringConferences(ctx context.Context, opts *ListOptions) (*RecurringConferenceListResponse, error) {
 	endpoint := "configuration/v1/recurring_conference/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result RecurringConferenceListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllRecurringConferences returns an iterator over all recurring conferences, fetching further pages as needed
+func (s *Service) AllRecurringConferences(ctx context.Context, opts *ListOptions) iter.Seq2[RecurringConference, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]RecurringConference, options.Meta, error) {
+		result, err := s.ListRecurringConferences(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetRecurringConference retrieves a specific recurring conference by ID
 func (s *Service) GetRecurringConference(ctx context.Context, id int) (*RecurringConference, error) {
 	endpoint := fmt.Sprintf("configuration/v1/recurring_conference/%d/", id)
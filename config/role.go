@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListRoles(ctx context.Context, opts *ListOptions) (*RoleListResponse, error) {
 	endpoint := "configuration/v1/role/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result RoleListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllRoles returns an iterator over all roles, fetching further pages as needed
+func (s *Service) AllRoles(ctx context.Context, opts *ListOptions) iter.Seq2[Role, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]Role, options.Meta, error) {
+		result, err := s.ListRoles(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetRole retrieves a specific role by ID
 func (s *Service) GetRole(ctx context.Context, id int) (*Role, error) {
 	endpoint := fmt.Sprintf("configuration/v1/role/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListRoleMappings(ctx context.Context, opts *ListOptions) (*RoleMappingListResponse, error) {
 	endpoint := "configuration/v1/role_mapping/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result RoleMappingListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllRoleMappings returns an iterator over all role mappings, fetching further pages as needed
+func (s *Service) AllRoleMappings(ctx context.Context, opts *ListOptions) iter.Seq2[RoleMapping, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]RoleMapping, options.Meta, error) {
+		result, err := s.ListRoleMappings(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetRoleMapping retrieves a specific role mapping by ID
 func (s *Service) GetRoleMapping(ctx context.Context, id int) (*RoleMapping, error) {
 	endpoint := fmt.Sprintf("configuration/v1/role_mapping/%d/", id)
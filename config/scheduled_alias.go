@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListScheduledAliases(ctx context.Context, opts *ListOptions) (*ScheduledAliasListResponse, error) {
 	endpoint := "configuration/v1/scheduled_alias/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result ScheduledAliasListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllScheduledAliases returns an iterator over all scheduled aliases, fetching further pages as needed
+func (s *Service) AllScheduledAliases(ctx context.Context, opts *ListOptions) iter.Seq2[ScheduledAlias, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ScheduledAlias, options.Meta, error) {
+		result, err := s.ListScheduledAliases(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetScheduledAlias retrieves a specific scheduled alias by ID
 func (s *Service) GetScheduledAlias(ctx context.Context, id int) (*ScheduledAlias, error) {
 	endpoint := fmt.Sprintf("configuration/v1/scheduled_alias/%d/", id)
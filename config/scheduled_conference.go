@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListScheduledConferences(ctx context.Context, opts *ListOptions) (*ScheduledConferenceListResponse, error) {
 	endpoint := "configuration/v1/scheduled_conference/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result ScheduledConferenceListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllScheduledConferences returns an iterator over all scheduled conferences, fetching further pages as needed
+func (s *Service) AllScheduledConferences(ctx context.Context, opts *ListOptions) iter.Seq2[ScheduledConference, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ScheduledConference, options.Meta, error) {
+		result, err := s.ListScheduledConferences(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetScheduledConference retrieves a specific scheduled conference by ID
 func (s *Service) GetScheduledConference(ctx context.Context, id int) (*ScheduledConference, error) {
 	endpoint := fmt.Sprintf("configuration/v1/scheduled_conference/%d/", id)
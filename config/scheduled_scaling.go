@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListScheduledScalings(ctx context.Context, opts *ListOptions) (*ScheduledScalingListResponse, error) {
 	endpoint := "configuration/v1/scheduled_scaling/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result ScheduledScalingListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllScheduledScalings returns an iterator over all scheduled scaling policies, fetching further pages as needed
+func (s *Service) AllScheduledScalings(ctx context.Context, opts *ListOptions) iter.Seq2[ScheduledScaling, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ScheduledScaling, options.Meta, error) {
+		result, err := s.ListScheduledScalings(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetScheduledScaling retrieves a specific scheduled scaling policy by ID
 func (s *Service) GetScheduledScaling(ctx context.Context, id int) (*ScheduledScaling, error) {
 	endpoint := fmt.Sprintf("configuration/v1/scheduled_scaling/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListSIPCredentials(ctx context.Context, opts *ListOptions) (*SIPCredentialListResponse, error) {
 	endpoint := "configuration/v1/sip_credential/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result SIPCredentialListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllSIPCredentials returns an iterator over all SIP credentials, fetching further pages as needed
+func (s *Service) AllSIPCredentials(ctx context.Context, opts *ListOptions) iter.Seq2[SIPCredential, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]SIPCredential, options.Meta, error) {
+		result, err := s.ListSIPCredentials(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSIPCredential retrieves a specific SIP credential by ID
 func (s *Service) GetSIPCredential(ctx context.Context, id int) (*SIPCredential, error) {
 	endpoint := fmt.Sprintf("configuration/v1/sip_credential/%d/", id)
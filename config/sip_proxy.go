@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListSIPProxies(ctx context.Context, opts *ListOptions) (*SIPProxyListResponse, error) {
 	endpoint := "configuration/v1/sip_proxy/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result SIPProxyListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllSIPProxies returns an iterator over all SIP proxies, fetching further pages as needed
+func (s *Service) AllSIPProxies(ctx context.Context, opts *ListOptions) iter.Seq2[SIPProxy, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]SIPProxy, options.Meta, error) {
+		result, err := s.ListSIPProxies(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSIPProxy retrieves a specific SIP proxy by ID
 func (s *Service) GetSIPProxy(ctx context.Context, id int) (*SIPProxy, error) {
 	endpoint := fmt.Sprintf("configuration/v1/sip_proxy/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListSMTPServers(ctx context.Context, opts *ListOptions) (*SMTPServerListResponse, error) {
 	endpoint := "configuration/v1/smtp_server/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result SMTPServerListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllSMTPServers returns an iterator over all SMTP servers, fetching further pages as needed
+func (s *Service) AllSMTPServers(ctx context.Context, opts *ListOptions) iter.Seq2[SMTPServer, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]SMTPServer, options.Meta, error) {
+		result, err := s.ListSMTPServers(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSMTPServer retrieves a specific SMTP server by ID
 func (s *Service) GetSMTPServer(ctx context.Context, id int) (*SMTPServer, error) {
 	endpoint := fmt.Sprintf("configuration/v1/smtp_server/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListSnmpNetworkManagementSystems(ctx context.Context, opts *ListOptions) (*SnmpNetworkManagementSystemListResponse, error) {
 	endpoint := "configuration/v1/snmp_network_management_system/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result SnmpNetworkManagementSystemListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllSnmpNetworkManagementSystems returns an iterator over all SNMP network management systems, fetching further pages as needed
+func (s *Service) AllSnmpNetworkManagementSystems(ctx context.Context, opts *ListOptions) iter.Seq2[SnmpNetworkManagementSystem, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]SnmpNetworkManagementSystem, options.Meta, error) {
+		result, err := s.ListSnmpNetworkManagementSystems(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSnmpNetworkManagementSystem retrieves a specific SNMP network management system by ID
 func (s *Service) GetSnmpNetworkManagementSystem(ctx context.Context, id int) (*SnmpNetworkManagementSystem, error) {
 	endpoint := fmt.Sprintf("configuration/v1/snmp_network_management_system/%d/", id)
@@ -9,24 +9,31 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListSoftwareBundles retrieves a list of software bundles (read-only)
 func (s *Service) ListSoftwareBundles(ctx context.Context, opts *ListOptions) (*SoftwareBundleListResponse, error) {
 	endpoint := "configuration/v1/software_bundle/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result SoftwareBundleListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllSoftwareBundles returns an iterator over all software bundles, fetching further pages as needed
+func (s *Service) AllSoftwareBundles(ctx context.Context, opts *ListOptions) iter.Seq2[SoftwareBundle, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]SoftwareBundle, options.Meta, error) {
+		result, err := s.ListSoftwareBundles(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSoftwareBundle retrieves a specific software bundle by ID (read-only)
 func (s *Service) GetSoftwareBundle(ctx context.Context, id int) (*SoftwareBundle, error) {
 	endpoint := fmt.Sprintf("configuration/v1/software_bundle/%d/", id)
@@ -9,24 +9,31 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListSoftwareBundleRevisions retrieves a list of software bundle revisions (read-only)
 func (s *Service) ListSoftwareBundleRevisions(ctx context.Context, opts *ListOptions) (*SoftwareBundleRevisionListResponse, error) {
 	endpoint := "configuration/v1/software_bundle_revision/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result SoftwareBundleRevisionListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllSoftwareBundleRevisions returns an iterator over all software bundle revisions, fetching further pages as needed
+func (s *Service) AllSoftwareBundleRevisions(ctx context.Context, opts *ListOptions) iter.Seq2[SoftwareBundleRevision, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]SoftwareBundleRevision, options.Meta, error) {
+		result, err := s.ListSoftwareBundleRevisions(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSoftwareBundleRevision retrieves a specific software bundle revision by ID (read-only)
 func (s *Service) GetSoftwareBundleRevision(ctx context.Context, id int) (*SoftwareBundleRevision, error) {
 	endpoint := fmt.Sprintf("configuration/v1/software_bundle_revision/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListSSHAuthorizedKeys(ctx context.Context, opts *ListOptions) (*SSHAuthorizedKeyListResponse, error) {
 	endpoint := "configuration/v1/ssh_authorized_key/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result SSHAuthorizedKeyListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllSSHAuthorizedKeys returns an iterator over all SSH authorized keys, fetching further pages as needed
+func (s *Service) AllSSHAuthorizedKeys(ctx context.Context, opts *ListOptions) iter.Seq2[SSHAuthorizedKey, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]SSHAuthorizedKey, options.Meta, error) {
+		result, err := s.ListSSHAuthorizedKeys(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSSHAuthorizedKey retrieves a specific SSH authorized key by ID
 func (s *Service) GetSSHAuthorizedKey(ctx context.Context, id int) (*SSHAuthorizedKey, error) {
 	endpoint := fmt.Sprintf("configuration/v1/ssh_authorized_key/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListStaticRoutes(ctx context.Context, opts *ListOptions) (*StaticRouteListResponse, error) {
 	endpoint := "configuration/v1/static_route/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result StaticRouteListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllStaticRoutes returns an iterator over all static routes, fetching further pages as needed
+func (s *Service) AllStaticRoutes(ctx context.Context, opts *ListOptions) iter.Seq2[StaticRoute, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]StaticRoute, options.Meta, error) {
+		result, err := s.ListStaticRoutes(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetStaticRoute retrieves a specific static route by ID
 func (s *Service) GetStaticRoute(ctx context.Context, id int) (*StaticRoute, error) {
 	endpoint := fmt.Sprintf("configuration/v1/static_route/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListSTUNServers(ctx context.Context, opts *ListOptions) (*STUNServerListResponse, error) {
 	endpoint := "configuration/v1/stun_server/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result STUNServerListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllSTUNServers returns an iterator over all STUN servers, fetching further pages as needed
+func (s *Service) AllSTUNServers(ctx context.Context, opts *ListOptions) iter.Seq2[STUNServer, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]STUNServer, options.Meta, error) {
+		result, err := s.ListSTUNServers(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSTUNServer retrieves a specific STUN server by ID
 func (s *Service) GetSTUNServer(ctx context.Context, id int) (*STUNServer, error) {
 	endpoint := fmt.Sprintf("configuration/v1/stun_server/%d/", id)
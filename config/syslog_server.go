@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListSyslogServers(ctx context.Context, opts *ListOptions) (*SyslogServerListResponse, error) {
 	endpoint := "configuration/v1/syslog_server/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result SyslogServerListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllSyslogServers returns an iterator over all syslog servers, fetching further pages as needed
+func (s *Service) AllSyslogServers(ctx context.Context, opts *ListOptions) iter.Seq2[SyslogServer, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]SyslogServer, options.Meta, error) {
+		result, err := s.ListSyslogServers(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSyslogServer retrieves a specific syslog server by ID
 func (s *Service) GetSyslogServer(ctx context.Context, id int) (*SyslogServer, error) {
 	endpoint := fmt.Sprintf("configuration/v1/syslog_server/%d/", id)
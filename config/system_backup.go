@@ -8,24 +8,31 @@ package config
 
 import (
 	"context"
-	"net/url"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListSystemBackups retrieves a list of system backups (read-only)
 func (s *Service) ListSystemBackups(ctx context.Context, opts *ListOptions) (*SystemBackupListResponse, error) {
 	endpoint := "configuration/v1/system_backup/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result SystemBackupListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllSystemBackups returns an iterator over all system backups, fetching further pages as needed
+func (s *Service) AllSystemBackups(ctx context.Context, opts *ListOptions) iter.Seq2[SystemBackup, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]SystemBackup, options.Meta, error) {
+		result, err := s.ListSystemBackups(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSystemBackup retrieves a specific system backup by filename (read-only)
 func (s *Service) GetSystemBackup(ctx context.Context, filename string) (*SystemBackup, error) {
 	endpoint := "configuration/v1/system_backup/" + filename + "/"
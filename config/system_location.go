@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListSystemLocations(ctx context.Context, opts *ListOptions) (*SystemLocationListResponse, error) {
 	endpoint := "configuration/v1/system_location/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result SystemLocationListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllSystemLocations returns an iterator over all system locations, fetching further pages as needed
+func (s *Service) AllSystemLocations(ctx context.Context, opts *ListOptions) iter.Seq2[SystemLocation, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]SystemLocation, options.Meta, error) {
+		result, err := s.ListSystemLocations(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSystemLocation retrieves a specific system location by ID
 func (s *Service) GetSystemLocation(ctx context.Context, id int) (*SystemLocation, error) {
 	endpoint := fmt.Sprintf("configuration/v1/system_location/%d/", id)
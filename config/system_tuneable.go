@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListSystemTuneables(ctx context.Context, opts *ListOptions) (*SystemTuneableListResponse, error) {
 	endpoint := "configuration/v1/system_tuneable/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result SystemTuneableListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllSystemTuneables returns an iterator over all system tuneables, fetching further pages as needed
+func (s *Service) AllSystemTuneables(ctx context.Context, opts *ListOptions) iter.Seq2[SystemTuneable, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]SystemTuneable, options.Meta, error) {
+		result, err := s.ListSystemTuneables(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSystemTuneable retrieves a specific system tuneable by ID
 func (s *Service) GetSystemTuneable(ctx context.Context, id int) (*SystemTuneable, error) {
 	endpoint := fmt.Sprintf("configuration/v1/system_tuneable/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListTeamsProxies(ctx context.Context, opts *ListOptions) (*TeamsProxyListResponse, error) {
 	endpoint := "configuration/v1/teams_proxy/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result TeamsProxyListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllTeamsProxies returns an iterator over all Teams proxies, fetching further pages as needed
+func (s *Service) AllTeamsProxies(ctx context.Context, opts *ListOptions) iter.Seq2[TeamsProxy, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]TeamsProxy, options.Meta, error) {
+		result, err := s.ListTeamsProxies(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetTeamsProxy retrieves a specific Teams proxy by ID
 func (s *Service) GetTeamsProxy(ctx context.Context, id int) (*TeamsProxy, error) {
 	endpoint := fmt.Sprintf("configuration/v1/teams_proxy/%d/", id)
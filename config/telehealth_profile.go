@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListTelehealthProfiles(ctx context.Context, opts *ListOptions) (*TelehealthProfileListResponse, error) {
 	endpoint := "configuration/v1/telehealth_profile/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result TelehealthProfileListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllTelehealthProfiles returns an iterator over all telehealth profiles, fetching further pages as needed
+func (s *Service) AllTelehealthProfiles(ctx context.Context, opts *ListOptions) iter.Seq2[TelehealthProfile, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]TelehealthProfile, options.Meta, error) {
+		result, err := s.ListTelehealthProfiles(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetTelehealthProfile retrieves a specific telehealth profile by ID
 func (s *Service) GetTelehealthProfile(ctx context.Context, id int) (*TelehealthProfile, error) {
 	endpoint := fmt.Sprintf("configuration/v1/telehealth_profile/%d/", id)
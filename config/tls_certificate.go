@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListTLSCertificates(ctx context.Context, opts *ListOptions) (*TLSCertificateListResponse, error) {
 	endpoint := "configuration/v1/tls_certificate/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result TLSCertificateListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllTLSCertificates returns an iterator over all TLS certificates, fetching further pages as needed
+func (s *Service) AllTLSCertificates(ctx context.Context, opts *ListOptions) iter.Seq2[TLSCertificate, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]TLSCertificate, options.Meta, error) {
+		result, err := s.ListTLSCertificates(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetTLSCertificate retrieves a specific TLS certificate by ID
 func (s *Service) GetTLSCertificate(ctx context.Context, id int) (*TLSCertificate, error) {
 	endpoint := fmt.Sprintf("configuration/v1/tls_certificate/%d/", id)
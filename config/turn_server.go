@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListTURNServers(ctx context.Context, opts *ListOptions) (*TURNServerListResponse, error) {
 	endpoint := "configuration/v1/turn_server/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result TURNServerListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllTURNServers returns an iterator over all TURN servers, fetching further pages as needed
+func (s *Service) AllTURNServers(ctx context.Context, opts *ListOptions) iter.Seq2[TURNServer, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]TURNServer, options.Meta, error) {
+		result, err := s.ListTURNServers(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetTURNServer retrieves a specific TURN server by ID
 func (s *Service) GetTURNServer(ctx context.Context, id int) (*TURNServer, error) {
 	endpoint := fmt.Sprintf("configuration/v1/turn_server/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListUserGroups(ctx context.Context, opts *ListOptions) (*UserGroupListResponse, error) {
 	endpoint := "configuration/v1/user_group/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result UserGroupListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllUserGroups returns an iterator over all user groups, fetching further pages as needed
+func (s *Service) AllUserGroups(ctx context.Context, opts *ListOptions) iter.Seq2[UserGroup, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]UserGroup, options.Meta, error) {
+		result, err := s.ListUserGroups(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetUserGroup retrieves a specific user group by ID
 func (s *Service) GetUserGroup(ctx context.Context, id int) (*UserGroup, error) {
 	endpoint := fmt.Sprintf("configuration/v1/user_group/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListUserGroupEntityMappings(ctx context.Context, opts *ListOptions) (*UserGroupEntityMappingListResponse, error) {
 	endpoint := "configuration/v1/user_group_entity_mapping/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result UserGroupEntityMappingListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllUserGroupEntityMappings returns an iterator over all user group entity mappings, fetching further pages as needed
+func (s *Service) AllUserGroupEntityMappings(ctx context.Context, opts *ListOptions) iter.Seq2[UserGroupEntityMapping, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]UserGroupEntityMapping, options.Meta, error) {
+		result, err := s.ListUserGroupEntityMappings(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetUserGroupEntityMapping retrieves a specific user group entity mapping by ID
 func (s *Service) GetUserGroupEntityMapping(ctx context.Context, id int) (*UserGroupEntityMapping, error) {
 	endpoint := fmt.Sprintf("configuration/v1/user_group_entity_mapping/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListWebappAliases(ctx context.Context, opts *ListOptions) (*WebappAliasListResponse, error) {
 	endpoint := "configuration/v1/webapp_alias/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result WebappAliasListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllWebappAliases returns an iterator over all web app aliases, fetching further pages as needed
+func (s *Service) AllWebappAliases(ctx context.Context, opts *ListOptions) iter.Seq2[WebappAlias, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]WebappAlias, options.Meta, error) {
+		result, err := s.ListWebappAliases(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetWebappAlias retrieves a specific web app alias by ID
 func (s *Service) GetWebappAlias(ctx context.Context, id int) (*WebappAlias, error) {
 	endpoint := fmt.Sprintf("configuration/v1/webapp_alias/%d/", id)
@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"io"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListWebappBrandings(ctx context.Context, opts *ListOptions) (*WebappBrandingListResponse, error) {
 	endpoint := "configuration/v1/webapp_branding/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result WebappBrandingListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllWebappBrandings returns an iterator over all webapp brandings, fetching further pages as needed
+func (s *Service) AllWebappBrandings(ctx context.Context, opts *ListOptions) iter.Seq2[WebappBranding, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]WebappBranding, options.Meta, error) {
+		result, err := s.ListWebappBrandings(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetWebappBranding retrieves a specific webapp branding by name
 func (s *Service) GetWebappBranding(ctx context.Context, uuid string) (*WebappBranding, error) {
 	endpoint := "configuration/v1/webapp_branding/" + uuid + "/"
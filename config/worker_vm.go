@@ -9,8 +9,9 @@ package config
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"iter"
 
+	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/pexip/go-infinity-sdk/v41/types"
 )
 
@@ -18,17 +19,22 @@ import (
 func (s *Service) ListWorkerVMs(ctx context.Context, opts *ListOptions) (*WorkerVMListResponse, error) {
 	endpoint := "configuration/v1/worker_vm/"
 
-	var params *url.Values
-	if opts != nil {
-		urlValues := opts.ToURLValues()
-		params = &urlValues
-	}
-
 	var result WorkerVMListResponse
-	err := s.client.GetJSON(ctx, endpoint, params, &result)
+	err := s.listEndpoint(ctx, endpoint, opts, &result)
 	return &result, err
 }
 
+// AllWorkerVMs returns an iterator over all worker VMs, fetching further pages as needed
+func (s *Service) AllWorkerVMs(ctx context.Context, opts *ListOptions) iter.Seq2[WorkerVM, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]WorkerVM, options.Meta, error) {
+		result, err := s.ListWorkerVMs(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetWorkerVM retrieves a specific worker VM by ID
 func (s *Service) GetWorkerVM(ctx context.Context, id int) (*WorkerVM, error) {
 	endpoint := fmt.Sprintf("configuration/v1/worker_vm/%d/", id)
@@ -0,0 +1,154 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package eventsink
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// EventType is the name of an Infinity event
+type EventType string
+
+const (
+	EventSinkStarted                 EventType = "eventsink_started"
+	EventSinkUpdated                 EventType = "eventsink_updated"
+	EventSinkStopped                 EventType = "eventsink_stopped"
+	ConferenceStarted                EventType = "conference_started"
+	ConferenceUpdated                EventType = "conference_updated"
+	ConferenceEnded                  EventType = "conference_ended"
+	ParticipantConnected             EventType = "participant_connected"
+	ParticipantUpdated               EventType = "participant_updated"
+	ParticipantDisconnected          EventType = "participant_disconnected"
+	ParticipantMediaStreamWindow     EventType = "participant_media_stream_window"
+	ParticipantMediaStreamsDestroyed EventType = "participant_media_streams_destroyed"
+)
+
+// Event is one event sent by a Conferencing Node. Conference is decoded from Data for conference events
+// and Participant for participant_connected, participant_updated and participant_disconnected events;
+// Data keeps the raw payload for every event, including the media stream events.
+type Event struct {
+	Type    EventType       `json:"event"`
+	Node    string          `json:"node"`
+	Seq     int64           `json:"seq"`
+	Version int             `json:"version"`
+	Time    time.Time       `json:"-"`
+	Data    json.RawMessage `json:"data"`
+
+	Conference  *Conference  `json:"-"`
+	Participant *Participant `json:"-"`
+
+	hasSeq bool // whether the event had a sequence number to deduplicate on
+}
+
+// Conference is the data of conference events
+type Conference struct {
+	Name        string     `json:"name"`
+	ServiceType string     `json:"service_type"`
+	Tag         string     `json:"tag"`
+	IsLocked    bool       `json:"is_locked"`
+	IsStarted   bool       `json:"is_started"`
+	GuestsMuted bool       `json:"guests_muted"`
+	StartTime   *Timestamp `json:"start_time,omitempty"`
+	EndTime     *Timestamp `json:"end_time,omitempty"`
+}
+
+// Participant is the data of participant events
+type Participant struct {
+	UUID             string     `json:"uuid"`
+	ConversationID   string     `json:"conversation_id"`
+	CallID           string     `json:"call_id"`
+	Conference       string     `json:"conference"`
+	DisplayName      string     `json:"display_name"`
+	SourceAlias      string     `json:"source_alias"`
+	DestinationAlias string     `json:"destination_alias"`
+	RemoteAddress    string     `json:"remote_address"`
+	Role             string     `json:"role"`
+	Protocol         string     `json:"protocol"`
+	CallDirection    string     `json:"call_direction"`
+	ServiceType      string     `json:"service_type"`
+	ServiceTag       string     `json:"service_tag"`
+	Vendor           string     `json:"vendor"`
+	Encryption       string     `json:"encryption"`
+	CallQuality      string     `json:"call_quality"`
+	LicenseType      string     `json:"license_type"`
+	MediaNode        string     `json:"media_node"`
+	SignallingNode   string     `json:"signalling_node"`
+	SystemLocation   string     `json:"system_location"`
+	Bandwidth        int        `json:"bandwidth"`
+	HasMedia         bool       `json:"has_media"`
+	IsMuted          bool       `json:"is_muted"`
+	IsPresenting     bool       `json:"is_presenting"`
+	IsStreaming      bool       `json:"is_streaming"`
+	IsOnHold         bool       `json:"is_on_hold"`
+	ConnectTime      *Timestamp `json:"connect_time,omitempty"`
+	Duration         float64    `json:"duration,omitempty"`
+	DisconnectReason string     `json:"disconnect_reason,omitempty"`
+}
+
+// Timestamp is a time sent as fractional seconds since the Unix epoch
+type Timestamp struct {
+	time.Time
+}
+
+// UnmarshalJSON decodes fractional epoch seconds, ignoring null
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return fmt.Errorf("invalid timestamp %s: %w", data, err)
+	}
+	t.Time = epoch(seconds)
+	return nil
+}
+
+func epoch(seconds float64) time.Time {
+	whole, frac := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(frac*1e9)).UTC()
+}
+
+// UnmarshalJSON decodes an event and its typed data
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type plain Event
+	var raw struct {
+		plain
+		Seq  *int64  `json:"seq"`
+		Time float64 `json:"time"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*e = Event(raw.plain)
+	if raw.Seq != nil {
+		e.Seq, e.hasSeq = *raw.Seq, true
+	}
+	if raw.Time > 0 {
+		e.Time = epoch(raw.Time)
+	}
+	if len(e.Data) == 0 || string(e.Data) == "null" {
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(string(e.Type), "conference_"):
+		e.Conference = &Conference{}
+		if err := json.Unmarshal(e.Data, e.Conference); err != nil {
+			return fmt.Errorf("invalid %s data: %w", e.Type, err)
+		}
+	case e.Type == ParticipantConnected, e.Type == ParticipantUpdated, e.Type == ParticipantDisconnected:
+		e.Participant = &Participant{}
+		if err := json.Unmarshal(e.Data, e.Participant); err != nil {
+			return fmt.Errorf("invalid %s data: %w", e.Type, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package eventsink receives the external events that Infinity Conferencing Nodes send to event sinks
+// configured with config.EventSink. Receiver is an http.Handler that accepts single and bulk event POSTs,
+// decodes them into typed events, checks the sink's basic auth credentials, drops events it has already
+// seen and dispatches the rest to registered handlers or a channel.
+package eventsink
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// Receiver defaults
+const (
+	DefaultDedupWindow = 1024
+	DefaultMaxBodySize = 10 << 20
+)
+
+// HandlerFunc handles an event. Returning an error makes the receiver answer with a server error, so the
+// Conferencing Node sends the event again.
+type HandlerFunc func(ctx context.Context, event *Event) error
+
+// Receiver is an http.Handler for event sink POSTs. It is safe for concurrent use.
+type Receiver struct {
+	username    string
+	password    string
+	events      chan<- *Event
+	dedupWindow int
+	maxBodySize int64
+
+	mu       sync.RWMutex
+	handlers map[EventType][]HandlerFunc
+	all      []HandlerFunc
+
+	seenMu sync.Mutex
+	seen   map[string]*window // per Conferencing Node
+}
+
+// Option configures a Receiver
+type Option func(*Receiver)
+
+// WithBasicAuth requires the username and password configured on the event sink
+func WithBasicAuth(username, password string) Option {
+	return func(r *Receiver) {
+		r.username = username
+		r.password = password
+	}
+}
+
+// WithChannel delivers every event on ch, after the registered handlers. A full channel holds up the
+// response until there is room or the request is cancelled.
+func WithChannel(ch chan<- *Event) Option {
+	return func(r *Receiver) {
+		r.events = ch
+	}
+}
+
+// WithDedupWindow sets how many recent sequence numbers are remembered per Conferencing Node. Zero or a
+// negative n disables deduplication.
+func WithDedupWindow(n int) Option {
+	return func(r *Receiver) {
+		r.dedupWindow = max(n, 0)
+	}
+}
+
+// WithMaxBodySize sets the largest request body accepted, in bytes
+func WithMaxBodySize(n int64) Option {
+	return func(r *Receiver) {
+		r.maxBodySize = n
+	}
+}
+
+// NewReceiver creates a new event sink receiver
+func NewReceiver(opts ...Option) *Receiver {
+	r := &Receiver{
+		dedupWindow: DefaultDedupWindow,
+		maxBodySize: DefaultMaxBodySize,
+		handlers:    make(map[EventType][]HandlerFunc),
+		seen:        make(map[string]*window),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Handle registers fn for events of the given type
+func (r *Receiver) Handle(eventType EventType, fn HandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[eventType] = append(r.handlers[eventType], fn)
+}
+
+// HandleAll registers fn for every event
+func (r *Receiver) HandleAll(fn HandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.all = append(r.all, fn)
+}
+
+// ServeHTTP accepts a POST of one event or, for sinks with bulk support, an array of events
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !r.authorized(req) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="eventsink"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, r.maxBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusRequestEntityTooLarge)
+		return
+	}
+	events, err := decode(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, event := range events {
+		if err := r.dispatch(req.Context(), event); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (r *Receiver) authorized(req *http.Request) bool {
+	if r.username == "" && r.password == "" {
+		return true
+	}
+	username, password, ok := req.BasicAuth()
+	return ok &&
+		subtle.ConstantTimeCompare([]byte(username), []byte(r.username)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(password), []byte(r.password)) == 1
+}
+
+// decode parses a single event or an array of events
+func decode(body []byte) ([]*Event, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var events []*Event
+		if err := json.Unmarshal(body, &events); err != nil {
+			return nil, fmt.Errorf("invalid events: %w", err)
+		}
+		return events, nil
+	}
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	return []*Event{&event}, nil
+}
+
+// dispatch passes an event that has not been seen before to the handlers and the channel. The event's
+// sequence number is claimed while it is handled, and remembered only once every handler has succeeded.
+func (r *Receiver) dispatch(ctx context.Context, event *Event) (err error) {
+	claimed, err := r.claim(event)
+	if !claimed {
+		return err
+	}
+	defer func() { r.release(event, err == nil) }()
+
+	r.mu.RLock()
+	handlers := slices.Concat(r.handlers[event.Type], r.all)
+	r.mu.RUnlock()
+	for _, fn := range handlers {
+		if err := fn(ctx, event); err != nil {
+			return fmt.Errorf("%s %d: %w", event.Type, event.Seq, err)
+		}
+	}
+	if r.events != nil {
+		select {
+		case r.events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// claim reports whether the event should be dispatched. An event whose sequence number has been seen from
+// its node is not; one that is still being handled is not either, and an error asks the node to send it
+// again in case handling it fails. Events without a sequence number are always dispatched.
+func (r *Receiver) claim(event *Event) (bool, error) {
+	r.seenMu.Lock()
+	defer r.seenMu.Unlock()
+	if event.Type == EventSinkStarted {
+		// The node has restarted its sequence
+		delete(r.seen, event.Node)
+		return true, nil
+	}
+	if !event.hasSeq {
+		return true, nil
+	}
+	w, ok := r.seen[event.Node]
+	if !ok {
+		w = newWindow(r.dedupWindow)
+		r.seen[event.Node] = w
+	}
+	if w.contains(event.Seq) {
+		return false, nil
+	}
+	if _, ok := w.inFlight[event.Seq]; ok {
+		return false, fmt.Errorf("%s %d: already being handled", event.Type, event.Seq)
+	}
+	w.inFlight[event.Seq] = struct{}{}
+	return true, nil
+}
+
+// release ends the claim on the event's sequence number, remembering it if the event was handled
+func (r *Receiver) release(event *Event, handled bool) {
+	if event.Type == EventSinkStarted || !event.hasSeq {
+		return
+	}
+	r.seenMu.Lock()
+	defer r.seenMu.Unlock()
+	w, ok := r.seen[event.Node]
+	if !ok {
+		return // the node restarted while the event was handled
+	}
+	delete(w.inFlight, event.Seq)
+	if handled {
+		w.add(event.Seq)
+	}
+}
+
+// window remembers the most recent sequence numbers
+type window struct {
+	seqs     map[int64]struct{}
+	ring     []int64
+	next     int
+	inFlight map[int64]struct{} // claimed by events being handled
+}
+
+func newWindow(size int) *window {
+	return &window{seqs: make(map[int64]struct{}, size), ring: make([]int64, 0, size), inFlight: make(map[int64]struct{})}
+}
+
+func (w *window) contains(seq int64) bool {
+	_, ok := w.seqs[seq]
+	return ok
+}
+
+func (w *window) add(seq int64) {
+	if w.contains(seq) || cap(w.ring) == 0 {
+		return
+	}
+	if len(w.ring) < cap(w.ring) {
+		w.ring = append(w.ring, seq)
+	} else {
+		delete(w.seqs, w.ring[w.next])
+		w.ring[w.next] = seq
+		w.next = (w.next + 1) % len(w.ring)
+	}
+	w.seqs[seq] = struct{}{}
+}
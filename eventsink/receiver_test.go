@@ -0,0 +1,211 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package eventsink
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const participantConnected = `{
+	"event": "participant_connected",
+	"node": "10.0.0.1",
+	"seq": 7,
+	"version": 1,
+	"time": 1700000000.5,
+	"data": {
+		"uuid": "p-1",
+		"conference": "Sales",
+		"display_name": "Alice",
+		"role": "chair",
+		"protocol": "WebRTC",
+		"connect_time": 1700000000.25,
+		"is_muted": true
+	}
+}`
+
+func post(t *testing.T, handler http.Handler, body string, auth bool) int {
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	if auth {
+		req.SetBasicAuth("sink", "secret")
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestEvent_UnmarshalJSON(t *testing.T) {
+	events, err := decode([]byte(participantConnected))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	event := events[0]
+	assert.Equal(t, ParticipantConnected, event.Type)
+	assert.Equal(t, "10.0.0.1", event.Node)
+	assert.Equal(t, int64(7), event.Seq)
+	assert.Equal(t, time.Unix(1700000000, 5e8).UTC(), event.Time)
+	require.NotNil(t, event.Participant)
+	assert.Nil(t, event.Conference)
+	assert.Equal(t, "Alice", event.Participant.DisplayName)
+	assert.True(t, event.Participant.IsMuted)
+	assert.Equal(t, time.Unix(1700000000, 25e7).UTC(), event.Participant.ConnectTime.Time)
+
+	events, err = decode([]byte(`[{"event":"conference_started","node":"10.0.0.1","seq":1,"data":{"name":"Sales","is_locked":true}}]`))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Sales", events[0].Conference.Name)
+	assert.True(t, events[0].Conference.IsLocked)
+
+	// Media stream events have data of a different shape, kept raw
+	events, err = decode([]byte(`{"event":"participant_media_stream_window","node":"10.0.0.1","seq":2,"data":{"uuid":"p-1","role":[{"stream_id":"1"}]}}`))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Nil(t, events[0].Participant)
+	assert.JSONEq(t, `{"uuid":"p-1","role":[{"stream_id":"1"}]}`, string(events[0].Data))
+}
+
+func TestReceiver_NegativeDedupWindow(t *testing.T) {
+	receiver := NewReceiver(WithDedupWindow(-1))
+	calls := 0
+	receiver.HandleAll(func(ctx context.Context, event *Event) error {
+		calls++
+		return nil
+	})
+
+	assert.Equal(t, http.StatusOK, post(t, receiver, participantConnected, false))
+	assert.Equal(t, http.StatusOK, post(t, receiver, participantConnected, false))
+	assert.Equal(t, 2, calls, "deduplication is disabled")
+}
+
+func TestReceiver_ServeHTTP(t *testing.T) {
+	ch := make(chan *Event, 10)
+	receiver := NewReceiver(WithBasicAuth("sink", "secret"), WithChannel(ch))
+	var connected, all int
+	receiver.Handle(ParticipantConnected, func(ctx context.Context, event *Event) error {
+		connected++
+		return nil
+	})
+	receiver.HandleAll(func(ctx context.Context, event *Event) error {
+		all++
+		return nil
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, post(t, receiver, participantConnected, false))
+	assert.Equal(t, http.StatusOK, post(t, receiver, participantConnected, true))
+	// A resent event is acknowledged but not dispatched again
+	assert.Equal(t, http.StatusOK, post(t, receiver, participantConnected, true))
+
+	bulk := `[
+		{"event":"conference_started","node":"10.0.0.1","seq":8,"data":{"name":"Sales"}},
+		{"event":"conference_started","node":"10.0.0.2","seq":7,"data":{"name":"Support"}}
+	]`
+	assert.Equal(t, http.StatusOK, post(t, receiver, bulk, true))
+
+	assert.Equal(t, 1, connected)
+	assert.Equal(t, 3, all)
+	require.Len(t, ch, 3)
+	assert.Equal(t, ParticipantConnected, (<-ch).Type)
+
+	assert.Equal(t, http.StatusBadRequest, post(t, receiver, "not json", true))
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	rec := httptest.NewRecorder()
+	receiver.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestReceiver_HandlerError(t *testing.T) {
+	receiver := NewReceiver()
+	fail := true
+	calls := 0
+	receiver.Handle(ParticipantConnected, func(ctx context.Context, event *Event) error {
+		calls++
+		if fail {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+
+	assert.Equal(t, http.StatusServiceUnavailable, post(t, receiver, participantConnected, false))
+	// The event is dispatched again when it is resent
+	fail = false
+	assert.Equal(t, http.StatusOK, post(t, receiver, participantConnected, false))
+	assert.Equal(t, 2, calls)
+}
+
+func TestReceiver_InFlight(t *testing.T) {
+	receiver := NewReceiver()
+	started := make(chan struct{})
+	finish := make(chan error)
+	var calls atomic.Int32
+	receiver.Handle(ParticipantConnected, func(ctx context.Context, event *Event) error {
+		if calls.Add(1) == 1 {
+			close(started)
+			return <-finish
+		}
+		return nil
+	})
+
+	first := make(chan int)
+	go func() { first <- post(t, receiver, participantConnected, false) }()
+	<-started
+	// The same event resent while the first delivery is handled is neither dispatched nor acknowledged
+	assert.Equal(t, http.StatusServiceUnavailable, post(t, receiver, participantConnected, false))
+	finish <- errors.New("database unavailable")
+	assert.Equal(t, http.StatusServiceUnavailable, <-first)
+
+	// The failed delivery released its claim
+	assert.Equal(t, http.StatusOK, post(t, receiver, participantConnected, false))
+	assert.Equal(t, http.StatusOK, post(t, receiver, participantConnected, false))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestReceiver_NoSeq(t *testing.T) {
+	receiver := NewReceiver()
+	var names []string
+	receiver.Handle(ConferenceStarted, func(ctx context.Context, event *Event) error {
+		names = append(names, event.Conference.Name)
+		return nil
+	})
+
+	assert.Equal(t, http.StatusOK, post(t, receiver, `{"event":"conference_started","node":"10.0.0.1","data":{"name":"Sales"}}`, false))
+	assert.Equal(t, http.StatusOK, post(t, receiver, `{"event":"conference_started","node":"10.0.0.1","data":{"name":"Support"}}`, false))
+	assert.Equal(t, []string{"Sales", "Support"}, names)
+}
+
+func TestReceiver_SinkRestart(t *testing.T) {
+	receiver := NewReceiver()
+	calls := 0
+	receiver.HandleAll(func(ctx context.Context, event *Event) error {
+		calls++
+		return nil
+	})
+
+	assert.Equal(t, http.StatusOK, post(t, receiver, participantConnected, false))
+	assert.Equal(t, http.StatusOK, post(t, receiver, `{"event":"eventsink_started","node":"10.0.0.1","seq":0}`, false))
+	// Sequence numbers start again after the sink restarts
+	assert.Equal(t, http.StatusOK, post(t, receiver, participantConnected, false))
+	assert.Equal(t, 3, calls)
+}
+
+func TestWindow(t *testing.T) {
+	w := newWindow(2)
+	w.add(1)
+	w.add(2)
+	w.add(3)
+	assert.False(t, w.contains(1))
+	assert.True(t, w.contains(2))
+	assert.True(t, w.contains(3))
+}
@@ -0,0 +1,273 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package exporter
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	infinity "github.com/pexip/go-infinity-sdk/v41"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	upDesc          = newDesc("", "up", "Whether the management node answered the system status request.")
+	systemInfoDesc  = newDesc("system", "info", "Version and status of the management node.", "version", "hostname", "status")
+	uptimeDesc      = newDesc("system", "uptime_seconds", "Uptime of the management node.")
+	memoryTotalDesc = newDesc("system", "memory_total_bytes", "Total memory of the management node.")
+	memoryUsedDesc  = newDesc("system", "memory_used_bytes", "Memory used on the management node.")
+	cpuLoadDesc     = newDesc("system", "cpu_load", "CPU load of the management node.")
+	systemCollector = collector{
+		name:  "system",
+		descs: []*prometheus.Desc{upDesc, systemInfoDesc, uptimeDesc, memoryTotalDesc, memoryUsedDesc, cpuLoadDesc},
+		collect: func(ctx context.Context, client *infinity.Client) ([]prometheus.Metric, error) {
+			s, err := client.Status().GetSystemStatus(ctx)
+			if err != nil {
+				// up is still reported so that an unreachable node can be alerted on
+				return []prometheus.Metric{gauge(upDesc, 0)}, err
+			}
+			return []prometheus.Metric{
+				gauge(upDesc, 1),
+				gauge(systemInfoDesc, 1, s.Version, s.HostName, s.Status),
+				gauge(uptimeDesc, float64(s.Uptime)),
+				gauge(memoryTotalDesc, float64(s.TotalMemory)),
+				gauge(memoryUsedDesc, float64(s.UsedMemory)),
+				gauge(cpuLoadDesc, s.CPULoad),
+			}, nil
+		},
+	}
+)
+
+var (
+	workerLabels            = []string{"node", "location"}
+	workerInfoDesc          = newDesc("worker", "info", "Version and type of a conferencing node.", "node", "location", "version", "node_type", "deploy_status")
+	workerMediaLoadDesc     = newDesc("worker", "media_load", "Media load of a conferencing node as a percentage.", workerLabels...)
+	workerTokensUsedDesc    = newDesc("worker", "media_tokens_used", "Media tokens in use on a conferencing node.", workerLabels...)
+	workerTokensMaxDesc     = newDesc("worker", "media_tokens_max", "Media tokens available on a conferencing node.", workerLabels...)
+	workerSignalingDesc     = newDesc("worker", "signaling_sessions", "Signaling sessions on a conferencing node.", workerLabels...)
+	workerMaintenanceDesc   = newDesc("worker", "maintenance_mode", "Whether a conferencing node is in maintenance mode.", workerLabels...)
+	workerSyncStatusDesc    = newDesc("worker", "sync_status", "Configuration sync status of a conferencing node, 1 for the current status.", "node", "location", "status")
+	workerUpgradeStatusDesc = newDesc("worker", "upgrade_status", "Upgrade status of a conferencing node, 1 for the current status.", "node", "location", "status")
+	workerCollector         = collector{
+		name: "worker",
+		descs: []*prometheus.Desc{
+			workerInfoDesc, workerMediaLoadDesc, workerTokensUsedDesc, workerTokensMaxDesc,
+			workerSignalingDesc, workerMaintenanceDesc, workerSyncStatusDesc, workerUpgradeStatusDesc,
+		},
+		collect: func(ctx context.Context, client *infinity.Client) ([]prometheus.Metric, error) {
+			var metrics []prometheus.Metric
+			seen := make(labelSets)
+			for w, err := range client.Status().AllWorkerVMs(ctx, nil) {
+				if err != nil {
+					return nil, err
+				}
+				if !seen.add(w.Name, w.SystemLocation) {
+					continue
+				}
+				metrics = append(metrics,
+					gauge(workerInfoDesc, 1, w.Name, w.SystemLocation, w.Version, w.NodeType, w.DeployStatus),
+					gauge(workerMediaLoadDesc, float64(w.MediaLoad), w.Name, w.SystemLocation),
+					gauge(workerTokensUsedDesc, float64(w.MediaTokensUsed), w.Name, w.SystemLocation),
+					gauge(workerTokensMaxDesc, float64(w.MaxMediaTokens), w.Name, w.SystemLocation),
+					gauge(workerSignalingDesc, float64(w.SignalingCount), w.Name, w.SystemLocation),
+					gauge(workerMaintenanceDesc, boolValue(w.MaintenanceMode), w.Name, w.SystemLocation),
+					gauge(workerSyncStatusDesc, 1, w.Name, w.SystemLocation, w.SyncStatus),
+					gauge(workerUpgradeStatusDesc, 1, w.Name, w.SystemLocation, w.UpgradeStatus),
+				)
+			}
+			return metrics, nil
+		},
+	}
+)
+
+var (
+	locationMediaLoadDesc  = newDesc("location", "media_load", "Media load of a system location as a percentage.", "location")
+	locationTokensUsedDesc = newDesc("location", "media_tokens_used", "Media tokens in use in a system location.", "location")
+	locationTokensMaxDesc  = newDesc("location", "media_tokens_max", "Media tokens available in a system location.", "location")
+	locationMaxCallsDesc   = newDesc("location", "max_calls", "Calls a system location can hold, by call type.", "location", "call_type")
+	locationCollector      = collector{
+		name:  "location",
+		descs: []*prometheus.Desc{locationMediaLoadDesc, locationTokensUsedDesc, locationTokensMaxDesc, locationMaxCallsDesc},
+		collect: func(ctx context.Context, client *infinity.Client) ([]prometheus.Metric, error) {
+			var metrics []prometheus.Metric
+			for l, err := range client.Status().AllSystemLocations(ctx, nil) {
+				if err != nil {
+					return nil, err
+				}
+				metrics = append(metrics,
+					gauge(locationMediaLoadDesc, l.MediaLoad, l.Name),
+					gauge(locationTokensUsedDesc, float64(l.MediaTokensUsed), l.Name),
+					gauge(locationTokensMaxDesc, float64(l.MaxMediaTokens), l.Name),
+					gauge(locationMaxCallsDesc, float64(l.MaxAudioCalls), l.Name, "audio"),
+					gauge(locationMaxCallsDesc, float64(l.MaxSDCalls), l.Name, "sd"),
+					gauge(locationMaxCallsDesc, float64(l.MaxHDCalls), l.Name, "hd"),
+					gauge(locationMaxCallsDesc, float64(l.MaxFullHDCalls), l.Name, "full_hd"),
+				)
+			}
+			return metrics, nil
+		},
+	}
+)
+
+var (
+	licenseUsedDesc    = newDesc("license", "used", "Licenses in use, by license type.", "license")
+	licenseTotalDesc   = newDesc("license", "installed", "Licenses installed, by license type.", "license")
+	customLayoutsDesc  = newDesc("license", "custom_layouts_active", "Whether the custom layouts license is active.")
+	licensingCollector = collector{
+		name:  "licensing",
+		descs: []*prometheus.Desc{licenseUsedDesc, licenseTotalDesc, customLayoutsDesc},
+		collect: func(ctx context.Context, client *infinity.Client) ([]prometheus.Metric, error) {
+			l, err := client.Status().GetLicensing(ctx)
+			if err != nil {
+				return nil, err
+			}
+			licenses := []struct {
+				name        string
+				used, total int
+			}{
+				{"port", l.PortCount, l.PortTotal},
+				{"audio", l.AudioCount, l.AudioTotal},
+				{"system", l.SystemCount, l.SystemTotal},
+				{"vmr", l.VMRCount, l.VMRTotal},
+				{"teams", l.TeamsCount, l.TeamsTotal},
+				{"ghm", l.GHMCount, l.GHMTotal},
+				{"otj", l.OTJCount, l.OTJTotal},
+				{"scheduling", l.SchedulingCount, l.SchedulingTotal},
+				{"telehealth", l.TelehealthCount, l.TelehealthTotal},
+			}
+			metrics := []prometheus.Metric{gauge(customLayoutsDesc, boolValue(l.CustomLayoutsActive))}
+			for _, lic := range licenses {
+				metrics = append(metrics,
+					gauge(licenseUsedDesc, float64(lic.used), lic.name),
+					gauge(licenseTotalDesc, float64(lic.total), lic.name),
+				)
+			}
+			return metrics, nil
+		},
+	}
+)
+
+var (
+	alarmsDesc     = newDesc("", "alarms", "Raised alarms, by level, name and node.", "level", "name", "node", "acknowledged")
+	alarmCollector = collector{
+		name:  "alarm",
+		descs: []*prometheus.Desc{alarmsDesc},
+		collect: func(ctx context.Context, client *infinity.Client) ([]prometheus.Metric, error) {
+			type key struct{ level, name, node, acknowledged string }
+			counts := make(map[key]int)
+			var order []key
+			for a, err := range client.Status().AllAlarms(ctx, nil) {
+				if err != nil {
+					return nil, err
+				}
+				k := key{a.Level, a.Name, a.Node, strconv.FormatBool(a.Acknowledged)}
+				if counts[k] == 0 {
+					order = append(order, k)
+				}
+				counts[k]++
+			}
+			metrics := make([]prometheus.Metric, 0, len(order))
+			for _, k := range order {
+				metrics = append(metrics, gauge(alarmsDesc, float64(counts[k]), k.level, k.name, k.node, k.acknowledged))
+			}
+			return metrics, nil
+		},
+	}
+)
+
+var (
+	teamsNodeInfoDesc      = newDesc("teams_node", "info", "State of a Teams Connector node.", "node", "scaleset", "state", "instance_status")
+	teamsNodeCallsDesc     = newDesc("teams_node", "calls", "Calls on a Teams Connector node.", "node", "scaleset")
+	teamsNodeMaxCallsDesc  = newDesc("teams_node", "max_calls", "Calls a Teams Connector node can hold.", "node", "scaleset")
+	teamsNodeMediaLoadDesc = newDesc("teams_node", "media_load", "Media load of a Teams Connector node as a percentage.", "node", "scaleset")
+	teamsNodeCollector     = collector{
+		name:  "teams_node",
+		descs: []*prometheus.Desc{teamsNodeInfoDesc, teamsNodeCallsDesc, teamsNodeMaxCallsDesc, teamsNodeMediaLoadDesc},
+		collect: func(ctx context.Context, client *infinity.Client) ([]prometheus.Metric, error) {
+			var metrics []prometheus.Metric
+			seen := make(labelSets)
+			for n, err := range client.Status().AllTeamsNodes(ctx, nil) {
+				if err != nil {
+					return nil, err
+				}
+				if !seen.add(n.Name, n.ScalesetID) {
+					continue
+				}
+				metrics = append(metrics,
+					gauge(teamsNodeInfoDesc, 1, n.Name, n.ScalesetID, deref(n.State), deref(n.InstanceStatus)),
+					gauge(teamsNodeCallsDesc, float64(n.CallCount), n.Name, n.ScalesetID),
+					gauge(teamsNodeMediaLoadDesc, float64(n.MediaLoad), n.Name, n.ScalesetID),
+				)
+				if n.MaxCalls != nil {
+					metrics = append(metrics, gauge(teamsNodeMaxCallsDesc, float64(*n.MaxCalls), n.Name, n.ScalesetID))
+				}
+			}
+			return metrics, nil
+		},
+	}
+)
+
+var (
+	cloudNodeStateDesc      = newDesc("cloud_node", "state", "State of a dynamic bursting cloud node, 1 for the current state.", "node", "location", "state")
+	cloudNodeMediaLoadDesc  = newDesc("cloud_node", "media_load", "Media load of a dynamic bursting cloud node as a percentage.", "node", "location")
+	cloudNodeMaxHDCallsDesc = newDesc("cloud_node", "max_hd_calls", "HD calls a dynamic bursting cloud node can hold.", "node", "location")
+	cloudNodeCollector      = collector{
+		name:  "cloud_node",
+		descs: []*prometheus.Desc{cloudNodeStateDesc, cloudNodeMediaLoadDesc, cloudNodeMaxHDCallsDesc},
+		collect: func(ctx context.Context, client *infinity.Client) ([]prometheus.Metric, error) {
+			var metrics []prometheus.Metric
+			seen := make(labelSets)
+			for n, err := range client.Status().AllCloudNodes(ctx, nil) {
+				if err != nil {
+					return nil, err
+				}
+				name, id, state := n.CloudInstanceName, n.CloudInstanceID, n.CloudInstanceState
+				if name == "" && id == "" {
+					name, id, state = n.AWSInstanceName, n.AWSInstanceID, n.AWSInstanceState
+				}
+				if name == "" {
+					// Instances that are starting up may not have a name yet
+					name = id
+				}
+				location := deref(n.WorkerVMConfigurationLocationName)
+				if !seen.add(name, location) {
+					continue
+				}
+				metrics = append(metrics, gauge(cloudNodeStateDesc, 1, name, location, state))
+				if n.MediaLoad != nil {
+					metrics = append(metrics, gauge(cloudNodeMediaLoadDesc, float64(*n.MediaLoad), name, location))
+				}
+				if n.MaxHDCalls != nil {
+					metrics = append(metrics, gauge(cloudNodeMaxHDCallsDesc, float64(*n.MaxHDCalls), name, location))
+				}
+			}
+			return metrics, nil
+		},
+	}
+)
+
+// labelSets records the label sets a collector has reported, so that nodes reported twice, or that can only
+// be told apart by fields that are not labels, do not produce metrics that Prometheus rejects as duplicates
+type labelSets map[string]bool
+
+// add reports whether labels had not been seen before
+func (s labelSets) add(labels ...string) bool {
+	key := strings.Join(labels, "\xff")
+	if s[key] {
+		return false
+	}
+	s[key] = true
+	return true
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,181 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package exporter exposes the status of a Pexip Infinity deployment as Prometheus metrics. An Exporter
+// is a prometheus.Collector that reads the platform status API on every scrape, calling the status
+// endpoints concurrently with a bound on parallelism and an overall scrape timeout.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	infinity "github.com/pexip/go-infinity-sdk/v41"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultTimeout is the default time allowed for a scrape
+	DefaultTimeout = 10 * time.Second
+	// DefaultConcurrency is the default number of status endpoints called at the same time
+	DefaultConcurrency = 4
+)
+
+const namespace = "infinity"
+
+var (
+	scrapeSuccessDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "scrape", "collector_success"),
+		"Whether the status endpoints of a collector were read successfully.",
+		[]string{"collector"}, nil,
+	)
+	scrapeDurationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "scrape", "collector_duration_seconds"),
+		"Time taken to read the status endpoints of a collector.",
+		[]string{"collector"}, nil,
+	)
+)
+
+// collector reads one area of the status API and returns its metrics
+type collector struct {
+	name    string
+	descs   []*prometheus.Desc
+	collect func(ctx context.Context, client *infinity.Client) ([]prometheus.Metric, error)
+}
+
+// Exporter is a prometheus.Collector for the status of an Infinity deployment
+type Exporter struct {
+	client      *infinity.Client
+	timeout     time.Duration
+	concurrency int
+	logger      *slog.Logger
+	collectors  []collector
+}
+
+// Option configures an Exporter
+type Option func(*Exporter) error
+
+// WithTimeout sets the time allowed for a scrape. Collectors that have not finished when it expires are
+// reported as failed.
+func WithTimeout(timeout time.Duration) Option {
+	return func(e *Exporter) error {
+		if timeout <= 0 {
+			return fmt.Errorf("timeout must be positive")
+		}
+		e.timeout = timeout
+		return nil
+	}
+}
+
+// WithConcurrency sets how many status endpoints are called at the same time during a scrape
+func WithConcurrency(n int) Option {
+	return func(e *Exporter) error {
+		if n <= 0 {
+			return fmt.Errorf("concurrency must be positive")
+		}
+		e.concurrency = n
+		return nil
+	}
+}
+
+// WithLogger sets the logger failed collectors are reported to
+func WithLogger(logger *slog.Logger) Option {
+	return func(e *Exporter) error {
+		if logger == nil {
+			return fmt.Errorf("logger cannot be nil")
+		}
+		e.logger = logger
+		return nil
+	}
+}
+
+// New creates an Exporter that reads status with client
+func New(client *infinity.Client, opts ...Option) (*Exporter, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client cannot be nil")
+	}
+	e := &Exporter{
+		client:      client,
+		timeout:     DefaultTimeout,
+		concurrency: DefaultConcurrency,
+		collectors: []collector{
+			systemCollector,
+			workerCollector,
+			locationCollector,
+			licensingCollector,
+			alarmCollector,
+			teamsNodeCollector,
+			cloudNodeCollector,
+		},
+	}
+	for _, opt := range opts {
+		if err := opt(e); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// Describe implements prometheus.Collector
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- scrapeSuccessDesc
+	ch <- scrapeDurationDesc
+	for _, c := range e.collectors {
+		for _, desc := range c.descs {
+			ch <- desc
+		}
+	}
+}
+
+// Collect implements prometheus.Collector. Collectors return no metrics when one of their endpoints
+// fails, so a failed scrape never exposes partial data.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	sem := make(chan struct{}, e.concurrency)
+	var wg sync.WaitGroup
+	for _, c := range e.collectors {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			start := time.Now()
+			metrics, err := c.collect(ctx, e.client)
+			success := 1.0
+			if err != nil {
+				success = 0
+				if e.logger != nil {
+					e.logger.Warn("failed to collect status", slog.String("collector", c.name), slog.String("error", err.Error()))
+				}
+			}
+			for _, m := range metrics {
+				ch <- m
+			}
+			ch <- gauge(scrapeSuccessDesc, success, c.name)
+			ch <- gauge(scrapeDurationDesc, time.Since(start).Seconds(), c.name)
+		})
+	}
+	wg.Wait()
+}
+
+func newDesc(subsystem, name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, name), help, labels, nil)
+}
+
+func gauge(desc *prometheus.Desc, value float64, labels ...string) prometheus.Metric {
+	return prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,230 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package exporter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	infinity "github.com/pexip/go-infinity-sdk/v41"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func list(objects ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"meta":    map[string]interface{}{"limit": 100, "offset": 0, "total_count": len(objects)},
+		"objects": objects,
+	}
+}
+
+var responses = map[string]interface{}{
+	"status/v1/system_status/": map[string]interface{}{
+		"status": "ok", "version": "38.1", "hostname": "mgr", "uptime": 3600,
+		"total_memory": 8000, "used_memory": 2000, "cpu_load": 0.5,
+	},
+	"status/v1/worker_vm/": list(map[string]interface{}{
+		"name": "cn1", "system_location": "Oslo", "version": "38.1", "node_type": "CONFERENCING", "deploy_status": "DEPLOYED",
+		"media_load": 40, "media_tokens_used": 20, "max_media_tokens": 50, "signaling_count": 3,
+		"maintenance_mode": true, "sync_status": "SYNCED", "upgrade_status": "IDLE",
+	}),
+	"status/v1/system_location/": list(map[string]interface{}{
+		"name": "Oslo", "media_load": 40.0, "media_tokens_used": 20, "max_media_tokens": 50, "max_hd_calls": 10,
+	}),
+	"status/v1/licensing/": list(map[string]interface{}{"port_count": 7, "port_total": 100, "customlayouts_active": true}),
+	"status/v1/alarm/": list(
+		map[string]interface{}{"level": "error", "name": "cpu", "node": "cn1"},
+		map[string]interface{}{"level": "error", "name": "cpu", "node": "cn1"},
+		map[string]interface{}{"level": "warning", "name": "disk", "node": "cn1", "acknowledged": true},
+	),
+	"status/v1/teamsnode/":  list(map[string]interface{}{"name": "tn1", "scaleset_id": "ss1", "call_count": 4, "media_load": 10, "max_calls": 16, "state": "running"}),
+	"status/v1/cloud_node/": list(map[string]interface{}{"cloud_instance_name": "burst1", "cloud_instance_state": "running", "media_load": 5, "workervm_configuration_location_name": "Cloud"}),
+}
+
+func newServer(t *testing.T, fail string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := strings.TrimPrefix(r.URL.Path, "/api/admin/")
+		body, ok := responses[endpoint]
+		if !ok || endpoint == fail {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newExporter(t *testing.T, server *httptest.Server, opts ...Option) *Exporter {
+	t.Helper()
+	client, err := infinity.New(infinity.WithBaseURL(server.URL), infinity.WithNoRetries())
+	require.NoError(t, err)
+	exp, err := New(client, opts...)
+	require.NoError(t, err)
+	return exp
+}
+
+func TestExporter_Collect(t *testing.T) {
+	exp := newExporter(t, newServer(t, ""))
+
+	expected := `
+# HELP infinity_up Whether the management node answered the system status request.
+# TYPE infinity_up gauge
+infinity_up 1
+# HELP infinity_worker_media_tokens_used Media tokens in use on a conferencing node.
+# TYPE infinity_worker_media_tokens_used gauge
+infinity_worker_media_tokens_used{location="Oslo",node="cn1"} 20
+# HELP infinity_worker_maintenance_mode Whether a conferencing node is in maintenance mode.
+# TYPE infinity_worker_maintenance_mode gauge
+infinity_worker_maintenance_mode{location="Oslo",node="cn1"} 1
+# HELP infinity_worker_sync_status Configuration sync status of a conferencing node, 1 for the current status.
+# TYPE infinity_worker_sync_status gauge
+infinity_worker_sync_status{location="Oslo",node="cn1",status="SYNCED"} 1
+# HELP infinity_alarms Raised alarms, by level, name and node.
+# TYPE infinity_alarms gauge
+infinity_alarms{acknowledged="false",level="error",name="cpu",node="cn1"} 2
+infinity_alarms{acknowledged="true",level="warning",name="disk",node="cn1"} 1
+# HELP infinity_teams_node_max_calls Calls a Teams Connector node can hold.
+# TYPE infinity_teams_node_max_calls gauge
+infinity_teams_node_max_calls{node="tn1",scaleset="ss1"} 16
+# HELP infinity_cloud_node_state State of a dynamic bursting cloud node, 1 for the current state.
+# TYPE infinity_cloud_node_state gauge
+infinity_cloud_node_state{location="Cloud",node="burst1",state="running"} 1
+`
+	err := testutil.CollectAndCompare(exp, strings.NewReader(expected),
+		"infinity_up", "infinity_worker_media_tokens_used", "infinity_worker_maintenance_mode",
+		"infinity_worker_sync_status", "infinity_alarms", "infinity_teams_node_max_calls", "infinity_cloud_node_state")
+	require.NoError(t, err)
+
+	licenses := `
+# HELP infinity_license_used Licenses in use, by license type.
+# TYPE infinity_license_used gauge
+infinity_license_used{license="audio"} 0
+infinity_license_used{license="ghm"} 0
+infinity_license_used{license="otj"} 0
+infinity_license_used{license="port"} 7
+infinity_license_used{license="scheduling"} 0
+infinity_license_used{license="system"} 0
+infinity_license_used{license="teams"} 0
+infinity_license_used{license="telehealth"} 0
+infinity_license_used{license="vmr"} 0
+`
+	require.NoError(t, testutil.CollectAndCompare(exp, strings.NewReader(licenses), "infinity_license_used"))
+
+	problems, err := testutil.CollectAndLint(exp)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+}
+
+func TestExporter_DuplicateLabels(t *testing.T) {
+	for endpoint, body := range map[string]interface{}{
+		"status/v1/worker_vm/": list(
+			map[string]interface{}{"name": "cn1", "system_location": "Oslo", "media_load": 40},
+			map[string]interface{}{"name": "cn1", "system_location": "Oslo", "media_load": 50},
+		),
+		"status/v1/teamsnode/": list(
+			map[string]interface{}{"name": "tn1", "scaleset_id": "ss1", "call_count": 4},
+			map[string]interface{}{"name": "tn1", "scaleset_id": "ss1", "call_count": 5},
+		),
+		"status/v1/cloud_node/": list(
+			map[string]interface{}{"cloud_instance_id": "i-1", "cloud_instance_state": "pending", "workervm_configuration_location_name": "Cloud"},
+			map[string]interface{}{"cloud_instance_id": "i-2", "cloud_instance_state": "pending", "workervm_configuration_location_name": "Cloud"},
+			map[string]interface{}{"aws_instance_id": "i-3", "aws_instance_state": "running"},
+		),
+	} {
+		previous := responses[endpoint]
+		responses[endpoint] = body
+		t.Cleanup(func() { responses[endpoint] = previous })
+	}
+	exp := newExporter(t, newServer(t, ""))
+
+	expected := `
+# HELP infinity_worker_media_load Media load of a conferencing node as a percentage.
+# TYPE infinity_worker_media_load gauge
+infinity_worker_media_load{location="Oslo",node="cn1"} 40
+# HELP infinity_teams_node_calls Calls on a Teams Connector node.
+# TYPE infinity_teams_node_calls gauge
+infinity_teams_node_calls{node="tn1",scaleset="ss1"} 4
+# HELP infinity_cloud_node_state State of a dynamic bursting cloud node, 1 for the current state.
+# TYPE infinity_cloud_node_state gauge
+infinity_cloud_node_state{location="Cloud",node="i-1",state="pending"} 1
+infinity_cloud_node_state{location="Cloud",node="i-2",state="pending"} 1
+infinity_cloud_node_state{location="",node="i-3",state="running"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(exp, strings.NewReader(expected), "infinity_worker_media_load", "infinity_teams_node_calls", "infinity_cloud_node_state"))
+}
+
+func TestExporter_CollectorFailure(t *testing.T) {
+	exp := newExporter(t, newServer(t, "status/v1/worker_vm/"))
+
+	assert.Equal(t, 0, testutil.CollectAndCount(exp, "infinity_worker_media_load"))
+	assert.Equal(t, 1, testutil.CollectAndCount(exp, "infinity_location_media_load"))
+
+	expected := `
+# HELP infinity_scrape_collector_success Whether the status endpoints of a collector were read successfully.
+# TYPE infinity_scrape_collector_success gauge
+infinity_scrape_collector_success{collector="alarm"} 1
+infinity_scrape_collector_success{collector="cloud_node"} 1
+infinity_scrape_collector_success{collector="licensing"} 1
+infinity_scrape_collector_success{collector="location"} 1
+infinity_scrape_collector_success{collector="system"} 1
+infinity_scrape_collector_success{collector="teams_node"} 1
+infinity_scrape_collector_success{collector="worker"} 0
+`
+	require.NoError(t, testutil.CollectAndCompare(exp, strings.NewReader(expected), "infinity_scrape_collector_success"))
+}
+
+func TestExporter_Down(t *testing.T) {
+	exp := newExporter(t, newServer(t, "status/v1/system_status/"))
+
+	expected := `
+# HELP infinity_up Whether the management node answered the system status request.
+# TYPE infinity_up gauge
+infinity_up 0
+`
+	require.NoError(t, testutil.CollectAndCompare(exp, strings.NewReader(expected), "infinity_up"))
+	assert.Equal(t, 0, testutil.CollectAndCount(exp, "infinity_system_info"))
+}
+
+func TestExporter_Timeout(t *testing.T) {
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(block) })
+
+	exp := newExporter(t, server, WithTimeout(50*time.Millisecond), WithConcurrency(2))
+
+	start := time.Now()
+	assert.Equal(t, 7, testutil.CollectAndCount(exp, "infinity_scrape_collector_success"))
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestNew_Errors(t *testing.T) {
+	_, err := New(nil)
+	assert.Error(t, err)
+
+	client, err := infinity.New(infinity.WithBaseURL("https://example.com"))
+	require.NoError(t, err)
+	_, err = New(client, WithConcurrency(0))
+	assert.Error(t, err)
+	_, err = New(client, WithTimeout(0))
+	assert.Error(t, err)
+	_, err = New(client, WithLogger(nil))
+	assert.Error(t, err)
+}
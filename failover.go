@@ -0,0 +1,177 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package infinity
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DefaultNodeCooldown is how long a management node that failed is avoided before it is tried again
+const DefaultNodeCooldown = 30 * time.Second
+
+// NodeStatus reports the health of one management node configured with WithBaseURLs
+type NodeStatus struct {
+	URL         string
+	Healthy     bool
+	LastError   string
+	LastChecked time.Time
+	// Circuit is the state of the node's circuit breaker, always closed if none is configured
+	Circuit CircuitState
+}
+
+// node is one management node address
+type node struct {
+	url     *url.URL
+	breaker *circuitBreaker // set by WithCircuitBreaker
+
+	mu          sync.Mutex
+	downUntil   time.Time
+	lastError   string
+	lastChecked time.Time
+}
+
+func (n *node) healthy(now time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return !now.Before(n.downUntil)
+}
+
+func (n *node) markUp(now time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.downUntil = time.Time{}
+	n.lastError = ""
+	n.lastChecked = now
+}
+
+func (n *node) markDown(now time.Time, cooldown time.Duration, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.downUntil = now.Add(cooldown)
+	n.lastError = err.Error()
+	n.lastChecked = now
+}
+
+func (n *node) status(now time.Time) NodeStatus {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return NodeStatus{
+		URL:         n.url.String(),
+		Healthy:     !now.Before(n.downUntil),
+		LastError:   n.lastError,
+		LastChecked: n.lastChecked,
+		Circuit:     n.breaker.currentState(),
+	}
+}
+
+// nodeSet routes requests to the first healthy management node, in the order they were configured
+type nodeSet struct {
+	nodes    []*node
+	cooldown time.Duration
+	now      func() time.Time
+}
+
+func newNodeSet(urls []*url.URL) *nodeSet {
+	s := &nodeSet{cooldown: DefaultNodeCooldown, now: time.Now}
+	for _, u := range urls {
+		s.nodes = append(s.nodes, &node{url: u})
+	}
+	return s
+}
+
+// pick returns the first healthy node that has not been tried, or the first untried node if none are healthy.
+// It returns nil once every node has been tried.
+func (s *nodeSet) pick(tried map[*node]bool) *node {
+	now := s.now()
+	var fallback *node
+	for _, n := range s.nodes {
+		if tried[n] {
+			continue
+		}
+		if n.healthy(now) {
+			return n
+		}
+		if fallback == nil {
+			fallback = n
+		}
+	}
+	return fallback
+}
+
+// notSent reports whether err means the request never left the client, such as a refused connection or
+// a failed DNS lookup, so that it can be sent to another node without being processed twice
+func notSent(err error) bool {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	return errors.As(err, &dnsErr) || errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+type pinnedNodeKey struct{}
+
+// checkNode probes a node with a system status request. The request is sent once, bypassing the node's
+// circuit breaker, which is closed again if the node passes.
+func (c *Client) checkNode(ctx context.Context, n *node) {
+	_, err := c.status.GetSystemStatus(context.WithValue(ctx, pinnedNodeKey{}, n))
+	var apiErr *APIError
+	switch {
+	case ctx.Err() != nil:
+		// Says nothing about the node
+	case err == nil, errors.As(err, &apiErr) && apiErr.StatusCode < 500:
+		n.markUp(c.nodes.now())
+		n.breaker.reset()
+	default:
+		n.markDown(c.nodes.now(), c.nodes.cooldown, err)
+	}
+}
+
+// CheckNodes checks the health of every management node configured with WithBaseURLs using
+// status.GetSystemStatus and returns their status. Nodes that do not respond, or respond with a server
+// error, are avoided until they pass a check or their cooldown expires. Checks are not retried and are
+// sent even while a node's circuit breaker is open; passing one closes the breaker.
+func (c *Client) CheckNodes(ctx context.Context) []NodeStatus {
+	if c.nodes == nil {
+		return nil
+	}
+	var wg sync.WaitGroup
+	for _, n := range c.nodes.nodes {
+		wg.Go(func() { c.checkNode(ctx, n) })
+	}
+	wg.Wait()
+	return c.Nodes()
+}
+
+// WatchNodes checks the health of the management nodes every interval until ctx is cancelled
+func (c *Client) WatchNodes(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.CheckNodes(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Nodes returns the last known status of the management nodes configured with WithBaseURLs
+func (c *Client) Nodes() []NodeStatus {
+	if c.nodes == nil {
+		return nil
+	}
+	now := c.nodes.now()
+	statuses := make([]NodeStatus, 0, len(c.nodes.nodes))
+	for _, n := range c.nodes.nodes {
+		statuses = append(statuses, n.status(now))
+	}
+	return statuses
+}
@@ -0,0 +1,204 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package infinity
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_WithBaseURLs_Failover(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close() // connections are refused
+
+	standby := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer standby.Close()
+
+	client, err := New(WithBaseURLs(down.URL, standby.URL), WithNoRetries())
+	require.NoError(t, err)
+
+	for range 2 {
+		resp, err := client.DoRequest(t.Context(), &Request{Method: http.MethodGet, Endpoint: "status/v1/alarm/"})
+		require.NoError(t, err)
+		assert.Equal(t, standby.URL, resp.ServedBy)
+	}
+
+	nodes := client.Nodes()
+	require.Len(t, nodes, 2)
+	assert.False(t, nodes[0].Healthy)
+	assert.NotEmpty(t, nodes[0].LastError)
+	assert.True(t, nodes[1].Healthy)
+}
+
+func TestClient_WithBaseURLs_FailoverRecordsRetries(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	standby := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer standby.Close()
+
+	client, err := New(WithBaseURLs(down.URL, standby.URL), WithNoRetries())
+	require.NoError(t, err)
+
+	_, err = client.DoRequest(t.Context(), &Request{Method: http.MethodGet, Endpoint: "status/v1/alarm/"})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 2, apiErr.Attempts)
+	require.Len(t, apiErr.Retries, 1)
+	assert.Contains(t, apiErr.Retries[0].Error, "refused")
+}
+
+func TestClient_WithBaseURLs_CommandNotResent(t *testing.T) {
+	// The primary drops the connection after reading the request, which may have been processed
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		conn.Close()
+	}))
+	defer primary.Close()
+	var standbyRequests atomic.Int32
+	standby := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		standbyRequests.Add(1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer standby.Close()
+
+	client, err := New(WithBaseURLs(primary.URL, standby.URL))
+	require.NoError(t, err)
+
+	_, err = client.DoRequest(t.Context(), &Request{
+		Method:   http.MethodPost,
+		Endpoint: "command/v1/participant/disconnect/",
+		Body:     map[string]string{"participant_id": "p1"},
+	})
+	assert.Error(t, err)
+	assert.Zero(t, standbyRequests.Load())
+
+	// Idempotent requests are retried on the standby, as the retry policy allows
+	client, err = New(
+		WithBaseURLs(primary.URL, standby.URL),
+		WithRetryConfig(&RetryConfig{MaxRetries: 1, BackoffMin: time.Millisecond, BackoffMax: time.Millisecond, Multiplier: 1}),
+	)
+	require.NoError(t, err)
+	resp, err := client.DoRequest(t.Context(), &Request{Method: http.MethodGet, Endpoint: "status/v1/alarm/"})
+	require.NoError(t, err)
+	assert.Equal(t, standby.URL, resp.ServedBy)
+}
+
+func TestClient_WithBaseURLs_AllDown(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	client, err := New(WithBaseURLs(down.URL, down.URL+"/"), WithNoRetries())
+	require.NoError(t, err)
+
+	_, err = client.DoRequest(t.Context(), &Request{Method: http.MethodGet, Endpoint: "status/v1/alarm/"})
+	assert.Error(t, err)
+}
+
+func TestClient_CheckNodes(t *testing.T) {
+	var primaryHealthy atomic.Bool
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !primaryHealthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer primary.Close()
+	standby := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer standby.Close()
+
+	client, err := New(WithBaseURLs(primary.URL, standby.URL), WithNoRetries())
+	require.NoError(t, err)
+
+	nodes := client.CheckNodes(t.Context())
+	require.Len(t, nodes, 2)
+	assert.False(t, nodes[0].Healthy)
+	assert.True(t, nodes[1].Healthy)
+
+	resp, err := client.DoRequest(t.Context(), &Request{Method: http.MethodGet, Endpoint: "status/v1/alarm/"})
+	require.NoError(t, err)
+	assert.Equal(t, standby.URL, resp.ServedBy)
+
+	primaryHealthy.Store(true)
+	nodes = client.CheckNodes(t.Context())
+	assert.True(t, nodes[0].Healthy)
+
+	resp, err = client.DoRequest(t.Context(), &Request{Method: http.MethodGet, Endpoint: "status/v1/alarm/"})
+	require.NoError(t, err)
+	assert.Equal(t, primary.URL, resp.ServedBy)
+}
+
+func TestClient_WithBaseURLs_CircuitBreakerPerNode(t *testing.T) {
+	var primaryHealthy atomic.Bool
+	var primaryRequests atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryRequests.Add(1)
+		if !primaryHealthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer primary.Close()
+	standby := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer standby.Close()
+
+	client, err := New(
+		WithBaseURLs(primary.URL, standby.URL),
+		WithRetryConfig(&RetryConfig{MaxRetries: 3, BackoffMin: time.Millisecond, BackoffMax: time.Millisecond, Multiplier: 1}),
+		WithCircuitBreaker(&CircuitBreakerConfig{ConsecutiveFailures: 1, OpenTimeout: time.Hour}),
+	)
+	require.NoError(t, err)
+
+	// The primary's breaker opens, and the retry goes to the standby
+	resp, err := client.DoRequest(t.Context(), &Request{Method: http.MethodGet, Endpoint: "status/v1/alarm/"})
+	require.NoError(t, err)
+	assert.Equal(t, standby.URL, resp.ServedBy)
+	assert.Equal(t, int32(1), primaryRequests.Load())
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+
+	nodes := client.Nodes()
+	assert.Equal(t, CircuitOpen, nodes[0].Circuit)
+	assert.Equal(t, CircuitClosed, nodes[1].Circuit)
+
+	// Health checks bypass the open breaker and are not retried
+	nodes = client.CheckNodes(t.Context())
+	assert.False(t, nodes[0].Healthy)
+	assert.Equal(t, CircuitOpen, nodes[0].Circuit)
+	assert.Equal(t, int32(2), primaryRequests.Load())
+
+	primaryHealthy.Store(true)
+	nodes = client.CheckNodes(t.Context())
+	assert.True(t, nodes[0].Healthy)
+	assert.Equal(t, CircuitClosed, nodes[0].Circuit)
+
+	resp, err = client.DoRequest(t.Context(), &Request{Method: http.MethodGet, Endpoint: "status/v1/alarm/"})
+	require.NoError(t, err)
+	assert.Equal(t, primary.URL, resp.ServedBy)
+}
+
+func TestWithBaseURLs_Invalid(t *testing.T) {
+	_, err := New(WithBaseURLs())
+	assert.Error(t, err)
+	_, err = New(WithBaseURLs("https://primary.example.com", ":/invalid"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,236 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package fleet manages many separate Pexip Infinity deployments at once. A Fleet holds a named client
+// per cluster and fans calls out across them concurrently with bounded parallelism, tagging results with
+// the cluster they came from and collecting per-cluster errors instead of failing the whole operation.
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	infinity "github.com/pexip/go-infinity-sdk/v41"
+	"github.com/pexip/go-infinity-sdk/v41/config"
+	"github.com/pexip/go-infinity-sdk/v41/options"
+	"github.com/pexip/go-infinity-sdk/v41/status"
+)
+
+// DefaultParallelism is the default number of clusters called at the same time
+const DefaultParallelism = 8
+
+// ErrNotFound is returned when no cluster has the item being looked for
+var ErrNotFound = errors.New("not found in any cluster")
+
+// Fleet is a named set of Infinity clients, one per cluster
+type Fleet struct {
+	clients     map[string]*infinity.Client
+	names       []string
+	parallelism int
+}
+
+// Option configures a Fleet
+type Option func(*Fleet) error
+
+// WithParallelism sets how many clusters are called at the same time
+func WithParallelism(n int) Option {
+	return func(f *Fleet) error {
+		if n <= 0 {
+			return fmt.Errorf("parallelism must be positive")
+		}
+		f.parallelism = n
+		return nil
+	}
+}
+
+// New creates a Fleet from clients keyed by cluster name
+func New(clients map[string]*infinity.Client, opts ...Option) (*Fleet, error) {
+	f := &Fleet{
+		clients:     make(map[string]*infinity.Client, len(clients)),
+		parallelism: DefaultParallelism,
+	}
+	for name, client := range clients {
+		if name == "" {
+			return nil, fmt.Errorf("cluster name cannot be empty")
+		}
+		if client == nil {
+			return nil, fmt.Errorf("client for cluster %q cannot be nil", name)
+		}
+		f.clients[name] = client
+		f.names = append(f.names, name)
+	}
+	sort.Strings(f.names)
+
+	for _, opt := range opts {
+		if err := opt(f); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Names returns the cluster names in sorted order
+func (f *Fleet) Names() []string {
+	return slices.Clone(f.names)
+}
+
+// Client returns the client of the named cluster
+func (f *Fleet) Client(name string) (*infinity.Client, bool) {
+	client, ok := f.clients[name]
+	return client, ok
+}
+
+// Result is the outcome of a call on one cluster
+type Result[T any] struct {
+	Cluster string
+	Value   T
+	Err     error
+}
+
+// Item is a value tagged with the cluster it came from
+type Item[T any] struct {
+	Cluster string
+	Value   T
+}
+
+// Errors maps cluster names to the error their call failed with
+type Errors map[string]error
+
+// Error lists the failed clusters in sorted order
+func (e Errors) Error() string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]string, 0, len(names))
+	for _, name := range names {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", name, e[name]))
+	}
+	return fmt.Sprintf("%d of the clusters failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the per-cluster errors so errors.Is and errors.As can match any of them
+func (e Errors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Func is a call made on one cluster
+type Func[T any] func(ctx context.Context, cluster string, client *infinity.Client) (T, error)
+
+// Map calls fn on every cluster concurrently and returns the results in cluster name order
+func Map[T any](ctx context.Context, f *Fleet, fn Func[T]) []Result[T] {
+	results := make([]Result[T], len(f.names))
+	sem := make(chan struct{}, f.parallelism)
+	var wg sync.WaitGroup
+	for i, name := range f.names {
+		results[i].Cluster = name
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			results[i].Value, results[i].Err = fn(ctx, name, f.clients[name])
+		})
+	}
+	wg.Wait()
+	return results
+}
+
+// Collect calls fn on every cluster concurrently and flattens the returned lists into items tagged with their
+// cluster. Items from the clusters that succeeded are returned even if others failed, in which case the error
+// is an Errors.
+func Collect[T any](ctx context.Context, f *Fleet, fn Func[[]T]) ([]Item[T], error) {
+	var items []Item[T]
+	errs := Errors{}
+	for _, result := range Map(ctx, f, fn) {
+		if result.Err != nil {
+			errs[result.Cluster] = result.Err
+			continue
+		}
+		for _, value := range result.Value {
+			items = append(items, Item[T]{Cluster: result.Cluster, Value: value})
+		}
+	}
+	if len(errs) > 0 {
+		return items, errs
+	}
+	return items, nil
+}
+
+// Find calls fn on every cluster concurrently until one reports that it found what it was looking for, and
+// cancels the remaining calls. If no cluster finds it, the error is ErrNotFound, or an Errors if any
+// cluster failed.
+func Find[T any](ctx context.Context, f *Fleet, fn func(ctx context.Context, cluster string, client *infinity.Client) (T, bool, error)) (Item[T], error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var once sync.Once
+	var found Item[T]
+	results := Map(ctx, f, func(ctx context.Context, cluster string, client *infinity.Client) (struct{}, error) {
+		value, ok, err := fn(ctx, cluster, client)
+		if ok {
+			once.Do(func() {
+				found = Item[T]{Cluster: cluster, Value: value}
+				cancel()
+			})
+		}
+		return struct{}{}, err
+	})
+	if found.Cluster != "" {
+		return found, nil
+	}
+
+	errs := Errors{}
+	for _, result := range results {
+		if result.Err != nil {
+			errs[result.Cluster] = result.Err
+		}
+	}
+	if len(errs) > 0 {
+		return found, errs
+	}
+	return found, ErrNotFound
+}
+
+// ActiveConferences lists the active conferences of every cluster
+func (f *Fleet) ActiveConferences(ctx context.Context) ([]Item[status.ConferenceStatus], error) {
+	return Collect(ctx, f, func(ctx context.Context, _ string, client *infinity.Client) ([]status.ConferenceStatus, error) {
+		var conferences []status.ConferenceStatus
+		for conference, err := range client.Status().AllConferences(ctx, nil) {
+			if err != nil {
+				return nil, err
+			}
+			conferences = append(conferences, conference)
+		}
+		return conferences, nil
+	})
+}
+
+// FindAlias returns the conference alias and the cluster that owns it
+func (f *Fleet) FindAlias(ctx context.Context, alias string) (Item[config.ConferenceAlias], error) {
+	opts := &config.ListOptions{}
+	opts.Filter = options.NewFilter().Where("alias", options.Exact, alias)
+	return Find(ctx, f, func(ctx context.Context, _ string, client *infinity.Client) (config.ConferenceAlias, bool, error) {
+		result, err := client.Config().ListConferenceAliases(ctx, opts)
+		if err != nil || len(result.Objects) == 0 {
+			return config.ConferenceAlias{}, false, err
+		}
+		return result.Objects[0], true, nil
+	})
+}
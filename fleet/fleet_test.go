@@ -0,0 +1,116 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package fleet
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	infinity "github.com/pexip/go-infinity-sdk/v41"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cluster starts a fake management node that answers every request with body, or with status if it is not 200
+func cluster(t *testing.T, status int, body string) *infinity.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	client, err := infinity.New(infinity.WithBaseURL(server.URL), infinity.WithNoRetries())
+	require.NoError(t, err)
+	return client
+}
+
+func TestFleet_ActiveConferences(t *testing.T) {
+	fleet, err := New(map[string]*infinity.Client{
+		"acme":    cluster(t, http.StatusOK, `{"meta":{"total_count":2},"objects":[{"id":"a1","name":"Sales"},{"id":"a2","name":"Support"}]}`),
+		"globex":  cluster(t, http.StatusOK, `{"meta":{"total_count":1},"objects":[{"id":"g1","name":"Board"}]}`),
+		"initech": cluster(t, http.StatusServiceUnavailable, ""),
+	}, WithParallelism(2))
+	require.NoError(t, err)
+
+	conferences, err := fleet.ActiveConferences(t.Context())
+	require.Len(t, conferences, 3)
+	assert.Equal(t, "acme", conferences[0].Cluster)
+	assert.Equal(t, "Sales", conferences[0].Value.Name)
+	assert.Equal(t, "globex", conferences[2].Cluster)
+
+	var errs Errors
+	require.ErrorAs(t, err, &errs)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs, "initech")
+	var apiErr *infinity.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+}
+
+func TestFleet_FindAlias(t *testing.T) {
+	fleet, err := New(map[string]*infinity.Client{
+		"acme":   cluster(t, http.StatusOK, `{"meta":{"total_count":0},"objects":[]}`),
+		"globex": cluster(t, http.StatusOK, `{"meta":{"total_count":1},"objects":[{"id":7,"alias":"meet.board@globex.com"}]}`),
+	})
+	require.NoError(t, err)
+
+	found, err := fleet.FindAlias(t.Context(), "meet.board@globex.com")
+	require.NoError(t, err)
+	assert.Equal(t, "globex", found.Cluster)
+	assert.Equal(t, 7, found.Value.ID)
+
+	fleet, err = New(map[string]*infinity.Client{
+		"acme": cluster(t, http.StatusOK, `{"meta":{"total_count":0},"objects":[]}`),
+	})
+	require.NoError(t, err)
+	_, err = fleet.FindAlias(t.Context(), "meet.board@globex.com")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMap_Parallelism(t *testing.T) {
+	clients := map[string]*infinity.Client{}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		clients[name] = &infinity.Client{}
+	}
+	fleet, err := New(clients, WithParallelism(2))
+	require.NoError(t, err)
+
+	var running, peak atomic.Int32
+	results := Map(t.Context(), fleet, func(ctx context.Context, cluster string, _ *infinity.Client) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		return cluster + "!", nil
+	})
+
+	require.Len(t, results, 5)
+	for i, name := range fleet.Names() {
+		assert.Equal(t, name, results[i].Cluster)
+		assert.Equal(t, name+"!", results[i].Value)
+	}
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+}
+
+func TestNew_Invalid(t *testing.T) {
+	_, err := New(map[string]*infinity.Client{"": {}})
+	assert.Error(t, err)
+	_, err = New(map[string]*infinity.Client{"acme": nil})
+	assert.Error(t, err)
+	_, err = New(nil, WithParallelism(0))
+	assert.Error(t, err)
+}
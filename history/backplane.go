@@ -9,6 +9,9 @@ package history
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListBackplanes retrieves a list of backplane history records
@@ -20,6 +23,17 @@ func (s *Service) ListBackplanes(ctx context.Context, opts *ListOptions) (*Backp
 	return &result, err
 }
 
+// AllBackplanes returns an iterator over all backplane history records, fetching further pages as needed
+func (s *Service) AllBackplanes(ctx context.Context, opts *ListOptions) iter.Seq2[Backplane, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]Backplane, options.Meta, error) {
+		result, err := s.ListBackplanes(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetBackplane retrieves a specific backplane history record by ID
 func (s *Service) GetBackplane(ctx context.Context, id string) (*Backplane, error) {
 	endpoint := fmt.Sprintf("history/v1/backplane/%s/", id)
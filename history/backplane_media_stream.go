@@ -9,8 +9,11 @@ package history
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"time"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListBackplaneMediaStreams retrieves a list of backplane media stream history records
@@ -22,6 +25,17 @@ func (s *Service) ListBackplaneMediaStreams(ctx context.Context, opts *ListOptio
 	return &result, err
 }
 
+// AllBackplaneMediaStreams returns an iterator over all backplane media stream history records, fetching further pages as needed
+func (s *Service) AllBackplaneMediaStreams(ctx context.Context, opts *ListOptions) iter.Seq2[BackplaneMediaStream, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]BackplaneMediaStream, options.Meta, error) {
+		result, err := s.ListBackplaneMediaStreams(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetBackplaneMediaStream retrieves a specific backplane media stream history record by ID
 func (s *Service) GetBackplaneMediaStream(ctx context.Context, id int) (*BackplaneMediaStream, error) {
 	endpoint := fmt.Sprintf("history/v1/backplane_media_stream/%d/", id)
@@ -39,11 +53,12 @@ func (s *Service) ListBackplaneMediaStreamsByBackplane(ctx context.Context, back
 	params.Set("backplane", backplaneID)
 
 	if opts != nil {
+		if err := opts.Filter.Validate(filterSchemas[endpoint]); err != nil {
+			return nil, err
+		}
 		optParams := opts.BaseListOptions.ToURLValues()
 		for key, values := range optParams {
-			for _, value := range values {
-				params.Set(key, value)
-			}
+			params[key] = values
 		}
 		if opts.StartTime != nil {
 			params.Set("start_time__gte", opts.StartTime.Format(time.RFC3339))
@@ -57,3 +72,14 @@ func (s *Service) ListBackplaneMediaStreamsByBackplane(ctx context.Context, back
 	err := s.client.GetJSON(ctx, endpoint, &params, &result)
 	return &result, err
 }
+
+// AllBackplaneMediaStreamsByBackplane returns an iterator over all backplane media stream history for a specific backplane, fetching further pages as needed
+func (s *Service) AllBackplaneMediaStreamsByBackplane(ctx context.Context, backplaneID string, opts *ListOptions) iter.Seq2[BackplaneMediaStream, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]BackplaneMediaStream, options.Meta, error) {
+		result, err := s.ListBackplaneMediaStreamsByBackplane(ctx, backplaneID, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
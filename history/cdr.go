@@ -0,0 +1,428 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package history
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pexip/go-infinity-sdk/v41/internal/parquet"
+)
+
+// CDR is a normalised call detail record: a participant joined to its conference and media streams.
+// Times are in the export's location and are zero when unknown.
+type CDR struct {
+	ParticipantID             int
+	CallUUID                  string
+	ConversationID            string
+	DisplayName               string
+	LocalAlias                string
+	RemoteAlias               string
+	Role                      string
+	CallDirection             string
+	StartTime                 time.Time
+	EndTime                   time.Time
+	DurationSeconds           int
+	DisconnectReason          string
+	RemoteAddress             string
+	Vendor                    string
+	Encryption                string
+	MediaNode                 string
+	SignalingNode             string
+	RxBytes                   int64
+	TxBytes                   int64
+	ConferenceID              int
+	ConferenceName            string
+	ConferenceTag             string
+	ServiceType               string
+	ConferenceStartTime       time.Time
+	ConferenceEndTime         time.Time
+	ConferenceDurationSeconds int
+	ConferenceParticipants    int
+	MediaStreams              int
+	Codecs                    []string
+	RxPackets                 int64
+	TxPackets                 int64
+	RxPacketsLost             int64
+	TxPacketsLost             int64
+
+	// Participant, Conference and Streams are the source records, for custom columns.
+	// Conference is nil if the participant's conference record could not be found.
+	Participant Participant
+	Conference  *ConferenceRecord
+	Streams     []MediaStream
+}
+
+// newCDR joins p to its conference and media streams, converting times to loc
+func newCDR(p Participant, conf *ConferenceRecord, streams []MediaStream, loc *time.Location) *CDR {
+	cdr := &CDR{
+		ParticipantID:    p.ID,
+		CallUUID:         p.CallUUID,
+		ConversationID:   p.ConversationID,
+		DisplayName:      p.DisplayName,
+		LocalAlias:       p.LocalAlias,
+		RemoteAlias:      p.RemoteAlias,
+		Role:             p.Role,
+		CallDirection:    p.CallDirection,
+		StartTime:        inLocation(p.StartTime.Time, loc),
+		EndTime:          inLocation(p.EndTime.Time, loc),
+		DurationSeconds:  p.DurationSeconds,
+		DisconnectReason: p.DisconnectReason,
+		RemoteAddress:    p.RemoteAddress,
+		Vendor:           p.Vendor,
+		Encryption:       p.Encryption,
+		MediaNode:        p.MediaNode,
+		SignalingNode:    p.SignalingNode,
+		RxBytes:          p.TotalRxBytes,
+		TxBytes:          p.TotalTxBytes,
+		ConferenceID:     p.ConferenceID,
+		ConferenceName:   p.ConferenceName,
+		ServiceType:      p.ServiceType,
+		MediaStreams:     len(streams),
+		Participant:      p,
+		Conference:       conf,
+		Streams:          streams,
+	}
+	if conf != nil {
+		cdr.ConferenceName = conf.Name
+		cdr.ConferenceTag = conf.Tag
+		if conf.ServiceType != "" {
+			cdr.ServiceType = conf.ServiceType
+		}
+		cdr.ConferenceStartTime = inLocation(conf.StartTime.Time, loc)
+		cdr.ConferenceEndTime = inLocation(conf.EndTime.Time, loc)
+		cdr.ConferenceDurationSeconds = conf.DurationSeconds
+		cdr.ConferenceParticipants = conf.TotalParticipants
+	}
+	for _, stream := range streams {
+		if stream.Codec != "" && !slices.Contains(cdr.Codecs, stream.Codec) {
+			cdr.Codecs = append(cdr.Codecs, stream.Codec)
+		}
+		cdr.RxPackets += int64(stream.RxPackets)
+		cdr.TxPackets += int64(stream.TxPackets)
+		cdr.RxPacketsLost += int64(stream.RxPacketsLost)
+		cdr.TxPacketsLost += int64(stream.TxPacketsLost)
+	}
+	slices.Sort(cdr.Codecs)
+	return cdr
+}
+
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	if t.IsZero() {
+		return time.Time{}
+	}
+	return t.In(loc)
+}
+
+// ColumnType is the type of a CDR column's values
+type ColumnType int
+
+const (
+	// StringColumn values are strings
+	StringColumn ColumnType = iota
+	// IntColumn values are int64
+	IntColumn
+	// FloatColumn values are float64
+	FloatColumn
+	// BoolColumn values are bools
+	BoolColumn
+	// TimeColumn values are time.Time, written as RFC 3339 in text formats
+	TimeColumn
+)
+
+// Column is a column of an exported CDR. Value returns nil for a missing value, or
+// a value of the column's type.
+type Column struct {
+	Name  string
+	Type  ColumnType
+	Value func(*CDR) interface{}
+}
+
+func stringColumn(name string, value func(*CDR) string) Column {
+	return Column{Name: name, Type: StringColumn, Value: func(c *CDR) interface{} { return value(c) }}
+}
+
+func intColumn(name string, value func(*CDR) int64) Column {
+	return Column{Name: name, Type: IntColumn, Value: func(c *CDR) interface{} { return value(c) }}
+}
+
+func timeColumn(name string, value func(*CDR) time.Time) Column {
+	return Column{Name: name, Type: TimeColumn, Value: func(c *CDR) interface{} {
+		if t := value(c); !t.IsZero() {
+			return t
+		}
+		return nil
+	}}
+}
+
+// CDRColumns are the built-in CDR columns, in their default order
+var CDRColumns = []Column{
+	intColumn("participant_id", func(c *CDR) int64 { return int64(c.ParticipantID) }),
+	stringColumn("call_uuid", func(c *CDR) string { return c.CallUUID }),
+	stringColumn("conversation_id", func(c *CDR) string { return c.ConversationID }),
+	stringColumn("display_name", func(c *CDR) string { return c.DisplayName }),
+	stringColumn("local_alias", func(c *CDR) string { return c.LocalAlias }),
+	stringColumn("remote_alias", func(c *CDR) string { return c.RemoteAlias }),
+	stringColumn("role", func(c *CDR) string { return c.Role }),
+	stringColumn("call_direction", func(c *CDR) string { return c.CallDirection }),
+	timeColumn("start_time", func(c *CDR) time.Time { return c.StartTime }),
+	timeColumn("end_time", func(c *CDR) time.Time { return c.EndTime }),
+	intColumn("duration_seconds", func(c *CDR) int64 { return int64(c.DurationSeconds) }),
+	stringColumn("disconnect_reason", func(c *CDR) string { return c.DisconnectReason }),
+	stringColumn("remote_address", func(c *CDR) string { return c.RemoteAddress }),
+	stringColumn("vendor", func(c *CDR) string { return c.Vendor }),
+	stringColumn("encryption", func(c *CDR) string { return c.Encryption }),
+	stringColumn("media_node", func(c *CDR) string { return c.MediaNode }),
+	stringColumn("signaling_node", func(c *CDR) string { return c.SignalingNode }),
+	intColumn("rx_bytes", func(c *CDR) int64 { return c.RxBytes }),
+	intColumn("tx_bytes", func(c *CDR) int64 { return c.TxBytes }),
+	intColumn("conference_id", func(c *CDR) int64 { return int64(c.ConferenceID) }),
+	stringColumn("conference_name", func(c *CDR) string { return c.ConferenceName }),
+	stringColumn("conference_tag", func(c *CDR) string { return c.ConferenceTag }),
+	stringColumn("service_type", func(c *CDR) string { return c.ServiceType }),
+	timeColumn("conference_start_time", func(c *CDR) time.Time { return c.ConferenceStartTime }),
+	timeColumn("conference_end_time", func(c *CDR) time.Time { return c.ConferenceEndTime }),
+	intColumn("conference_duration_seconds", func(c *CDR) int64 { return int64(c.ConferenceDurationSeconds) }),
+	intColumn("conference_participants", func(c *CDR) int64 { return int64(c.ConferenceParticipants) }),
+	intColumn("media_streams", func(c *CDR) int64 { return int64(c.MediaStreams) }),
+	stringColumn("codecs", func(c *CDR) string { return strings.Join(c.Codecs, ",") }),
+	intColumn("rx_packets", func(c *CDR) int64 { return c.RxPackets }),
+	intColumn("tx_packets", func(c *CDR) int64 { return c.TxPackets }),
+	intColumn("rx_packets_lost", func(c *CDR) int64 { return c.RxPacketsLost }),
+	intColumn("tx_packets_lost", func(c *CDR) int64 { return c.TxPacketsLost }),
+}
+
+// SelectColumns returns the built-in columns with the given names, in the given order.
+// With no names it returns all of them.
+func SelectColumns(names ...string) ([]Column, error) {
+	if len(names) == 0 {
+		return slices.Clone(CDRColumns), nil
+	}
+	columns := make([]Column, 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(CDRColumns, func(c Column) bool { return c.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown CDR column %q", name)
+		}
+		columns = append(columns, CDRColumns[i])
+	}
+	return columns, nil
+}
+
+// CDRWriter writes CDRs in a file format. Flush is called at every checkpoint, after
+// which the records written so far must be durable in the underlying writer.
+type CDRWriter interface {
+	Write(cdr *CDR) error
+	Flush() error
+	Close() error
+}
+
+// sizedWriter is a CDRWriter that reports how many bytes it has written to the underlying
+// writer, which Export records in each checkpoint
+type sizedWriter interface {
+	Written() int64
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// CSVWriter writes CDRs as CSV with one column per Column
+type CSVWriter struct {
+	w       *csv.Writer
+	out     *countingWriter
+	columns []Column
+	header  bool
+	record  []string
+}
+
+// NewCSVWriter creates a CSVWriter of columns to w, or of all built-in columns if columns
+// is empty. If header is true a header row of column names is written first; pass false
+// when appending to a file that already has one.
+func NewCSVWriter(w io.Writer, columns []Column, header bool) *CSVWriter {
+	if len(columns) == 0 {
+		columns = CDRColumns
+	}
+	out := &countingWriter{w: w}
+	return &CSVWriter{w: csv.NewWriter(out), out: out, columns: columns, header: header, record: make([]string, len(columns))}
+}
+
+// Write writes cdr as a CSV record
+func (w *CSVWriter) Write(cdr *CDR) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	for i, column := range w.columns {
+		w.record[i] = formatText(column.Value(cdr))
+	}
+	return w.w.Write(w.record)
+}
+
+func (w *CSVWriter) writeHeader() error {
+	if !w.header {
+		return nil
+	}
+	w.header = false
+	for i, column := range w.columns {
+		w.record[i] = column.Name
+	}
+	return w.w.Write(w.record)
+}
+
+// Flush writes any buffered records to the underlying writer
+func (w *CSVWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// Close writes the header if no records were written and flushes. It does not close the underlying writer.
+func (w *CSVWriter) Close() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// Written returns the number of bytes written to the underlying writer
+func (w *CSVWriter) Written() int64 {
+	return w.out.n
+}
+
+// formatText formats a column value for text formats: nil as empty, times as RFC 3339
+func formatText(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// JSONLWriter writes CDRs as JSON Lines, one object per record with keys in column order
+type JSONLWriter struct {
+	w       *bufio.Writer
+	out     *countingWriter
+	columns []Column
+	line    []byte
+}
+
+// NewJSONLWriter creates a JSONLWriter of columns to w, or of all built-in columns if columns is empty
+func NewJSONLWriter(w io.Writer, columns []Column) *JSONLWriter {
+	if len(columns) == 0 {
+		columns = CDRColumns
+	}
+	out := &countingWriter{w: w}
+	return &JSONLWriter{w: bufio.NewWriter(out), out: out, columns: columns}
+}
+
+// Write writes cdr as a JSON object on its own line
+func (w *JSONLWriter) Write(cdr *CDR) error {
+	line := append(w.line[:0], '{')
+	for i, column := range w.columns {
+		if i > 0 {
+			line = append(line, ',')
+		}
+		name, _ := json.Marshal(column.Name)
+		line = append(line, name...)
+		line = append(line, ':')
+		value, err := json.Marshal(column.Value(cdr))
+		if err != nil {
+			return fmt.Errorf("column %s: %w", column.Name, err)
+		}
+		line = append(line, value...)
+	}
+	line = append(line, '}', '\n')
+	w.line = line
+	_, err := w.w.Write(line)
+	return err
+}
+
+// Flush writes any buffered records to the underlying writer
+func (w *JSONLWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// Close flushes. It does not close the underlying writer.
+func (w *JSONLWriter) Close() error {
+	return w.Flush()
+}
+
+// Written returns the number of bytes written to the underlying writer
+func (w *JSONLWriter) Written() int64 {
+	return w.out.n
+}
+
+// ParquetWriter writes CDRs as a Parquet file with a row group per Flush. The file is only
+// readable once Close has written its footer, so a resumed export must write to a new file.
+type ParquetWriter struct {
+	w       *parquet.Writer
+	columns []Column
+}
+
+// NewParquetWriter creates a ParquetWriter of columns to w, or of all built-in columns if columns is empty
+func NewParquetWriter(w io.Writer, columns []Column) *ParquetWriter {
+	if len(columns) == 0 {
+		columns = CDRColumns
+	}
+	schema := make([]parquet.Column, len(columns))
+	for i, column := range columns {
+		schema[i] = parquet.Column{Name: column.Name, Type: parquetTypes[column.Type]}
+	}
+	return &ParquetWriter{w: parquet.NewWriter(w, schema), columns: columns}
+}
+
+var parquetTypes = map[ColumnType]parquet.Type{
+	StringColumn: parquet.String,
+	IntColumn:    parquet.Int64,
+	FloatColumn:  parquet.Double,
+	BoolColumn:   parquet.Boolean,
+	TimeColumn:   parquet.Timestamp,
+}
+
+// Write buffers cdr as a row
+func (w *ParquetWriter) Write(cdr *CDR) error {
+	row := make([]interface{}, len(w.columns))
+	for i, column := range w.columns {
+		row[i] = column.Value(cdr)
+	}
+	return w.w.Write(row)
+}
+
+// Flush writes the buffered rows as a row group
+func (w *ParquetWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// Close writes the buffered rows and the file footer. It does not close the underlying writer.
+func (w *ParquetWriter) Close() error {
+	return w.w.Close()
+}
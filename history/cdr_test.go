@@ -0,0 +1,127 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package history
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCDRs() []*CDR {
+	utc := time.UTC
+	streams := []MediaStream{
+		{ParticipantID: 1, Codec: "opus", RxPackets: 100, RxPacketsLost: 2},
+		{ParticipantID: 1, Codec: "H264", RxPackets: 300, TxPacketsLost: 1},
+		{ParticipantID: 1, Codec: "opus", RxPackets: 50},
+	}
+	conf := &ConferenceRecord{ID: 7, Name: "Board, weekly", StartTime: at("2025-03-01T09:00:00Z")}
+	return []*CDR{
+		newCDR(Participant{ID: 1, ConferenceID: 7, DisplayName: "Alice", StartTime: at("2025-03-01T09:00:00Z")}, conf, streams, utc),
+		newCDR(Participant{ID: 2, ConferenceID: 8, ConferenceName: "Ad hoc"}, nil, nil, utc),
+	}
+}
+
+func TestNewCDR(t *testing.T) {
+	cdrs := testCDRs()
+
+	assert.Equal(t, "Board, weekly", cdrs[0].ConferenceName)
+	assert.Equal(t, []string{"H264", "opus"}, cdrs[0].Codecs)
+	assert.Equal(t, int64(450), cdrs[0].RxPackets)
+	assert.Equal(t, int64(2), cdrs[0].RxPacketsLost)
+	assert.Equal(t, int64(1), cdrs[0].TxPacketsLost)
+
+	assert.Equal(t, "Ad hoc", cdrs[1].ConferenceName)
+	assert.True(t, cdrs[1].StartTime.IsZero())
+	assert.Nil(t, cdrs[1].Conference)
+}
+
+func TestSelectColumns(t *testing.T) {
+	columns, err := SelectColumns()
+	require.NoError(t, err)
+	assert.Len(t, columns, len(CDRColumns))
+
+	columns, err = SelectColumns("conference_name", "participant_id")
+	require.NoError(t, err)
+	require.Len(t, columns, 2)
+	assert.Equal(t, "conference_name", columns[0].Name)
+	assert.Equal(t, "participant_id", columns[1].Name)
+
+	_, err = SelectColumns("participant_id", "mos")
+	assert.ErrorContains(t, err, `unknown CDR column "mos"`)
+}
+
+func TestCSVWriter(t *testing.T) {
+	columns, err := SelectColumns("participant_id", "conference_name", "start_time", "codecs")
+	require.NoError(t, err)
+	columns = append(columns, Column{Name: "user_agent", Type: StringColumn, Value: func(c *CDR) interface{} {
+		return c.Participant.UserAgent
+	}})
+
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf, columns, true)
+	for _, cdr := range testCDRs() {
+		require.NoError(t, w.Write(cdr))
+	}
+	require.NoError(t, w.Close())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"participant_id", "conference_name", "start_time", "codecs", "user_agent"},
+		{"1", "Board, weekly", "2025-03-01T09:00:00Z", "H264,opus", ""},
+		{"2", "Ad hoc", "", "", ""},
+	}, records)
+}
+
+func TestCSVWriter_Empty(t *testing.T) {
+	columns, err := SelectColumns("participant_id", "vendor")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, NewCSVWriter(&buf, columns, true).Close())
+	assert.Equal(t, "participant_id,vendor\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, NewCSVWriter(&buf, columns, false).Close())
+	assert.Empty(t, buf.String())
+}
+
+func TestJSONLWriter(t *testing.T) {
+	columns, err := SelectColumns("participant_id", "start_time", "rx_packets_lost")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w := NewJSONLWriter(&buf, columns)
+	for _, cdr := range testCDRs() {
+		require.NoError(t, w.Write(cdr))
+	}
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, `{"participant_id":1,"start_time":"2025-03-01T09:00:00Z","rx_packets_lost":2}
+{"participant_id":2,"start_time":null,"rx_packets_lost":0}
+`, buf.String())
+}
+
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewParquetWriter(&buf, nil)
+	cdrs := testCDRs()
+	require.NoError(t, w.Write(cdrs[0]))
+	require.NoError(t, w.Flush())
+	require.NoError(t, w.Write(cdrs[1]))
+	require.NoError(t, w.Close())
+
+	data := buf.Bytes()
+	assert.Equal(t, "PAR1", string(data[:4]))
+	assert.Equal(t, "PAR1", string(data[len(data)-4:]))
+	assert.Contains(t, buf.String(), "conference_participants")
+}
@@ -9,6 +9,9 @@ package history
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListConferenceRecords retrieves a list of conference history records
@@ -20,6 +23,17 @@ func (s *Service) ListConferenceRecords(ctx context.Context, opts *ListOptions)
 	return &result, err
 }
 
+// AllConferenceRecords returns an iterator over all conference history records, fetching further pages as needed
+func (s *Service) AllConferenceRecords(ctx context.Context, opts *ListOptions) iter.Seq2[ConferenceRecord, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ConferenceRecord, options.Meta, error) {
+		result, err := s.ListConferenceRecords(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetConferenceRecord retrieves a specific conference history record by ID
 func (s *Service) GetConferenceRecord(ctx context.Context, id int) (*ConferenceRecord, error) {
 	endpoint := fmt.Sprintf("history/v1/conference/%d/", id)
@@ -0,0 +1,277 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
+)
+
+const (
+	// DefaultExportWindow is the span of participant start times exported between checkpoints
+	DefaultExportWindow = 24 * time.Hour
+	// DefaultExportPageSize is the number of participants requested per page
+	DefaultExportPageSize = 500
+)
+
+// ExportOptions controls an export of call detail records
+type ExportOptions struct {
+	// From and To bound the participants' start times, From inclusive and To exclusive
+	From time.Time
+	To   time.Time
+	// Window is the span of start times exported between checkpoints, DefaultExportWindow if zero
+	Window time.Duration
+	// PageSize is the number of participants requested per page, DefaultExportPageSize if zero
+	PageSize int
+	// Location is the time zone of the exported times, UTC if nil
+	Location *time.Location
+	// Resume continues an interrupted export from its last checkpoint
+	Resume *Checkpoint
+	// OnCheckpoint is called after each window has been written and flushed, typically to save the checkpoint
+	OnCheckpoint func(Checkpoint) error
+}
+
+// Checkpoint records the progress of an export: every participant who started
+// between From and Next has been written
+type Checkpoint struct {
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Next    time.Time `json:"next"`
+	Records int       `json:"records"`
+	// Offset is the size of the output at the checkpoint, recorded for the CSV and JSON Lines
+	// writers. Anything after it was written by an interrupted window and is written again on resume.
+	Offset int64 `json:"offset"`
+}
+
+// Done reports whether the export is complete
+func (c *Checkpoint) Done() bool {
+	return !c.Next.Before(c.To)
+}
+
+// LoadCheckpoint reads a checkpoint saved by Save. It returns nil and no error if path does not exist.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	return &cp, nil
+}
+
+// Save writes the checkpoint to path, replacing it atomically
+func (c *Checkpoint) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// OpenExportFile opens the file at path for an export to append to. If resume is nil the file
+// is created or truncated; otherwise it is truncated to resume.Offset, discarding any records
+// written after the checkpoint, so that resuming does not duplicate them.
+func OpenExportFile(path string, resume *Checkpoint) (*os.File, error) {
+	if resume == nil {
+		return os.Create(path)
+	}
+	if resume.Offset == 0 && resume.Records > 0 {
+		return nil, errors.New("checkpoint has no output offset")
+	}
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(resume.Offset); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// batchSize is the number of IDs in an id__in or participant_id__in filter
+const batchSize = 100
+
+// Export writes a CDR for every participant who started between opts.From and opts.To,
+// joined to its conference and media streams, ordered by start time. It does not close w.
+//
+// Participants are exported a window at a time; after each window w is flushed and
+// opts.OnCheckpoint is called. Export returns the last checkpoint reached, which can be
+// passed as opts.Resume to continue after an error. w may already have written part of
+// the next window, so open a file to resume into with OpenExportFile.
+func (s *Service) Export(ctx context.Context, w CDRWriter, opts *ExportOptions) (*Checkpoint, error) {
+	o := *opts
+	if o.Window <= 0 {
+		o.Window = DefaultExportWindow
+	}
+	if o.PageSize <= 0 {
+		o.PageSize = DefaultExportPageSize
+	}
+	if o.Location == nil {
+		o.Location = time.UTC
+	}
+	cp := &Checkpoint{From: o.From, To: o.To, Next: o.From}
+	if o.Resume != nil {
+		if !o.Resume.From.Equal(o.From) || !o.Resume.To.Equal(o.To) {
+			return nil, fmt.Errorf("checkpoint covers %s to %s, not %s to %s",
+				o.Resume.From.Format(time.RFC3339), o.Resume.To.Format(time.RFC3339),
+				o.From.Format(time.RFC3339), o.To.Format(time.RFC3339))
+		}
+		*cp = *o.Resume
+	}
+	offset := cp.Offset
+	if !o.From.Before(o.To) {
+		return nil, errors.New("export range is empty: From must be before To")
+	}
+
+	for !cp.Done() {
+		end := cp.Next.Add(o.Window)
+		if end.After(o.To) {
+			end = o.To
+		}
+		n, err := s.exportWindow(ctx, w, cp.Next, end, &o)
+		if err != nil {
+			return cp, err
+		}
+		if err := w.Flush(); err != nil {
+			return cp, err
+		}
+		cp.Next = end
+		cp.Records += n
+		if sw, ok := w.(sizedWriter); ok {
+			cp.Offset = offset + sw.Written()
+		}
+		if o.OnCheckpoint != nil {
+			if err := o.OnCheckpoint(*cp); err != nil {
+				return cp, err
+			}
+		}
+	}
+	return cp, nil
+}
+
+// exportWindow writes the CDRs of participants who started between from and to, a page at a time
+func (s *Service) exportWindow(ctx context.Context, w CDRWriter, from, to time.Time, o *ExportOptions) (int, error) {
+	listOpts := &ListOptions{}
+	listOpts.Limit = o.PageSize
+	listOpts.Filter = options.NewFilter().
+		Where("start_time", options.GTE, from.UTC()).
+		Where("start_time", options.LT, to.UTC()).
+		OrderBy("start_time", "id")
+
+	conferences := make(map[int]*ConferenceRecord)
+	page := make([]Participant, 0, o.PageSize)
+	written := 0
+	flush := func() error {
+		if err := s.fetchConferences(ctx, page, conferences); err != nil {
+			return err
+		}
+		streams, err := s.fetchStreams(ctx, page)
+		if err != nil {
+			return err
+		}
+		for _, p := range page {
+			if err := w.Write(newCDR(p, conferences[p.ConferenceID], streams[p.ID], o.Location)); err != nil {
+				return err
+			}
+			written++
+		}
+		page = page[:0]
+		return nil
+	}
+
+	for p, err := range s.AllParticipants(ctx, listOpts) {
+		if err != nil {
+			return written, err
+		}
+		page = append(page, p)
+		if len(page) == o.PageSize {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return written, err
+	}
+	return written, nil
+}
+
+// fetchConferences adds the conference records of participants that are not yet in conferences
+func (s *Service) fetchConferences(ctx context.Context, participants []Participant, conferences map[int]*ConferenceRecord) error {
+	var missing []int
+	for _, p := range participants {
+		if _, ok := conferences[p.ConferenceID]; !ok && p.ConferenceID != 0 {
+			conferences[p.ConferenceID] = nil
+			missing = append(missing, p.ConferenceID)
+		}
+	}
+	for ids := range slices.Chunk(missing, batchSize) {
+		opts := &ListOptions{}
+		opts.Limit = len(ids)
+		opts.Filter = options.NewFilter().Where("id", options.In, ids)
+		for conf, err := range s.AllConferenceRecords(ctx, opts) {
+			if err != nil {
+				return fmt.Errorf("failed to fetch conferences: %w", err)
+			}
+			conferences[conf.ID] = &conf
+		}
+	}
+	return nil
+}
+
+// fetchStreams returns the media streams of participants, keyed by participant ID
+func (s *Service) fetchStreams(ctx context.Context, participants []Participant) (map[int][]MediaStream, error) {
+	ids := make([]int, len(participants))
+	for i, p := range participants {
+		ids[i] = p.ID
+	}
+	streams := make(map[int][]MediaStream)
+	for ids := range slices.Chunk(ids, batchSize) {
+		opts := &ListOptions{}
+		opts.Limit = batchSize
+		opts.Filter = options.NewFilter().Where("participant_id", options.In, ids).OrderBy("id")
+		for stream, err := range s.AllMediaStreams(ctx, opts) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch media streams: %w", err)
+			}
+			streams[stream.ParticipantID] = append(streams[stream.ParticipantID], stream)
+		}
+	}
+	return streams, nil
+}
@@ -0,0 +1,293 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pexip/go-infinity-sdk/v41/interfaces"
+	"github.com/pexip/go-infinity-sdk/v41/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHistory serves in-memory history records, applying the filters that Export uses,
+// and fails the participant request numbered failOn
+type fakeHistory struct {
+	*interfaces.HTTPClientMock
+	conferences  []ConferenceRecord
+	participants []Participant
+	streams      []MediaStream
+	requests     map[string]int
+	failOn       int
+}
+
+func (f *fakeHistory) GetJSON(_ context.Context, endpoint string, params *url.Values, result interface{}) error {
+	if f.requests == nil {
+		f.requests = make(map[string]int)
+	}
+	f.requests[endpoint]++
+	switch endpoint {
+	case "history/v1/conference/":
+		ids := intList(params.Get("id__in"))
+		var objects []ConferenceRecord
+		for _, c := range f.conferences {
+			if slices.Contains(ids, c.ID) {
+				objects = append(objects, c)
+			}
+		}
+		res := result.(*ConferenceRecordListResponse)
+		res.Objects, res.Meta.Offset, res.Meta.Next = page(objects, params)
+	case "history/v1/participant/":
+		if f.requests[endpoint] == f.failOn {
+			return errors.New("connection reset")
+		}
+		from, _ := time.Parse(time.RFC3339, params.Get("start_time__gte"))
+		to, _ := time.Parse(time.RFC3339, params.Get("start_time__lt"))
+		var objects []Participant
+		for _, p := range f.participants {
+			if !p.StartTime.Before(from) && p.StartTime.Before(to) {
+				objects = append(objects, p)
+			}
+		}
+		res := result.(*ParticipantListResponse)
+		res.Objects, res.Meta.Offset, res.Meta.Next = page(objects, params)
+	case "history/v1/media_stream/":
+		ids := intList(params.Get("participant_id__in"))
+		var objects []MediaStream
+		for _, s := range f.streams {
+			if slices.Contains(ids, s.ParticipantID) {
+				objects = append(objects, s)
+			}
+		}
+		res := result.(*MediaStreamListResponse)
+		res.Objects, res.Meta.Offset, res.Meta.Next = page(objects, params)
+	}
+	return nil
+}
+
+func page[T any](objects []T, params *url.Values) ([]T, int, string) {
+	offset, _ := strconv.Atoi(params.Get("offset"))
+	limit, _ := strconv.Atoi(params.Get("limit"))
+	end := min(offset+limit, len(objects))
+	if offset >= end {
+		return nil, offset, ""
+	}
+	next := ""
+	if end < len(objects) {
+		next = "more"
+	}
+	return objects[offset:end], offset, next
+}
+
+func intList(s string) []int {
+	var ids []int
+	for field := range strings.SplitSeq(s, ",") {
+		if id, err := strconv.Atoi(field); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func at(s string) util.InfinityTime {
+	t, _ := time.Parse(time.RFC3339, s)
+	return util.InfinityTime{Time: t}
+}
+
+func newFakeHistory() *fakeHistory {
+	return &fakeHistory{
+		conferences: []ConferenceRecord{
+			{ID: 1, Name: "Sales", Tag: "emea", ServiceType: "conference", StartTime: at("2025-03-01T09:00:00Z"), EndTime: at("2025-03-01T10:00:00Z"), DurationSeconds: 3600, TotalParticipants: 3},
+			{ID: 2, Name: "Support", ServiceType: "lecture", StartTime: at("2025-03-02T23:30:00Z"), DurationSeconds: 600, TotalParticipants: 1},
+		},
+		participants: []Participant{
+			{ID: 10, ConferenceID: 1, ConferenceName: "Sales", DisplayName: "Alice", StartTime: at("2025-03-01T09:00:00Z"), EndTime: at("2025-03-01T10:00:00Z"), DurationSeconds: 3600, Vendor: "Pexip"},
+			{ID: 11, ConferenceID: 1, ConferenceName: "Sales", DisplayName: "Bob", StartTime: at("2025-03-01T09:05:00Z"), EndTime: at("2025-03-01T09:50:00Z")},
+			{ID: 12, ConferenceID: 1, ConferenceName: "Sales", DisplayName: "Carol", StartTime: at("2025-03-01T09:10:00Z"), EndTime: at("2025-03-01T10:00:00Z")},
+			{ID: 13, ConferenceID: 2, ConferenceName: "Support", DisplayName: "Dave", StartTime: at("2025-03-02T23:30:00Z")},
+			{ID: 14, ConferenceID: 3, ConferenceName: "Gone", DisplayName: "Eve", StartTime: at("2025-03-03T08:00:00Z")},
+		},
+		streams: []MediaStream{
+			{ID: 100, ParticipantID: 10, StreamType: "audio", Codec: "opus", RxPackets: 1000, RxPacketsLost: 10},
+			{ID: 101, ParticipantID: 10, StreamType: "video", Codec: "VP8", RxPackets: 5000, TxPackets: 4000, RxPacketsLost: 50, TxPacketsLost: 4},
+			{ID: 102, ParticipantID: 11, StreamType: "audio", Codec: "opus", RxPackets: 800},
+		},
+	}
+}
+
+func readJSONL(t *testing.T, data []byte) []map[string]interface{} {
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestService_Export(t *testing.T) {
+	fake := newFakeHistory()
+	cet := time.FixedZone("CET", 3600)
+
+	var buf bytes.Buffer
+	var checkpoints []Checkpoint
+	cp, err := New(fake).Export(t.Context(), NewJSONLWriter(&buf, nil), &ExportOptions{
+		From:         at("2025-03-01T00:00:00Z").Time,
+		To:           at("2025-03-04T00:00:00Z").Time,
+		PageSize:     2,
+		Location:     cet,
+		OnCheckpoint: func(cp Checkpoint) error { checkpoints = append(checkpoints, cp); return nil },
+	})
+	require.NoError(t, err)
+	assert.True(t, cp.Done())
+	assert.Equal(t, 5, cp.Records)
+	require.Len(t, checkpoints, 3)
+	assert.Equal(t, []int{3, 4, 5}, []int{checkpoints[0].Records, checkpoints[1].Records, checkpoints[2].Records})
+
+	records := readJSONL(t, buf.Bytes())
+	require.Len(t, records, 5)
+
+	alice := records[0]
+	assert.Equal(t, float64(10), alice["participant_id"])
+	assert.Equal(t, "2025-03-01T10:00:00+01:00", alice["start_time"])
+	assert.Equal(t, "emea", alice["conference_tag"])
+	assert.Equal(t, "2025-03-01T11:00:00+01:00", alice["conference_end_time"])
+	assert.Equal(t, float64(3), alice["conference_participants"])
+	assert.Equal(t, float64(2), alice["media_streams"])
+	assert.Equal(t, "VP8,opus", alice["codecs"])
+	assert.Equal(t, float64(60), alice["rx_packets_lost"])
+
+	dave := records[3]
+	assert.Equal(t, "lecture", dave["service_type"])
+	assert.Nil(t, dave["end_time"])
+	assert.Nil(t, dave["conference_end_time"])
+	assert.Equal(t, float64(0), dave["media_streams"])
+
+	eve := records[4]
+	assert.Equal(t, "Gone", eve["conference_name"])
+	assert.Nil(t, eve["conference_start_time"])
+
+	// Conferences are fetched once per window and streams once per page
+	assert.Equal(t, 3, fake.requests["history/v1/conference/"])
+	assert.Equal(t, 4, fake.requests["history/v1/media_stream/"])
+}
+
+func TestService_Export_Resume(t *testing.T) {
+	fake := newFakeHistory()
+	fake.failOn = 2
+	opts := &ExportOptions{
+		From:   at("2025-03-01T00:00:00Z").Time,
+		To:     at("2025-03-04T00:00:00Z").Time,
+		Window: 48 * time.Hour,
+	}
+
+	var buf bytes.Buffer
+	cp, err := New(fake).Export(t.Context(), NewJSONLWriter(&buf, nil), opts)
+	require.Error(t, err)
+	assert.Equal(t, 4, cp.Records)
+	assert.Equal(t, at("2025-03-03T00:00:00Z").Time, cp.Next)
+
+	opts.Resume = cp
+	cp, err = New(fake).Export(t.Context(), NewJSONLWriter(&buf, nil), opts)
+	require.NoError(t, err)
+	assert.True(t, cp.Done())
+	assert.Equal(t, 5, cp.Records)
+
+	var ids []float64
+	for _, record := range readJSONL(t, buf.Bytes()) {
+		ids = append(ids, record["participant_id"].(float64))
+	}
+	assert.Equal(t, []float64{10, 11, 12, 13, 14}, ids)
+
+	opts.From = opts.From.Add(time.Hour)
+	_, err = New(fake).Export(t.Context(), NewJSONLWriter(&buf, nil), opts)
+	assert.ErrorContains(t, err, "checkpoint covers")
+}
+
+func TestService_Export_ResumeFile(t *testing.T) {
+	fake := newFakeHistory()
+	fake.participants = append(fake.participants, Participant{ID: 15, ConferenceID: 3, ConferenceName: "Gone", StartTime: at("2025-03-03T09:00:00Z")})
+	fake.failOn = 6 // after the first page of the second window has been written
+	path := filepath.Join(t.TempDir(), "cdr.csv")
+	columns, err := SelectColumns("participant_id")
+	require.NoError(t, err)
+	opts := &ExportOptions{
+		From:     at("2025-03-01T00:00:00Z").Time,
+		To:       at("2025-03-04T00:00:00Z").Time,
+		Window:   48 * time.Hour,
+		PageSize: 1,
+	}
+
+	export := func(resume *Checkpoint) (*Checkpoint, error) {
+		f, err := OpenExportFile(path, resume)
+		require.NoError(t, err)
+		defer f.Close()
+		w := NewCSVWriter(f, columns, resume == nil)
+		defer func() { require.NoError(t, w.Close()) }()
+		opts.Resume = resume
+		return New(fake).Export(t.Context(), w, opts)
+	}
+
+	cp, err := export(nil)
+	require.Error(t, err)
+	assert.Equal(t, 4, cp.Records)
+	assert.Equal(t, int64(len("participant_id\n10\n11\n12\n13\n")), cp.Offset)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "participant_id\n10\n11\n12\n13\n14\n", string(data), "the interrupted window was partly written")
+
+	cp, err = export(cp)
+	require.NoError(t, err)
+	assert.Equal(t, 6, cp.Records)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "participant_id\n10\n11\n12\n13\n14\n15\n", string(data))
+	assert.Equal(t, int64(len(data)), cp.Offset)
+
+	_, err = OpenExportFile(path, &Checkpoint{Records: 4})
+	assert.ErrorContains(t, err, "no output offset")
+}
+
+func TestCheckpoint_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.checkpoint")
+
+	cp, err := LoadCheckpoint(path)
+	require.NoError(t, err)
+	assert.Nil(t, cp)
+
+	want := &Checkpoint{
+		From:    at("2025-03-01T00:00:00Z").Time,
+		To:      at("2025-03-04T00:00:00Z").Time,
+		Next:    at("2025-03-02T00:00:00Z").Time,
+		Records: 42,
+	}
+	require.NoError(t, want.Save(path))
+	require.NoError(t, want.Save(path))
+
+	cp, err = LoadCheckpoint(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, cp)
+	assert.False(t, cp.Done())
+
+	matches, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
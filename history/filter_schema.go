@@ -0,0 +1,73 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package history
+
+import "github.com/pexip/go-infinity-sdk/v41/options"
+
+// filterSchemas lists the filterable and orderable fields of history resources,
+// keyed by list endpoint. Filters on endpoints without a schema are sent unvalidated.
+var filterSchemas = map[string]*options.FilterSchema{
+	"history/v1/conference/": {
+		Fields: map[string][]options.Operator{
+			"id":                 options.NumericOperators,
+			"name":               options.TextOperators,
+			"service_type":       options.TextOperators,
+			"tag":                options.TextOperators,
+			"start_time":         options.TimeOperators,
+			"end_time":           options.TimeOperators,
+			"duration_seconds":   options.NumericOperators,
+			"total_participants": options.NumericOperators,
+		},
+		Ordering: []string{"id", "name", "start_time", "end_time", "duration_seconds", "total_participants"},
+	},
+	"history/v1/participant/": {
+		Fields: map[string][]options.Operator{
+			"id":                options.NumericOperators,
+			"conference_id":     options.NumericOperators,
+			"conference_name":   options.TextOperators,
+			"display_name":      options.TextOperators,
+			"local_alias":       options.TextOperators,
+			"remote_alias":      options.TextOperators,
+			"remote_address":    options.TextOperators,
+			"role":              options.TextOperators,
+			"service_type":      options.TextOperators,
+			"call_direction":    options.TextOperators,
+			"disconnect_reason": options.TextOperators,
+			"vendor":            options.TextOperators,
+			"media_node":        options.TextOperators,
+			"start_time":        options.TimeOperators,
+			"end_time":          options.TimeOperators,
+			"duration_seconds":  options.NumericOperators,
+		},
+		Ordering: []string{"id", "conference_name", "display_name", "start_time", "end_time", "duration_seconds"},
+	},
+	"history/v1/media_stream/": {
+		Fields: map[string][]options.Operator{
+			"id":               options.NumericOperators,
+			"participant_id":   options.NumericOperators,
+			"node":             options.TextOperators,
+			"stream_type":      options.TextOperators,
+			"direction":        options.TextOperators,
+			"codec":            options.TextOperators,
+			"start_time":       options.TimeOperators,
+			"end_time":         options.TimeOperators,
+			"duration_seconds": options.NumericOperators,
+		},
+		Ordering: []string{"id", "start_time", "end_time", "duration_seconds"},
+	},
+	"history/v1/alarm/": {
+		Fields: map[string][]options.Operator{
+			"id":           options.NumericOperators,
+			"name":         options.TextOperators,
+			"level":        options.TextOperators,
+			"node":         options.TextOperators,
+			"time_raised":  options.TimeOperators,
+			"time_lowered": options.TimeOperators,
+		},
+		Ordering: []string{"id", "name", "level", "node", "time_raised", "time_lowered"},
+	},
+}
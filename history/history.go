@@ -32,6 +32,9 @@ func New(client interfaces.HTTPClient) *Service {
 func (s *Service) listEndpoint(ctx context.Context, endpoint string, opts *ListOptions, result interface{}) error {
 	var params url.Values
 	if opts != nil {
+		if err := opts.Filter.Validate(filterSchemas[endpoint]); err != nil {
+			return err
+		}
 		params = opts.ToURLValues()
 	}
 	return s.client.GetJSON(ctx, endpoint, &params, result)
@@ -40,6 +43,9 @@ func (s *Service) listEndpoint(ctx context.Context, endpoint string, opts *ListO
 func (s *Service) listEndpointWithSearchField(ctx context.Context, endpoint string, opts *ListOptions, searchField string, result interface{}) error {
 	var params url.Values
 	if opts != nil {
+		if err := opts.Filter.Validate(filterSchemas[endpoint]); err != nil {
+			return err
+		}
 		if searchField != "" {
 			params = opts.ToURLValuesWithSearchField(searchField)
 		} else {
@@ -52,6 +58,9 @@ func (s *Service) listEndpointWithSearchField(ctx context.Context, endpoint stri
 func (s *Service) listEndpointWithTimeFilter(ctx context.Context, endpoint string, opts *ListOptions, result interface{}) error {
 	var params url.Values
 	if opts != nil {
+		if err := opts.Filter.Validate(filterSchemas[endpoint]); err != nil {
+			return err
+		}
 		params = opts.ToURLValues()
 		if opts.StartTime != nil {
 			params.Set("start_time__gte", opts.StartTime.Format(time.RFC3339))
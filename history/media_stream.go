@@ -9,9 +9,12 @@ package history
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 	"time"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListMediaStreams retrieves a list of media stream history records
@@ -23,6 +26,17 @@ func (s *Service) ListMediaStreams(ctx context.Context, opts *ListOptions) (*Med
 	return &result, err
 }
 
+// AllMediaStreams returns an iterator over all media stream history records, fetching further pages as needed
+func (s *Service) AllMediaStreams(ctx context.Context, opts *ListOptions) iter.Seq2[MediaStream, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MediaStream, options.Meta, error) {
+		result, err := s.ListMediaStreams(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMediaStream retrieves a specific media stream history record by ID
 func (s *Service) GetMediaStream(ctx context.Context, id int) (*MediaStream, error) {
 	endpoint := fmt.Sprintf("history/v1/media_stream/%d/", id)
@@ -40,11 +54,12 @@ func (s *Service) ListMediaStreamsByParticipant(ctx context.Context, participant
 	params.Set("participant_id", strconv.Itoa(participantID))
 
 	if opts != nil {
+		if err := opts.Filter.Validate(filterSchemas[endpoint]); err != nil {
+			return nil, err
+		}
 		optParams := opts.BaseListOptions.ToURLValues()
 		for key, values := range optParams {
-			for _, value := range values {
-				params.Set(key, value)
-			}
+			params[key] = values
 		}
 		if opts.StartTime != nil {
 			params.Set("start_time__gte", opts.StartTime.Format(time.RFC3339))
@@ -58,3 +73,14 @@ func (s *Service) ListMediaStreamsByParticipant(ctx context.Context, participant
 	err := s.client.GetJSON(ctx, endpoint, &params, &result)
 	return &result, err
 }
+
+// AllMediaStreamsByParticipant returns an iterator over all media stream history for a specific participant, fetching further pages as needed
+func (s *Service) AllMediaStreamsByParticipant(ctx context.Context, participantID int, opts *ListOptions) iter.Seq2[MediaStream, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MediaStream, options.Meta, error) {
+		result, err := s.ListMediaStreamsByParticipant(ctx, participantID, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
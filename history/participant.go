@@ -9,8 +9,11 @@ package history
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListParticipants retrieves a list of participant history records
@@ -22,6 +25,17 @@ func (s *Service) ListParticipants(ctx context.Context, opts *ListOptions) (*Par
 	return &result, err
 }
 
+// AllParticipants returns an iterator over all participant history records, fetching further pages as needed
+func (s *Service) AllParticipants(ctx context.Context, opts *ListOptions) iter.Seq2[Participant, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]Participant, options.Meta, error) {
+		result, err := s.ListParticipants(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetParticipant retrieves a specific participant history record by ID
 func (s *Service) GetParticipant(ctx context.Context, id int) (*Participant, error) {
 	endpoint := fmt.Sprintf("history/v1/participant/%d/", id)
@@ -39,11 +53,12 @@ func (s *Service) ListParticipantsByConference(ctx context.Context, conferenceID
 	params.Set("conference_id", strconv.Itoa(conferenceID))
 
 	if opts != nil {
+		if err := opts.Filter.Validate(filterSchemas[endpoint]); err != nil {
+			return nil, err
+		}
 		optParams := opts.ToURLValuesWithSearchField("display_name__icontains")
 		for key, values := range optParams {
-			for _, value := range values {
-				params.Set(key, value)
-			}
+			params[key] = values
 		}
 	}
 
@@ -51,3 +66,14 @@ func (s *Service) ListParticipantsByConference(ctx context.Context, conferenceID
 	err := s.client.GetJSON(ctx, endpoint, &params, &result)
 	return &result, err
 }
+
+// AllParticipantsByConference returns an iterator over all participant history for a specific conference, fetching further pages as needed
+func (s *Service) AllParticipantsByConference(ctx context.Context, conferenceID int, opts *ListOptions) iter.Seq2[Participant, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]Participant, options.Meta, error) {
+		result, err := s.ListParticipantsByConference(ctx, conferenceID, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
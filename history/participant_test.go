@@ -7,6 +7,7 @@
 package history
 
 import (
+	"net/url"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/pexip/go-infinity-sdk/v41/options"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestService_ListParticipants(t *testing.T) {
@@ -193,3 +195,56 @@ func TestService_ListParticipantsByConference_WithOptions(t *testing.T) {
 
 	client.AssertExpectations(t)
 }
+
+func TestService_AllParticipantsByConference(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+
+	firstPage := url.Values{"conference_id": []string{"123"}, "limit": []string{"1"}}
+	secondPage := url.Values{"conference_id": []string{"123"}, "limit": []string{"1"}, "offset": []string{"1"}}
+
+	client.On("GetJSON", t.Context(), "history/v1/participant/", &firstPage, mock.AnythingOfType("*history.ParticipantListResponse")).Return(nil).Run(func(args mock.Arguments) {
+		result := args.Get(3).(*ParticipantListResponse)
+		result.Meta.Limit = 1
+		result.Meta.Next = "/api/admin/history/v1/participant/?conference_id=123&limit=1&offset=1"
+		result.Objects = []Participant{{ID: 1, ConferenceID: 123, DisplayName: "Alice"}}
+	})
+	client.On("GetJSON", t.Context(), "history/v1/participant/", &secondPage, mock.AnythingOfType("*history.ParticipantListResponse")).Return(nil).Run(func(args mock.Arguments) {
+		result := args.Get(3).(*ParticipantListResponse)
+		result.Meta.Limit = 1
+		result.Meta.Offset = 1
+		result.Objects = []Participant{{ID: 2, ConferenceID: 123, DisplayName: "Bob"}}
+	})
+
+	opts := &ListOptions{}
+	opts.Limit = 1
+
+	service := New(client)
+	var names []string
+	for participant, err := range service.AllParticipantsByConference(t.Context(), 123, opts) {
+		require.NoError(t, err)
+		names = append(names, participant.DisplayName)
+	}
+
+	assert.Equal(t, []string{"Alice", "Bob"}, names)
+	client.AssertExpectations(t)
+}
+
+func TestService_ListParticipantsByConference_WithFilter(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+
+	expectedParams := url.Values{
+		"conference_id":     []string{"123"},
+		"vendor__icontains": []string{"cisco"},
+		"order_by":          []string{"-start_time", "display_name"},
+	}
+	client.On("GetJSON", t.Context(), "history/v1/participant/", &expectedParams, mock.AnythingOfType("*history.ParticipantListResponse")).Return(nil)
+
+	opts := &ListOptions{}
+	opts.Filter = options.NewFilter().Where("vendor", options.IContains, "cisco").OrderBy("-start_time", "display_name")
+
+	service := New(client)
+	_, err := service.ListParticipantsByConference(t.Context(), 123, opts)
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
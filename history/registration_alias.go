@@ -9,6 +9,9 @@ package history
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListRegistrationAliases retrieves a list of registration alias history records
@@ -21,6 +24,17 @@ func (s *Service) ListRegistrationAliases(ctx context.Context, opts *ListOptions
 	return &result, err
 }
 
+// AllRegistrationAliases returns an iterator over all registration alias history records, fetching further pages as needed
+func (s *Service) AllRegistrationAliases(ctx context.Context, opts *ListOptions) iter.Seq2[RegistrationAlias, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]RegistrationAlias, options.Meta, error) {
+		result, err := s.ListRegistrationAliases(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetRegistrationAlias retrieves a specific registration alias history record by ID
 func (s *Service) GetRegistrationAlias(ctx context.Context, id int) (*RegistrationAlias, error) {
 	endpoint := fmt.Sprintf("history/v1/registration_alias/%d/", id)
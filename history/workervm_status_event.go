@@ -9,6 +9,9 @@ package history
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListWorkerVMStatusEvents retrieves a list of worker VM status event history records
@@ -20,6 +23,17 @@ func (s *Service) ListWorkerVMStatusEvents(ctx context.Context, opts *ListOption
 	return &result, err
 }
 
+// AllWorkerVMStatusEvents returns an iterator over all worker VM status event history records, fetching further pages as needed
+func (s *Service) AllWorkerVMStatusEvents(ctx context.Context, opts *ListOptions) iter.Seq2[WorkerVMStatusEvent, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]WorkerVMStatusEvent, options.Meta, error) {
+		result, err := s.ListWorkerVMStatusEvents(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetWorkerVMStatusEvent retrieves a specific worker VM status event history record by ID
 func (s *Service) GetWorkerVMStatusEvent(ctx context.Context, id int) (*WorkerVMStatusEvent, error) {
 	endpoint := fmt.Sprintf("history/v1/workervm_status_event/%d/", id)
@@ -0,0 +1,25 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package infinity
+
+import "context"
+
+// Handler performs an API request. The Response is returned alongside an APIError for error responses.
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Interceptor wraps a Handler to observe or change requests and responses, for example to add
+// audit logging, headers, caching or fault injection. An interceptor may return a response
+// without calling next.
+type Interceptor func(next Handler) Handler
+
+// chain wraps handler in interceptors so that the first interceptor is the outermost
+func chain(interceptors []Interceptor, handler Handler) Handler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		handler = interceptors[i](handler)
+	}
+	return handler
+}
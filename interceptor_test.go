@@ -0,0 +1,114 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package infinity
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_WithInterceptor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "audit-1", r.Header.Get("X-Request-ID"))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	var calls []string
+	trace := func(name string) Interceptor {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				calls = append(calls, name+" before")
+				resp, err := next(ctx, req)
+				calls = append(calls, name+" after")
+				return resp, err
+			}
+		}
+	}
+	inject := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			assert.Equal(t, map[string]string{"name": "sales"}, req.Body)
+			if req.Headers == nil {
+				req.Headers = make(map[string]string)
+			}
+			req.Headers["X-Request-ID"] = "audit-1"
+			return next(ctx, req)
+		}
+	}
+
+	client, err := New(WithBaseURL(server.URL), WithInterceptor(trace("outer")), WithInterceptor(trace("inner")), WithInterceptor(inject))
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	err = client.PostJSON(t.Context(), "configuration/v1/conference/", map[string]string{"name": "sales"}, &result)
+
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), result["id"])
+	assert.Equal(t, []string{"outer before", "inner before", "inner after", "outer after"}, calls)
+}
+
+func TestClient_WithInterceptor_ShortCircuit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach the server")
+	}))
+	defer server.Close()
+
+	cache := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if req.Method == http.MethodGet && req.Endpoint == "status/v1/licensing/" {
+				return &Response{StatusCode: http.StatusOK, Body: []byte(`{"objects": []}`)}, nil
+			}
+			return next(ctx, req)
+		}
+	}
+
+	client, err := New(WithBaseURL(server.URL), WithInterceptor(cache))
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	err = client.GetJSON(t.Context(), "status/v1/licensing/", nil, &result)
+
+	require.NoError(t, err)
+	assert.Contains(t, result, "objects")
+}
+
+func TestClient_WithInterceptor_SeesAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	var seen *Response
+	var seenErr error
+	observe := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			seen, seenErr = next(ctx, req)
+			return seen, seenErr
+		}
+	}
+
+	client, err := New(WithBaseURL(server.URL), WithInterceptor(observe))
+	require.NoError(t, err)
+
+	err = client.DeleteJSON(t.Context(), "configuration/v1/conference/9/", nil)
+
+	assert.True(t, IsNotFound(err))
+	require.NotNil(t, seen)
+	assert.Equal(t, http.StatusNotFound, seen.StatusCode)
+	assert.True(t, IsNotFound(seenErr))
+}
+
+func TestWithInterceptor_Nil(t *testing.T) {
+	_, err := New(WithInterceptor(nil))
+	assert.EqualError(t, err, "interceptor cannot be nil")
+}
@@ -0,0 +1,96 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package parquet
+
+import "encoding/binary"
+
+// Thrift compact protocol type codes
+const (
+	tcI32    = 5
+	tcI64    = 6
+	tcBinary = 8
+	tcList   = 9
+	tcStruct = 12
+)
+
+// thriftWriter encodes Parquet metadata with the Thrift compact protocol
+type thriftWriter struct {
+	buf       []byte
+	lastField []int16
+}
+
+func (w *thriftWriter) varint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func (w *thriftWriter) fieldHeader(id int16, typ byte) {
+	last := &w.lastField[len(w.lastField)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.varint(zigzag(int64(id)))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) beginStruct() {
+	w.lastField = append(w.lastField, 0)
+}
+
+func (w *thriftWriter) endStruct() {
+	w.buf = append(w.buf, 0)
+	w.lastField = w.lastField[:len(w.lastField)-1]
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.fieldHeader(id, tcI32)
+	w.varint(zigzag(int64(v)))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.fieldHeader(id, tcI64)
+	w.varint(zigzag(v))
+}
+
+func (w *thriftWriter) str(id int16, v string) {
+	w.fieldHeader(id, tcBinary)
+	w.varint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// structField begins a nested struct field, to be ended with endStruct
+func (w *thriftWriter) structField(id int16) {
+	w.fieldHeader(id, tcStruct)
+	w.beginStruct()
+}
+
+// list writes a list field header; the caller then writes n elements of type elem
+func (w *thriftWriter) list(id int16, elem byte, n int) {
+	w.fieldHeader(id, tcList)
+	if n < 15 {
+		w.buf = append(w.buf, byte(n)<<4|elem)
+	} else {
+		w.buf = append(w.buf, 0xf0|elem)
+		w.varint(uint64(n))
+	}
+}
+
+// i32Elem writes an i32 list element
+func (w *thriftWriter) i32Elem(v int32) {
+	w.varint(zigzag(int64(v)))
+}
+
+// strElem writes a binary list element
+func (w *thriftWriter) strElem(v string) {
+	w.varint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
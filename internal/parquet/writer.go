@@ -0,0 +1,346 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package parquet writes flat tables of optional columns as Apache Parquet files. It supports only
+// what tabular exports need: uncompressed PLAIN-encoded pages, one page per column chunk and a row
+// group per Flush.
+package parquet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Type is the type of a column
+type Type int
+
+const (
+	String Type = iota
+	Int64
+	Double
+	Boolean
+	// Timestamp is stored as milliseconds since the Unix epoch in UTC
+	Timestamp
+)
+
+// Parquet physical types, converted types, repetition types and encodings
+const (
+	physBoolean   = 0
+	physInt64     = 2
+	physDouble    = 5
+	physByteArray = 6
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	repetitionOptional = 1
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	pageTypeData = 0
+	codecNone    = 0
+)
+
+const magic = "PAR1"
+
+// ErrClosed is returned when writing to a closed Writer
+var ErrClosed = errors.New("parquet writer is closed")
+
+// Column is a column of the table
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Writer writes rows to a Parquet file
+type Writer struct {
+	w       io.Writer
+	columns []Column
+	offset  int64
+	rows    [][]interface{}
+	groups  []rowGroup
+	closed  bool
+}
+
+type columnChunk struct {
+	offset int64
+	size   int64
+	values int64
+}
+
+type rowGroup struct {
+	chunks []columnChunk
+	rows   int64
+	size   int64
+}
+
+// NewWriter creates a Writer of the given columns to w
+func NewWriter(w io.Writer, columns []Column) *Writer {
+	return &Writer{w: w, columns: columns}
+}
+
+// Write buffers a row. Values must be nil, string, int64 (or int), float64, bool or time.Time to match
+// the column types.
+func (w *Writer) Write(row []interface{}) error {
+	if w.closed {
+		return ErrClosed
+	}
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("row has %d values, want %d", len(row), len(w.columns))
+	}
+	for i, v := range row {
+		if v != nil && !compatible(w.columns[i].Type, v) {
+			return fmt.Errorf("column %s: unexpected %T", w.columns[i].Name, v)
+		}
+	}
+	w.rows = append(w.rows, row)
+	return nil
+}
+
+// Rows returns the number of rows buffered since the last Flush
+func (w *Writer) Rows() int {
+	return len(w.rows)
+}
+
+func compatible(t Type, v interface{}) bool {
+	switch v.(type) {
+	case string:
+		return t == String
+	case int, int64:
+		return t == Int64
+	case float64:
+		return t == Double
+	case bool:
+		return t == Boolean
+	case time.Time:
+		return t == Timestamp
+	}
+	return false
+}
+
+// Flush writes the buffered rows as a row group
+func (w *Writer) Flush() error {
+	if w.closed {
+		return ErrClosed
+	}
+	if len(w.rows) == 0 {
+		return nil
+	}
+	if w.offset == 0 {
+		if err := w.write([]byte(magic)); err != nil {
+			return err
+		}
+	}
+	group := rowGroup{rows: int64(len(w.rows))}
+	for i := range w.columns {
+		chunk, err := w.writeColumn(i)
+		if err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, chunk)
+		group.size += chunk.size
+	}
+	w.groups = append(w.groups, group)
+	w.rows = w.rows[:0]
+	return nil
+}
+
+// Close flushes the buffered rows and writes the file footer. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	w.closed = true
+	if w.offset == 0 {
+		if err := w.write([]byte(magic)); err != nil {
+			return err
+		}
+	}
+	footer := w.footer()
+	footer = binary.LittleEndian.AppendUint32(footer, uint32(len(footer)))
+	footer = append(footer, magic...)
+	return w.write(footer)
+}
+
+func (w *Writer) write(b []byte) error {
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+	return err
+}
+
+// writeColumn writes column i of the buffered rows as a single data page
+func (w *Writer) writeColumn(i int) (columnChunk, error) {
+	col := w.columns[i]
+	defined := make([]bool, len(w.rows))
+	var values []byte
+	var bits []bool
+	for r, row := range w.rows {
+		v := row[i]
+		if v == nil {
+			continue
+		}
+		defined[r] = true
+		switch v := v.(type) {
+		case string:
+			values = binary.LittleEndian.AppendUint32(values, uint32(len(v)))
+			values = append(values, v...)
+		case int:
+			values = binary.LittleEndian.AppendUint64(values, uint64(v))
+		case int64:
+			values = binary.LittleEndian.AppendUint64(values, uint64(v))
+		case float64:
+			values = binary.LittleEndian.AppendUint64(values, math.Float64bits(v))
+		case bool:
+			bits = append(bits, v)
+		case time.Time:
+			values = binary.LittleEndian.AppendUint64(values, uint64(v.UnixMilli()))
+		}
+	}
+	if col.Type == Boolean {
+		values = packBits(bits)
+	}
+
+	levels := encodeLevels(defined)
+	page := binary.LittleEndian.AppendUint32(nil, uint32(len(levels)))
+	page = append(page, levels...)
+	page = append(page, values...)
+
+	h := &thriftWriter{}
+	h.beginStruct()
+	h.i32(1, pageTypeData)
+	h.i32(2, int32(len(page)))
+	h.i32(3, int32(len(page)))
+	h.structField(5)
+	h.i32(1, int32(len(w.rows)))
+	h.i32(2, encodingPlain)
+	h.i32(3, encodingRLE)
+	h.i32(4, encodingRLE)
+	h.endStruct()
+	h.endStruct()
+
+	chunk := columnChunk{offset: w.offset, values: int64(len(w.rows))}
+	if err := w.write(h.buf); err != nil {
+		return chunk, err
+	}
+	if err := w.write(page); err != nil {
+		return chunk, err
+	}
+	chunk.size = w.offset - chunk.offset
+	return chunk, nil
+}
+
+// encodeLevels encodes definition levels with a bit width of 1 as RLE runs
+func encodeLevels(defined []bool) []byte {
+	var out []byte
+	for i := 0; i < len(defined); {
+		j := i
+		for j < len(defined) && defined[j] == defined[i] {
+			j++
+		}
+		out = binary.AppendUvarint(out, uint64(j-i)<<1)
+		if defined[i] {
+			out = append(out, 1)
+		} else {
+			out = append(out, 0)
+		}
+		i = j
+	}
+	return out
+}
+
+// packBits packs booleans least significant bit first, as PLAIN encoding requires
+func packBits(bits []bool) []byte {
+	out := make([]byte, (len(bits)+7)/8)
+	for i, b := range bits {
+		if b {
+			out[i/8] |= 1 << (i % 8)
+		}
+	}
+	return out
+}
+
+func (c Column) physicalType() int32 {
+	switch c.Type {
+	case Int64, Timestamp:
+		return physInt64
+	case Double:
+		return physDouble
+	case Boolean:
+		return physBoolean
+	}
+	return physByteArray
+}
+
+// footer encodes the FileMetaData
+func (w *Writer) footer() []byte {
+	var rows int64
+	for _, g := range w.groups {
+		rows += g.rows
+	}
+
+	t := &thriftWriter{}
+	t.beginStruct()
+	t.i32(1, 1)
+
+	t.list(2, tcStruct, len(w.columns)+1)
+	t.beginStruct()
+	t.str(4, "schema")
+	t.i32(5, int32(len(w.columns)))
+	t.endStruct()
+	for _, c := range w.columns {
+		t.beginStruct()
+		t.i32(1, c.physicalType())
+		t.i32(3, repetitionOptional)
+		t.str(4, c.Name)
+		switch c.Type {
+		case String:
+			t.i32(6, convertedUTF8)
+		case Timestamp:
+			t.i32(6, convertedTimestampMillis)
+		}
+		t.endStruct()
+	}
+
+	t.i64(3, rows)
+	t.list(4, tcStruct, len(w.groups))
+	for _, g := range w.groups {
+		t.beginStruct()
+		t.list(1, tcStruct, len(g.chunks))
+		for i, chunk := range g.chunks {
+			c := w.columns[i]
+			t.beginStruct()
+			t.i64(2, chunk.offset)
+			t.structField(3)
+			t.i32(1, c.physicalType())
+			t.list(2, tcI32, 2)
+			t.i32Elem(encodingPlain)
+			t.i32Elem(encodingRLE)
+			t.list(3, tcBinary, 1)
+			t.strElem(c.Name)
+			t.i32(4, codecNone)
+			t.i64(5, chunk.values)
+			t.i64(6, chunk.size)
+			t.i64(7, chunk.size)
+			t.i64(9, chunk.offset)
+			t.endStruct()
+			t.endStruct()
+		}
+		t.i64(2, g.size)
+		t.i64(3, g.rows)
+		t.endStruct()
+	}
+	t.str(6, "go-infinity-sdk")
+	t.endStruct()
+	return t.buf
+}
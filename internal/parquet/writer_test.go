@@ -0,0 +1,213 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// thriftReader decodes Thrift compact structs into maps of field ID to value, enough to check the
+// metadata the Writer produces
+type thriftReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) varint() int64 {
+	u := r.uvarint()
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (r *thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case tcI32, tcI64:
+		return r.varint()
+	case tcBinary:
+		n := int(r.uvarint())
+		s := string(r.buf[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case tcList:
+		header := r.buf[r.pos]
+		r.pos++
+		n, elem := int(header>>4), header&0x0f
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]interface{}, n)
+		for i := range list {
+			list[i] = r.value(elem)
+		}
+		return list
+	case tcStruct:
+		return r.structValue()
+	}
+	panic("unsupported thrift type")
+}
+
+func (r *thriftReader) structValue() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+	var last int16
+	for {
+		header := r.buf[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields
+		}
+		typ := header & 0x0f
+		if delta := int16(header >> 4); delta != 0 {
+			last += delta
+		} else {
+			last = int16(r.varint())
+		}
+		fields[last] = r.value(typ)
+	}
+}
+
+// readFile decodes a file written by Writer into its metadata and the values of each column
+func readFile(t *testing.T, data []byte) (map[int16]interface{}, [][]interface{}) {
+	t.Helper()
+	require.Equal(t, magic, string(data[:4]))
+	require.Equal(t, magic, string(data[len(data)-4:]))
+	size := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	meta := (&thriftReader{buf: data[len(data)-8-size : len(data)-8]}).structValue()
+
+	schema := meta[2].([]interface{})[1:]
+	columns := make([][]interface{}, len(schema))
+	for _, g := range meta[4].([]interface{}) {
+		for i, c := range g.(map[int16]interface{})[1].([]interface{}) {
+			cm := c.(map[int16]interface{})[3].(map[int16]interface{})
+			r := &thriftReader{buf: data, pos: int(cm[9].(int64))}
+			header := r.structValue()
+			page := data[r.pos : r.pos+int(header[3].(int64))]
+			numValues := int(header[5].(map[int16]interface{})[1].(int64))
+			columns[i] = append(columns[i], decodePage(t, cm[1].(int64), page, numValues)...)
+		}
+	}
+	return meta, columns
+}
+
+func decodePage(t *testing.T, physical int64, page []byte, n int) []interface{} {
+	t.Helper()
+	levelsLen := int(binary.LittleEndian.Uint32(page))
+	levels := &thriftReader{buf: page[4 : 4+levelsLen]}
+	var defined []bool
+	for len(defined) < n {
+		count := int(levels.uvarint() >> 1)
+		v := levels.buf[levels.pos] == 1
+		levels.pos++
+		for range count {
+			defined = append(defined, v)
+		}
+	}
+	values := page[4+levelsLen:]
+	out := make([]interface{}, n)
+	bit := 0
+	for i, d := range defined {
+		if !d {
+			continue
+		}
+		switch physical {
+		case physByteArray:
+			l := int(binary.LittleEndian.Uint32(values))
+			out[i] = string(values[4 : 4+l])
+			values = values[4+l:]
+		case physInt64:
+			out[i] = int64(binary.LittleEndian.Uint64(values))
+			values = values[8:]
+		case physDouble:
+			out[i] = math.Float64frombits(binary.LittleEndian.Uint64(values))
+			values = values[8:]
+		case physBoolean:
+			out[i] = values[bit/8]&(1<<(bit%8)) != 0
+			bit++
+		}
+	}
+	return out
+}
+
+func TestWriter(t *testing.T) {
+	columns := []Column{
+		{Name: "name", Type: String},
+		{Name: "duration", Type: Int64},
+		{Name: "loss", Type: Double},
+		{Name: "encrypted", Type: Boolean},
+		{Name: "start", Type: Timestamp},
+	}
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, columns)
+	require.NoError(t, w.Write([]interface{}{"alice", int64(60), 0.5, true, start}))
+	require.NoError(t, w.Write([]interface{}{nil, 30, nil, false, nil}))
+	require.NoError(t, w.Flush())
+	require.NoError(t, w.Write([]interface{}{"carol", nil, 1.25, nil, start.Add(time.Minute)}))
+	require.NoError(t, w.Close())
+	assert.ErrorIs(t, w.Write([]interface{}{"x", nil, nil, nil, nil}), ErrClosed)
+
+	meta, values := readFile(t, buf.Bytes())
+	assert.Equal(t, int64(3), meta[3])
+	assert.Len(t, meta[4], 2)
+	schema := meta[2].([]interface{})
+	require.Len(t, schema, 6)
+	assert.Equal(t, "schema", schema[0].(map[int16]interface{})[4])
+	assert.Equal(t, int64(5), schema[0].(map[int16]interface{})[5])
+	assert.Equal(t, "start", schema[5].(map[int16]interface{})[4])
+	assert.Equal(t, int64(convertedTimestampMillis), schema[5].(map[int16]interface{})[6])
+
+	assert.Equal(t, []interface{}{"alice", nil, "carol"}, values[0])
+	assert.Equal(t, []interface{}{int64(60), int64(30), nil}, values[1])
+	assert.Equal(t, []interface{}{0.5, nil, 1.25}, values[2])
+	assert.Equal(t, []interface{}{true, false, nil}, values[3])
+	assert.Equal(t, []interface{}{start.UnixMilli(), nil, start.Add(time.Minute).UnixMilli()}, values[4])
+}
+
+func TestWriter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, []Column{{Name: "name", Type: String}})
+	require.NoError(t, w.Close())
+
+	meta, _ := readFile(t, buf.Bytes())
+	assert.Equal(t, int64(0), meta[3])
+	assert.Empty(t, meta[4])
+}
+
+func TestWriter_InvalidRow(t *testing.T) {
+	w := NewWriter(&bytes.Buffer{}, []Column{{Name: "name", Type: String}})
+	assert.Error(t, w.Write([]interface{}{1}))
+	assert.Error(t, w.Write([]interface{}{"a", "b"}))
+}
+
+func TestWriter_ManyColumns(t *testing.T) {
+	columns := make([]Column, 20)
+	row := make([]interface{}, 20)
+	for i := range columns {
+		columns[i] = Column{Name: string(rune('a' + i)), Type: Int64}
+		row[i] = int64(i)
+	}
+	var buf bytes.Buffer
+	w := NewWriter(&buf, columns)
+	require.NoError(t, w.Write(row))
+	require.NoError(t, w.Close())
+
+	meta, values := readFile(t, buf.Bytes())
+	assert.Len(t, meta[2], 21)
+	assert.Equal(t, []interface{}{int64(19)}, values[19])
+}
@@ -0,0 +1,141 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package redact identifies secret fields and headers and masks their values.
+package redact
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Mask replaces redacted values
+const Mask = "[REDACTED]"
+
+// secretSuffixes identify secret fields, such as password, ldap_bind_password,
+// snmp_privacy_password or private_key, by the end of their name
+var secretSuffixes = []string{
+	"password", "passphrase", "secret", "secret_key", "private_key", "private_key_jwt", "refresh_token", "encryption_key",
+	"assertion", "id_token",
+}
+
+// secretHeaders carry credentials
+var secretHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Csrftoken", "Proxy-Authorization"}
+
+// secretHeaderParts identify custom credential headers, such as X-Api-Key or X-Auth-Token, by
+// part of their name
+var secretHeaderParts = []string{"auth", "token", "api-key", "apikey", "secret", "password", "session", "csrf"}
+
+// IsSecret reports whether a resource field holds a secret
+func IsSecret(field string) bool {
+	field = strings.ToLower(field)
+	if field == "token" || field == "access_token" {
+		return true
+	}
+	for _, suffix := range secretSuffixes {
+		if strings.HasSuffix(field, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Body returns body with secrets masked according to its content type. Form encoded bodies are
+// masked by key and anything else is treated as JSON.
+func Body(body []byte, contentType string) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType == "application/x-www-form-urlencoded" {
+		return Form(body)
+	}
+	return JSON(body)
+}
+
+// JSON returns body with the values of secret fields masked at any depth. Bodies that are not
+// valid JSON are masked whole.
+func JSON(body []byte) []byte {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return []byte(Mask)
+	}
+	masked, err := json.Marshal(value(v))
+	if err != nil {
+		return []byte(Mask)
+	}
+	return masked
+}
+
+// Form returns a form encoded body with the values of secret keys masked. Bodies that cannot be
+// parsed are masked whole.
+func Form(body []byte) []byte {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return []byte(Mask)
+	}
+	for key, vals := range values {
+		if !IsSecret(key) {
+			continue
+		}
+		for i := range vals {
+			vals[i] = Mask
+		}
+	}
+	return []byte(values.Encode())
+}
+
+func value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for field, item := range v {
+			if s, ok := item.(string); ok && s != "" && IsSecret(field) {
+				v[field] = Mask
+				continue
+			}
+			v[field] = value(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = value(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// Header returns a copy of h with credentials masked
+func Header(h http.Header) http.Header {
+	masked := h.Clone()
+	for name, vals := range masked {
+		if !isSecretHeader(name) {
+			continue
+		}
+		for i := range vals {
+			vals[i] = Mask
+		}
+	}
+	return masked
+}
+
+func isSecretHeader(name string) bool {
+	for _, secret := range secretHeaders {
+		if strings.EqualFold(name, secret) {
+			return true
+		}
+	}
+	name = strings.ToLower(name)
+	for _, part := range secretHeaderParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
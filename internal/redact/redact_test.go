@@ -0,0 +1,72 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package redact
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSecret(t *testing.T) {
+	for _, field := range []string{
+		"password", "ldap_bind_password", "snmp_authentication_password", "snmp_privacy_password",
+		"private_key", "passphrase", "oidc_client_secret", "token", "Password",
+	} {
+		assert.True(t, IsSecret(field), field)
+	}
+	for _, field := range []string{"name", "username", "certificate", "token_endpoint", "password_policy"} {
+		assert.False(t, IsSecret(field), field)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	body := []byte(`{"name":"ldap","ldap_bind_password":"hunter2","nested":[{"private_key":"pem","empty_password":""}]}`)
+	assert.JSONEq(t,
+		`{"name":"ldap","ldap_bind_password":"[REDACTED]","nested":[{"private_key":"[REDACTED]","empty_password":""}]}`,
+		string(JSON(body)))
+
+	assert.Equal(t, Mask, string(JSON([]byte("password=hunter2"))))
+}
+
+func TestForm(t *testing.T) {
+	body := []byte("grant_type=client_credentials&client_assertion=eyJhbGciOi&password=hunter2&username=admin")
+	values, err := url.ParseQuery(string(Form(body)))
+	require.NoError(t, err)
+	assert.Equal(t, "client_credentials", values.Get("grant_type"))
+	assert.Equal(t, "admin", values.Get("username"))
+	assert.Equal(t, Mask, values.Get("client_assertion"))
+	assert.Equal(t, Mask, values.Get("password"))
+
+	assert.Equal(t, Mask, string(Form([]byte("password=%zz"))))
+}
+
+func TestBody(t *testing.T) {
+	form := []byte("password=hunter2")
+	assert.Equal(t, "password=%5BREDACTED%5D", string(Body(form, "application/x-www-form-urlencoded; charset=utf-8")))
+	assert.Equal(t, Mask, string(Body(form, "text/plain")))
+	assert.JSONEq(t, `{"password":"[REDACTED]"}`, string(Body([]byte(`{"password":"hunter2"}`), "")))
+	assert.Empty(t, Body(nil, "application/json"))
+}
+
+func TestHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Basic YWRtaW46YWRtaW4=")
+	h.Set("Content-Type", "application/json")
+	h.Set("X-Api-Key", "key")
+	h.Set("X-CSRFToken", "csrf")
+
+	masked := Header(h)
+	assert.Equal(t, Mask, masked.Get("Authorization"))
+	assert.Equal(t, Mask, masked.Get("X-Api-Key"))
+	assert.Equal(t, Mask, masked.Get("X-CSRFToken"))
+	assert.Equal(t, "application/json", masked.Get("Content-Type"))
+	assert.Equal(t, "Basic YWRtaW46YWRtaW4=", h.Get("Authorization"))
+}
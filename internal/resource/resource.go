@@ -0,0 +1,166 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package resource provides a generic JSON representation of configuration resources
+// and adapters from the typed Configuration API methods to that representation.
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+	"slices"
+
+	"github.com/pexip/go-infinity-sdk/v41/config"
+	"github.com/pexip/go-infinity-sdk/v41/types"
+)
+
+// Object is the generic JSON representation of a resource
+type Object = map[string]interface{}
+
+// MetaFields are set by the API and never sent in create or update requests
+var MetaFields = []string{"id", "resource_uri", "creation_time"}
+
+// ListFunc lists every resource of one type
+type ListFunc func(ctx context.Context) ([]Object, error)
+
+// CreateFunc creates a resource and returns its resource URI
+type CreateFunc func(ctx context.Context, obj Object) (string, error)
+
+// UpdateFunc updates the resource with the given ID
+type UpdateFunc func(ctx context.Context, id int, obj Object) error
+
+// ListWith adapts an All* iterator of the Configuration API to a ListFunc
+func ListWith[T any](all func(ctx context.Context, opts *config.ListOptions) iter.Seq2[T, error]) ListFunc {
+	return func(ctx context.Context) ([]Object, error) {
+		var objects []Object
+		for item, err := range all(ctx, nil) {
+			if err != nil {
+				return nil, err
+			}
+			obj, err := ToObject(item)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, obj)
+		}
+		return objects, nil
+	}
+}
+
+// CreateWith adapts a Create* method of the Configuration API to a CreateFunc
+func CreateWith[R any](create func(ctx context.Context, req *R) (*types.PostResponse, error)) CreateFunc {
+	return func(ctx context.Context, obj Object) (string, error) {
+		var req R
+		if err := FromObject(obj, &req); err != nil {
+			return "", err
+		}
+		resp, err := create(ctx, &req)
+		if err != nil {
+			return "", err
+		}
+		return Path(resp.ResourceURI), nil
+	}
+}
+
+// UpdateWith adapts an Update* method of the Configuration API to an UpdateFunc
+func UpdateWith[R, T any](update func(ctx context.Context, id int, req *R) (*T, error)) UpdateFunc {
+	return func(ctx context.Context, id int, obj Object) error {
+		var req R
+		if err := FromObject(obj, &req); err != nil {
+			return err
+		}
+		_, err := update(ctx, id, &req)
+		return err
+	}
+}
+
+// ToObject converts a typed resource to its generic representation
+func ToObject(v interface{}) (Object, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource: %w", err)
+	}
+	var obj Object
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resource: %w", err)
+	}
+	return obj, nil
+}
+
+// FromObject decodes obj into a typed request, flattening nested resources and
+// dropping fields set by the API
+func FromObject(obj Object, v interface{}) error {
+	body := Flatten(obj)
+	for _, field := range MetaFields {
+		delete(body, field)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	return nil
+}
+
+// Flatten returns a copy of obj with nested resources replaced by their resource URI,
+// which is how references are written in create and update requests
+func Flatten(obj Object) Object {
+	flat := make(Object, len(obj))
+	for field, value := range obj {
+		flat[field] = flattenValue(value)
+	}
+	return flat
+}
+
+func flattenValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if uri, ok := v["resource_uri"].(string); ok && uri != "" {
+			return uri
+		}
+		return v
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = flattenValue(item)
+		}
+		return values
+	default:
+		return v
+	}
+}
+
+// WithoutMeta returns a copy of obj without the fields set by the API
+func WithoutMeta(obj Object) Object {
+	out := make(Object, len(obj))
+	for field, value := range obj {
+		if !slices.Contains(MetaFields, field) {
+			out[field] = value
+		}
+	}
+	return out
+}
+
+// ID returns the numeric ID of obj, or zero if it has none
+func ID(obj Object) int {
+	id, _ := obj["id"].(float64)
+	return int(id)
+}
+
+// Path strips the scheme and host from a Location header so that it matches
+// the resource URIs returned by the API
+func Path(location string) string {
+	u, err := url.Parse(location)
+	if err != nil {
+		return location
+	}
+	return u.Path
+}
@@ -0,0 +1,451 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package licensing tracks licence usage over time. A Sampler records licensing status at an interval,
+// and Analyze turns the samples and the installed licences into a Report with peak and percentile
+// utilisation per licence type, projected exhaustion dates, expiring entitlements and alerts.
+package licensing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	infinity "github.com/pexip/go-infinity-sdk/v41"
+	"github.com/pexip/go-infinity-sdk/v41/config"
+	"github.com/pexip/go-infinity-sdk/v41/status"
+)
+
+const (
+	// DefaultInterval is the default time between samples
+	DefaultInterval = 5 * time.Minute
+	// DefaultRetention is the default age after which samples are discarded
+	DefaultRetention = 30 * 24 * time.Hour
+)
+
+// Type is a licence type reported by licensing status
+type Type string
+
+// Licence types, in the order they are reported
+const (
+	Port       Type = "port"
+	VMR        Type = "vmr"
+	Audio      Type = "audio"
+	System     Type = "system"
+	Teams      Type = "teams"
+	GHM        Type = "ghm"
+	OTJ        Type = "otj"
+	Scheduling Type = "scheduling"
+	Telehealth Type = "telehealth"
+)
+
+// Types lists every licence type
+var Types = []Type{Port, VMR, Audio, System, Teams, GHM, OTJ, Scheduling, Telehealth}
+
+// Usage is the number of licences of a type in use and installed
+type Usage struct {
+	Used  int `json:"used"`
+	Total int `json:"total"`
+}
+
+// Sample is the licence usage at a point in time
+type Sample struct {
+	Time  time.Time      `json:"time"`
+	Usage map[Type]Usage `json:"usage"`
+}
+
+// NewSample converts licensing status into a sample taken at t
+func NewSample(l *status.Licensing, t time.Time) Sample {
+	return Sample{
+		Time: t,
+		Usage: map[Type]Usage{
+			Port:       {l.PortCount, l.PortTotal},
+			VMR:        {l.VMRCount, l.VMRTotal},
+			Audio:      {l.AudioCount, l.AudioTotal},
+			System:     {l.SystemCount, l.SystemTotal},
+			Teams:      {l.TeamsCount, l.TeamsTotal},
+			GHM:        {l.GHMCount, l.GHMTotal},
+			OTJ:        {l.OTJCount, l.OTJTotal},
+			Scheduling: {l.SchedulingCount, l.SchedulingTotal},
+			Telehealth: {l.TelehealthCount, l.TelehealthTotal},
+		},
+	}
+}
+
+// Sampler records licence usage samples
+type Sampler struct {
+	client    *infinity.Client
+	interval  time.Duration
+	retention time.Duration
+	now       func() time.Time
+
+	mu      sync.Mutex
+	samples []Sample
+}
+
+// SamplerOption configures a Sampler
+type SamplerOption func(*Sampler) error
+
+// WithInterval sets the time between samples taken by Run
+func WithInterval(interval time.Duration) SamplerOption {
+	return func(s *Sampler) error {
+		if interval <= 0 {
+			return fmt.Errorf("interval must be positive")
+		}
+		s.interval = interval
+		return nil
+	}
+}
+
+// WithRetention sets the age after which samples are discarded
+func WithRetention(retention time.Duration) SamplerOption {
+	return func(s *Sampler) error {
+		if retention <= 0 {
+			return fmt.Errorf("retention must be positive")
+		}
+		s.retention = retention
+		return nil
+	}
+}
+
+// NewSampler creates a Sampler that reads licensing status with client
+func NewSampler(client *infinity.Client, opts ...SamplerOption) (*Sampler, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client cannot be nil")
+	}
+	s := &Sampler{
+		client:    client,
+		interval:  DefaultInterval,
+		retention: DefaultRetention,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Sample reads licensing status and records it as a sample
+func (s *Sampler) Sample(ctx context.Context) (Sample, error) {
+	l, err := s.client.Status().GetLicensing(ctx)
+	if err != nil {
+		return Sample{}, fmt.Errorf("failed to get licensing status: %w", err)
+	}
+	sample := NewSample(l, s.now())
+	s.Add(sample)
+	return sample, nil
+}
+
+// Add records a sample taken elsewhere, such as one restored from storage
+func (s *Sampler) Add(sample Sample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, _ := slices.BinarySearchFunc(s.samples, sample.Time, func(a Sample, t time.Time) int { return a.Time.Compare(t) })
+	s.samples = slices.Insert(s.samples, i, sample)
+
+	cutoff := s.now().Add(-s.retention)
+	expired, _ := slices.BinarySearchFunc(s.samples, cutoff, func(a Sample, t time.Time) int { return a.Time.Compare(t) })
+	s.samples = slices.Delete(s.samples, 0, expired)
+}
+
+// Samples returns the recorded samples in time order
+func (s *Sampler) Samples() []Sample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.samples)
+}
+
+// Run takes a sample every interval until ctx is done. Failed samples are skipped; Run only returns
+// when ctx is done.
+func (s *Sampler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		_, _ = s.Sample(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Report analyzes the recorded samples together with the installed licences
+func (s *Sampler) Report(licences []config.Licence, opts *ReportOptions) *Report {
+	return Analyze(s.Samples(), licences, opts)
+}
+
+// Severity is the severity of an alert
+type Severity string
+
+// Alert severities
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Alert is a licensing condition that needs attention
+type Alert struct {
+	Severity Severity `json:"severity"`
+	// Type is the licence type the alert is about, if any
+	Type    Type   `json:"type,omitempty"`
+	Message string `json:"message"`
+}
+
+// TypeReport is the usage of a single licence type over the sampled period
+type TypeReport struct {
+	Type    Type `json:"type"`
+	Total   int  `json:"total"`
+	Current int  `json:"current"`
+	Peak    int  `json:"peak"`
+	// PeakTime is when Peak was first reached
+	PeakTime   time.Time `json:"peak_time"`
+	Mean       float64   `json:"mean"`
+	Percentile float64   `json:"percentile"`
+	// Utilisation values are fractions of Total, or 0 when no licences of the type are installed
+	Utilisation           float64 `json:"utilisation"`
+	PeakUtilisation       float64 `json:"peak_utilisation"`
+	PercentileUtilisation float64 `json:"percentile_utilisation"`
+	// Trend is the change in usage per day from a least-squares fit of the samples
+	Trend float64 `json:"trend"`
+	// Exhaustion is when usage is projected to reach Total at the current trend, if it is growing
+	Exhaustion *time.Time `json:"exhaustion,omitempty"`
+}
+
+// Expiry is an installed licence that expires soon or has expired
+type Expiry struct {
+	EntitlementID string    `json:"entitlement_id"`
+	FulfillmentID string    `json:"fulfillment_id"`
+	ProductID     string    `json:"product_id"`
+	LicenseType   string    `json:"license_type"`
+	Concurrent    int       `json:"concurrent"`
+	Expires       time.Time `json:"expires"`
+	DaysLeft      int       `json:"days_left"`
+	Expired       bool      `json:"expired"`
+}
+
+// Report is the analysis of licence usage and entitlements
+type Report struct {
+	Time time.Time `json:"time"`
+	// From and To are the times of the first and last samples
+	From    time.Time    `json:"from"`
+	To      time.Time    `json:"to"`
+	Samples int          `json:"samples"`
+	Types   []TypeReport `json:"types"`
+	// Expiring lists licences that expire within ReportOptions.ExpiryWarning, soonest first
+	Expiring []Expiry `json:"expiring,omitempty"`
+	Alerts   []Alert  `json:"alerts,omitempty"`
+}
+
+// Type returns the report for licence type t
+func (r *Report) Type(t Type) (*TypeReport, bool) {
+	for i := range r.Types {
+		if r.Types[i].Type == t {
+			return &r.Types[i], true
+		}
+	}
+	return nil, false
+}
+
+// ReportOptions sets the thresholds of a report. Zero fields take their defaults.
+type ReportOptions struct {
+	// Now is the time the report is made at, by default time.Now()
+	Now time.Time
+	// Percentile is the usage percentile reported, between 0 and 100, by default 95
+	Percentile float64
+	// WarnUtilisation is the peak utilisation that raises a warning, by default 0.8
+	WarnUtilisation float64
+	// ExhaustionHorizon is how far ahead projected exhaustion raises a warning, by default 30 days
+	ExhaustionHorizon time.Duration
+	// ExpiryWarning is how far ahead an expiring licence raises a warning, by default 30 days
+	ExpiryWarning time.Duration
+}
+
+func (o *ReportOptions) withDefaults() ReportOptions {
+	opts := ReportOptions{}
+	if o != nil {
+		opts = *o
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	if opts.Percentile == 0 {
+		opts.Percentile = 95
+	}
+	if opts.WarnUtilisation == 0 {
+		opts.WarnUtilisation = 0.8
+	}
+	if opts.ExhaustionHorizon == 0 {
+		opts.ExhaustionHorizon = 30 * 24 * time.Hour
+	}
+	if opts.ExpiryWarning == 0 {
+		opts.ExpiryWarning = 30 * 24 * time.Hour
+	}
+	return opts
+}
+
+// Analyze builds a report from samples in time order and the installed licences
+func Analyze(samples []Sample, licences []config.Licence, opts *ReportOptions) *Report {
+	o := opts.withDefaults()
+	report := &Report{Time: o.Now, Samples: len(samples)}
+	if len(samples) > 0 {
+		report.From, report.To = samples[0].Time, samples[len(samples)-1].Time
+		for _, t := range Types {
+			tr := analyzeType(t, samples, o.Percentile)
+			if tr.Total == 0 && tr.Peak == 0 {
+				// The type is neither licensed nor used
+				continue
+			}
+			report.Types = append(report.Types, tr)
+			report.Alerts = append(report.Alerts, usageAlerts(tr, o)...)
+		}
+	}
+
+	for _, l := range licences {
+		expires, ok := parseDate(l.ExpirationDate)
+		if !ok || expires.Sub(o.Now) > o.ExpiryWarning {
+			continue
+		}
+		e := Expiry{
+			EntitlementID: l.EntitlementID,
+			FulfillmentID: l.FulfillmentID,
+			ProductID:     l.ProductID,
+			LicenseType:   l.LicenseType,
+			Concurrent:    l.Concurrent,
+			Expires:       expires,
+			DaysLeft:      int(math.Ceil(expires.Sub(o.Now).Hours() / 24)),
+			Expired:       !expires.After(o.Now),
+		}
+		report.Expiring = append(report.Expiring, e)
+	}
+	slices.SortFunc(report.Expiring, func(a, b Expiry) int { return a.Expires.Compare(b.Expires) })
+	for _, e := range report.Expiring {
+		if e.Expired {
+			report.Alerts = append(report.Alerts, Alert{
+				Severity: SeverityCritical,
+				Message:  fmt.Sprintf("licence %s (%s) expired on %s", e.EntitlementID, e.ProductID, e.Expires.Format(time.DateOnly)),
+			})
+		} else {
+			report.Alerts = append(report.Alerts, Alert{
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("licence %s (%s) expires in %d days on %s", e.EntitlementID, e.ProductID, e.DaysLeft, e.Expires.Format(time.DateOnly)),
+			})
+		}
+	}
+	return report
+}
+
+func analyzeType(t Type, samples []Sample, percentile float64) TypeReport {
+	tr := TypeReport{Type: t}
+	used := make([]float64, len(samples))
+	var sum float64
+	for i, s := range samples {
+		u := s.Usage[t]
+		used[i] = float64(u.Used)
+		sum += used[i]
+		if u.Used > tr.Peak || i == 0 {
+			tr.Peak, tr.PeakTime = u.Used, s.Time
+		}
+	}
+	last := samples[len(samples)-1]
+	tr.Total, tr.Current = last.Usage[t].Total, last.Usage[t].Used
+	tr.Mean = sum / float64(len(samples))
+	tr.Percentile = nearestRank(slices.Sorted(slices.Values(used)), percentile)
+	if tr.Total > 0 {
+		total := float64(tr.Total)
+		tr.Utilisation = float64(tr.Current) / total
+		tr.PeakUtilisation = float64(tr.Peak) / total
+		tr.PercentileUtilisation = tr.Percentile / total
+	}
+
+	slope, intercept, ok := fit(samples, used)
+	if !ok {
+		return tr
+	}
+	tr.Trend = slope * 24 * float64(time.Hour/time.Second)
+	if slope > 0 && tr.Total > 0 {
+		// Solve intercept + slope*x = total, with x in seconds since the first sample
+		x := (float64(tr.Total) - intercept) / slope
+		offset := x * float64(time.Second)
+		if math.IsNaN(offset) || offset >= math.MaxInt64 {
+			return tr // too flat to run out within the range of a time.Duration
+		}
+		exhaustion := last.Time
+		if offset > float64(last.Time.Sub(samples[0].Time)) {
+			exhaustion = samples[0].Time.Add(time.Duration(offset))
+		}
+		tr.Exhaustion = &exhaustion
+	}
+	return tr
+}
+
+func usageAlerts(tr TypeReport, o ReportOptions) []Alert {
+	var alerts []Alert
+	switch {
+	case tr.Total == 0:
+		alerts = append(alerts, Alert{SeverityCritical, tr.Type, fmt.Sprintf("%s licences are in use but none are installed", tr.Type)})
+	case tr.Peak >= tr.Total:
+		alerts = append(alerts, Alert{SeverityCritical, tr.Type, fmt.Sprintf("%s licences were exhausted at %s (%d of %d)", tr.Type, tr.PeakTime.Format(time.RFC3339), tr.Peak, tr.Total)})
+	case tr.PeakUtilisation >= o.WarnUtilisation:
+		alerts = append(alerts, Alert{SeverityWarning, tr.Type, fmt.Sprintf("%s licence usage peaked at %.0f%% (%d of %d)", tr.Type, tr.PeakUtilisation*100, tr.Peak, tr.Total)})
+	}
+	if tr.Exhaustion != nil && tr.Peak < tr.Total && tr.Exhaustion.Sub(o.Now) <= o.ExhaustionHorizon {
+		alerts = append(alerts, Alert{SeverityWarning, tr.Type, fmt.Sprintf("%s licences are projected to run out on %s", tr.Type, tr.Exhaustion.Format(time.DateOnly))})
+	}
+	return alerts
+}
+
+// nearestRank returns the p-th percentile of sorted values by the nearest-rank method
+func nearestRank(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
+
+// fit returns the least-squares line through the samples' usage, with time in seconds since the
+// first sample, or false if the samples do not span any time
+func fit(samples []Sample, used []float64) (slope, intercept float64, ok bool) {
+	n := float64(len(samples))
+	var sx, sy, sxx, sxy float64
+	for i, s := range samples {
+		x := s.Time.Sub(samples[0].Time).Seconds()
+		sx += x
+		sy += used[i]
+		sxx += x * x
+		sxy += x * used[i]
+	}
+	d := n*sxx - sx*sx
+	if n < 2 || d == 0 {
+		return 0, 0, false
+	}
+	slope = (n*sxy - sx*sy) / d
+	return slope, (sy - slope*sx) / n, true
+}
+
+// dateLayouts are the formats licence dates are reported in
+var dateLayouts = []string{"2-Jan-2006", "2006-01-02", time.RFC3339, "2006-01-02T15:04:05"}
+
+// parseDate parses a licence date, returning false for permanent licences and unparseable dates
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, "permanent") {
+		return time.Time{}, false
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,143 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package licensing
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	infinity "github.com/pexip/go-infinity-sdk/v41"
+	"github.com/pexip/go-infinity-sdk/v41/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// dailySamples returns one sample per day with the given port usage out of 100
+func dailySamples(used ...int) []Sample {
+	samples := make([]Sample, len(used))
+	for i, u := range used {
+		samples[i] = Sample{
+			Time:  start.Add(time.Duration(i) * 24 * time.Hour),
+			Usage: map[Type]Usage{Port: {Used: u, Total: 100}, VMR: {Used: 5, Total: 50}},
+		}
+	}
+	return samples
+}
+
+func TestAnalyze_Usage(t *testing.T) {
+	samples := dailySamples(10, 20, 30, 40, 50)
+	report := Analyze(samples, nil, &ReportOptions{Now: samples[4].Time, Percentile: 50})
+
+	assert.Equal(t, 5, report.Samples)
+	assert.Equal(t, start, report.From)
+	require.Len(t, report.Types, 2, "types that are neither installed nor used are left out")
+
+	port, ok := report.Type(Port)
+	require.True(t, ok)
+	assert.Equal(t, 100, port.Total)
+	assert.Equal(t, 50, port.Current)
+	assert.Equal(t, 50, port.Peak)
+	assert.Equal(t, samples[4].Time, port.PeakTime)
+	assert.Equal(t, 30.0, port.Mean)
+	assert.Equal(t, 30.0, port.Percentile)
+	assert.Equal(t, 0.5, port.PeakUtilisation)
+	assert.InDelta(t, 10.0, port.Trend, 1e-9)
+	require.NotNil(t, port.Exhaustion)
+	assert.Equal(t, start.Add(9*24*time.Hour), *port.Exhaustion)
+
+	vmr, _ := report.Type(VMR)
+	assert.Zero(t, vmr.Trend)
+	assert.Nil(t, vmr.Exhaustion)
+
+	require.Len(t, report.Alerts, 1)
+	assert.Equal(t, Alert{SeverityWarning, Port, "port licences are projected to run out on 2025-06-10"}, report.Alerts[0])
+}
+
+func TestAnalyze_UsageAlerts(t *testing.T) {
+	report := Analyze(dailySamples(85, 80, 80), nil, &ReportOptions{Now: start})
+	port, _ := report.Type(Port)
+	assert.Nil(t, port.Exhaustion, "usage is not growing")
+	assert.Equal(t, []Alert{{SeverityWarning, Port, "port licence usage peaked at 85% (85 of 100)"}}, report.Alerts)
+
+	report = Analyze(dailySamples(90, 100, 60), nil, &ReportOptions{Now: start})
+	assert.Equal(t, []Alert{{SeverityCritical, Port, "port licences were exhausted at 2025-06-02T00:00:00Z (100 of 100)"}}, report.Alerts)
+}
+
+func TestAnalyze_FlatTrend(t *testing.T) {
+	samples := make([]Sample, 30*24)
+	for i := range samples {
+		samples[i] = Sample{
+			Time:  start.Add(time.Duration(i) * time.Hour),
+			Usage: map[Type]Usage{Port: {Used: 10, Total: 1000}},
+		}
+	}
+	samples[len(samples)-1].Usage[Port] = Usage{Used: 11, Total: 1000}
+
+	report := Analyze(samples, nil, &ReportOptions{Now: samples[len(samples)-1].Time})
+
+	port, _ := report.Type(Port)
+	assert.Positive(t, port.Trend)
+	assert.Nil(t, port.Exhaustion, "exhaustion is beyond the range of a time.Duration")
+	assert.Empty(t, report.Alerts)
+}
+
+func TestAnalyze_Expiring(t *testing.T) {
+	licences := []config.Licence{
+		{EntitlementID: "E1", ProductID: "P1", ExpirationDate: "15-jun-2025"},
+		{EntitlementID: "E2", ProductID: "P2", ExpirationDate: "2025-05-30"},
+		{EntitlementID: "E3", ProductID: "P3", ExpirationDate: "permanent"},
+		{EntitlementID: "E4", ProductID: "P4", ExpirationDate: "1-dec-2025"},
+	}
+	report := Analyze(nil, licences, &ReportOptions{Now: start})
+
+	require.Len(t, report.Expiring, 2)
+	assert.Equal(t, "E2", report.Expiring[0].EntitlementID)
+	assert.True(t, report.Expiring[0].Expired)
+	assert.Equal(t, "E1", report.Expiring[1].EntitlementID)
+	assert.Equal(t, 14, report.Expiring[1].DaysLeft)
+	assert.Equal(t, []Alert{
+		{Severity: SeverityCritical, Message: "licence E2 (P2) expired on 2025-05-30"},
+		{Severity: SeverityWarning, Message: "licence E1 (P1) expires in 14 days on 2025-06-15"},
+	}, report.Alerts)
+}
+
+func TestSampler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/admin/status/v1/licensing/", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"objects": []map[string]interface{}{{"port_count": 12, "port_total": 100, "teams_count": 1, "teams_total": 5}},
+		})
+	}))
+	defer server.Close()
+
+	client, err := infinity.New(infinity.WithBaseURL(server.URL))
+	require.NoError(t, err)
+	sampler, err := NewSampler(client, WithRetention(time.Hour))
+	require.NoError(t, err)
+	now := start
+	sampler.now = func() time.Time { return now }
+
+	sample, err := sampler.Sample(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Used: 12, Total: 100}, sample.Usage[Port])
+	assert.Equal(t, Usage{Used: 1, Total: 5}, sample.Usage[Teams])
+
+	sampler.Add(Sample{Time: start.Add(-time.Minute)})
+	assert.Len(t, sampler.Samples(), 2)
+	assert.Equal(t, start.Add(-time.Minute), sampler.Samples()[0].Time, "samples are kept in time order")
+
+	now = start.Add(2 * time.Hour)
+	_, err = sampler.Sample(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, sampler.Samples(), 1, "samples older than the retention are discarded")
+}
@@ -0,0 +1,1089 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package localpolicy
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// undefined is the value of a variable or attribute that does not exist. Like Jinja's default
+// Undefined it prints as an empty string and is falsy, but cannot be used any further.
+type undefined struct {
+	name string
+}
+
+// function is a callable value: a global such as pex_now or a method such as str.lower
+type function func(args []interface{}, kwargs map[string]interface{}) (interface{}, error)
+
+// state is the evaluation state of a single render
+type state struct {
+	scopes []map[string]interface{}
+	env    *environment
+	out    strings.Builder
+}
+
+func (s *state) lookup(name string) interface{} {
+	for i := len(s.scopes) - 1; i >= 0; i-- {
+		if v, ok := s.scopes[i][name]; ok {
+			return v
+		}
+	}
+	if f, ok := s.env.globals[name]; ok {
+		return f
+	}
+	return undefined{name: name}
+}
+
+func (s *state) push(scope map[string]interface{}) {
+	s.scopes = append(s.scopes, scope)
+}
+
+func (s *state) pop() {
+	s.scopes = s.scopes[:len(s.scopes)-1]
+}
+
+func (s *state) execute(body []node) error {
+	for _, n := range body {
+		if err := s.executeNode(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *state) executeNode(n node) error {
+	switch n := n.(type) {
+	case *textNode:
+		s.out.WriteString(n.text)
+	case *printNode:
+		v, err := s.eval(n.expr)
+		if err != nil {
+			return err
+		}
+		s.out.WriteString(toString(v))
+	case *ifNode:
+		for _, b := range n.branches {
+			cond, err := s.eval(b.cond)
+			if err != nil {
+				return err
+			}
+			if truthy(cond) {
+				return s.execute(b.body)
+			}
+		}
+		return s.execute(n.els)
+	case *forNode:
+		return s.executeFor(n)
+	case *setNode:
+		v, err := s.eval(n.value)
+		if err != nil {
+			return err
+		}
+		return s.assign(s.scopes[len(s.scopes)-1], n.names, v, n.line)
+	}
+	return nil
+}
+
+func (s *state) executeFor(n *forNode) error {
+	iterable, err := s.eval(n.iter)
+	if err != nil {
+		return err
+	}
+	items, err := iterate(iterable, n.line)
+	if err != nil {
+		return err
+	}
+
+	// Loop variables are local to the loop, and the condition filters items before loop.index is assigned
+	scope := make(map[string]interface{})
+	s.push(scope)
+	defer s.pop()
+	if n.cond != nil {
+		var kept []interface{}
+		for _, item := range items {
+			if err := s.assign(scope, n.vars, item, n.line); err != nil {
+				return err
+			}
+			cond, err := s.eval(n.cond)
+			if err != nil {
+				return err
+			}
+			if truthy(cond) {
+				kept = append(kept, item)
+			}
+		}
+		items = kept
+	}
+	if len(items) == 0 {
+		return s.execute(n.els)
+	}
+
+	for i, item := range items {
+		if err := s.assign(scope, n.vars, item, n.line); err != nil {
+			return err
+		}
+		scope["loop"] = map[string]interface{}{
+			"index":     i + 1,
+			"index0":    i,
+			"revindex":  len(items) - i,
+			"revindex0": len(items) - i - 1,
+			"first":     i == 0,
+			"last":      i == len(items)-1,
+			"length":    len(items),
+		}
+		if err := s.execute(n.body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assign binds value to names, unpacking it when there is more than one name
+func (s *state) assign(scope map[string]interface{}, names []string, value interface{}, line int) error {
+	if len(names) == 1 {
+		scope[names[0]] = value
+		return nil
+	}
+	items, err := iterate(value, line)
+	if err != nil {
+		return err
+	}
+	if len(items) != len(names) {
+		return errorf(line, "cannot unpack %d values into %d variables", len(items), len(names))
+	}
+	for i, name := range names {
+		scope[name] = items[i]
+	}
+	return nil
+}
+
+func (s *state) eval(e expr) (interface{}, error) {
+	switch e := e.(type) {
+	case *literal:
+		return e.value, nil
+	case *name:
+		return s.lookup(e.name), nil
+	case *listExpr:
+		list := make([]interface{}, 0, len(e.items))
+		for _, item := range e.items {
+			v, err := s.eval(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case *dictExpr:
+		dict := make(map[string]interface{}, len(e.keys))
+		for i := range e.keys {
+			k, err := s.eval(e.keys[i])
+			if err != nil {
+				return nil, err
+			}
+			v, err := s.eval(e.values[i])
+			if err != nil {
+				return nil, err
+			}
+			dict[toString(k)] = v
+		}
+		return dict, nil
+	case *attrExpr:
+		obj, err := s.eval(e.obj)
+		if err != nil {
+			return nil, err
+		}
+		return getAttr(obj, e.attr, e.line)
+	case *indexExpr:
+		obj, err := s.eval(e.obj)
+		if err != nil {
+			return nil, err
+		}
+		index, err := s.eval(e.index)
+		if err != nil {
+			return nil, err
+		}
+		return getItem(obj, index, e.line)
+	case *sliceExpr:
+		return s.evalSlice(e)
+	case *callExpr:
+		fn, err := s.eval(e.fn)
+		if err != nil {
+			return nil, err
+		}
+		f, ok := fn.(function)
+		if !ok {
+			return nil, errorf(e.line, "%s is not callable", typeName(fn))
+		}
+		args, kwargs, err := s.evalArgs(e.args, e.kwargs)
+		if err != nil {
+			return nil, err
+		}
+		v, err := f(args, kwargs)
+		if err != nil {
+			return nil, lineError(e.line, err)
+		}
+		return v, nil
+	case *filterExpr:
+		f, ok := s.env.filters[e.name]
+		if !ok {
+			return nil, errorf(e.line, "no filter named %q", e.name)
+		}
+		v, err := s.eval(e.value)
+		if err != nil {
+			return nil, err
+		}
+		args, kwargs, err := s.evalArgs(e.args, e.kwargs)
+		if err != nil {
+			return nil, err
+		}
+		v, err = f(append([]interface{}{v}, args...), kwargs)
+		if err != nil {
+			return nil, lineError(e.line, fmt.Errorf("%s: %w", e.name, err))
+		}
+		return v, nil
+	case *testExpr:
+		t, ok := tests[e.name]
+		if !ok {
+			return nil, errorf(e.line, "no test named %q", e.name)
+		}
+		v, err := s.eval(e.value)
+		if err != nil {
+			return nil, err
+		}
+		args, _, err := s.evalArgs(e.args, nil)
+		if err != nil {
+			return nil, err
+		}
+		ok, err = t(v, args)
+		if err != nil {
+			return nil, lineError(e.line, err)
+		}
+		return ok != e.negate, nil
+	case *unaryExpr:
+		v, err := s.eval(e.operand)
+		if err != nil {
+			return nil, err
+		}
+		switch e.op {
+		case "not":
+			return !truthy(v), nil
+		case "-":
+			switch n := v.(type) {
+			case int:
+				if n == math.MinInt {
+					return nil, errorf(e.line, "integer overflow in unary -")
+				}
+				return -n, nil
+			case float64:
+				return -n, nil
+			}
+		case "+":
+			switch v.(type) {
+			case int, float64:
+				return v, nil
+			}
+		}
+		return nil, errorf(e.line, "bad operand type for unary %s: %s", e.op, typeName(v))
+	case *binaryExpr:
+		return s.evalBinary(e)
+	case *condExpr:
+		cond, err := s.eval(e.cond)
+		if err != nil {
+			return nil, err
+		}
+		if truthy(cond) {
+			return s.eval(e.then)
+		}
+		return s.eval(e.els)
+	}
+	return nil, fmt.Errorf("unknown expression %T", e)
+}
+
+func (s *state) evalArgs(exprs []expr, kwexprs map[string]expr) ([]interface{}, map[string]interface{}, error) {
+	args := make([]interface{}, 0, len(exprs))
+	for _, a := range exprs {
+		v, err := s.eval(a)
+		if err != nil {
+			return nil, nil, err
+		}
+		args = append(args, v)
+	}
+	var kwargs map[string]interface{}
+	for k, a := range kwexprs {
+		v, err := s.eval(a)
+		if err != nil {
+			return nil, nil, err
+		}
+		if kwargs == nil {
+			kwargs = make(map[string]interface{})
+		}
+		kwargs[k] = v
+	}
+	return args, kwargs, nil
+}
+
+func (s *state) evalSlice(e *sliceExpr) (interface{}, error) {
+	obj, err := s.eval(e.obj)
+	if err != nil {
+		return nil, err
+	}
+	var bounds [3]*int
+	for i, part := range []expr{e.start, e.stop, e.step} {
+		if part == nil {
+			continue
+		}
+		v, err := s.eval(part)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			continue
+		}
+		n, ok := v.(int)
+		if !ok {
+			return nil, errorf(e.line, "slice indices must be integers")
+		}
+		bounds[i] = &n
+	}
+	step := 1
+	if bounds[2] != nil {
+		if step = *bounds[2]; step == 0 {
+			return nil, errorf(e.line, "slice step cannot be zero")
+		}
+	}
+
+	var length int
+	switch v := obj.(type) {
+	case string:
+		length = len([]rune(v))
+	case []interface{}:
+		length = len(v)
+	default:
+		return nil, errorf(e.line, "%s is not sliceable", typeName(obj))
+	}
+	indices := sliceIndices(length, bounds[0], bounds[1], step)
+
+	switch v := obj.(type) {
+	case string:
+		runes := []rune(v)
+		out := make([]rune, 0, len(indices))
+		for _, i := range indices {
+			out = append(out, runes[i])
+		}
+		return string(out), nil
+	default:
+		list := v.([]interface{})
+		out := make([]interface{}, 0, len(indices))
+		for _, i := range indices {
+			out = append(out, list[i])
+		}
+		return out, nil
+	}
+}
+
+// sliceIndices returns the indices selected by a Python slice over a sequence of the given length
+func sliceIndices(length int, start, stop *int, step int) []int {
+	clamp := func(p *int, def, lo, hi int) int {
+		if p == nil {
+			return def
+		}
+		i := *p
+		if i < 0 {
+			i += length
+		}
+		return min(max(i, lo), hi)
+	}
+	var indices []int
+	if step > 0 {
+		for i := clamp(start, 0, 0, length); i < clamp(stop, length, 0, length); i += step {
+			indices = append(indices, i)
+		}
+	} else {
+		for i := clamp(start, length-1, -1, length-1); i > clamp(stop, -1, -1, length-1); i += step {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+func (s *state) evalBinary(e *binaryExpr) (interface{}, error) {
+	left, err := s.eval(e.left)
+	if err != nil {
+		return nil, err
+	}
+	// and/or short-circuit and return an operand, as in Python
+	switch e.op {
+	case "and":
+		if !truthy(left) {
+			return left, nil
+		}
+		return s.eval(e.right)
+	case "or":
+		if truthy(left) {
+			return left, nil
+		}
+		return s.eval(e.right)
+	}
+	right, err := s.eval(e.right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "~":
+		return toString(left) + toString(right), nil
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "in", "not in":
+		ok, err := contains(right, left)
+		if err != nil {
+			return nil, lineError(e.line, err)
+		}
+		return ok == (e.op == "in"), nil
+	case "<", ">", "<=", ">=":
+		c, err := compare(left, right)
+		if err != nil {
+			return nil, lineError(e.line, err)
+		}
+		switch e.op {
+		case "<":
+			return c < 0, nil
+		case ">":
+			return c > 0, nil
+		case "<=":
+			return c <= 0, nil
+		default:
+			return c >= 0, nil
+		}
+	}
+	v, err := arithmetic(e.op, left, right)
+	if err != nil {
+		return nil, lineError(e.line, err)
+	}
+	return v, nil
+}
+
+func arithmetic(op string, left, right interface{}) (interface{}, error) {
+	if op == "+" {
+		switch l := left.(type) {
+		case string:
+			if r, ok := right.(string); ok {
+				return l + r, nil
+			}
+		case []interface{}:
+			if r, ok := right.([]interface{}); ok {
+				return append(slices.Clone(l), r...), nil
+			}
+		}
+	}
+	if op == "*" {
+		if l, ok := left.(string); ok {
+			if r, ok := right.(int); ok {
+				return strings.Repeat(l, max(r, 0)), nil
+			}
+		}
+	}
+
+	li, lInt := toNumber(left)
+	ri, rInt := toNumber(right)
+	if li == nil || ri == nil {
+		return nil, fmt.Errorf("unsupported operand types for %s: %s and %s", op, typeName(left), typeName(right))
+	}
+	if lInt && rInt {
+		l, r := intValue(left), intValue(right)
+		switch op {
+		case "+":
+			if r > 0 && l > math.MaxInt-r || r < 0 && l < math.MinInt-r {
+				return nil, fmt.Errorf("integer overflow in %s", op)
+			}
+			return l + r, nil
+		case "-":
+			if r < 0 && l > math.MaxInt+r || r > 0 && l < math.MinInt+r {
+				return nil, fmt.Errorf("integer overflow in %s", op)
+			}
+			return l - r, nil
+		case "*":
+			p, ok := mulInt(l, r)
+			if !ok {
+				return nil, fmt.Errorf("integer overflow in %s", op)
+			}
+			return p, nil
+		case "//", "%":
+			if r == 0 {
+				return nil, fmt.Errorf("integer division or modulo by zero")
+			}
+			if l == math.MinInt && r == -1 {
+				if op == "%" {
+					return 0, nil
+				}
+				return nil, fmt.Errorf("integer overflow in %s", op)
+			}
+			q, m := l/r, l%r
+			// Python rounds towards negative infinity
+			if m != 0 && (m < 0) != (r < 0) {
+				q, m = q-1, m+r
+			}
+			if op == "//" {
+				return q, nil
+			}
+			return m, nil
+		case "**":
+			if r >= 0 {
+				p, ok := powInt(l, r)
+				if !ok {
+					return nil, fmt.Errorf("integer overflow in %s", op)
+				}
+				return p, nil
+			}
+		}
+	}
+
+	l, r := *li, *ri
+	switch op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return l / r, nil
+	case "//":
+		if r == 0 {
+			return nil, fmt.Errorf("float floor division by zero")
+		}
+		return math.Floor(l / r), nil
+	case "%":
+		if r == 0 {
+			return nil, fmt.Errorf("float modulo by zero")
+		}
+		return l - math.Floor(l/r)*r, nil
+	case "**":
+		return math.Pow(l, r), nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", op)
+}
+
+// intValue returns an integral operand, an int or a bool, as an int
+func intValue(v interface{}) int {
+	if b, ok := v.(bool); ok {
+		return boolInt(b)
+	}
+	return v.(int)
+}
+
+// mulInt returns l*r and whether it fits in an int
+func mulInt(l, r int) (int, bool) {
+	if l == 0 || r == 0 {
+		return 0, true
+	}
+	p := l * r
+	if p/r != l || (l == -1 && r == math.MinInt) || (r == -1 && l == math.MinInt) {
+		return 0, false
+	}
+	return p, true
+}
+
+// powInt returns base**exp for a non-negative exp by repeated squaring, and whether it fits in an int
+func powInt(base, exp int) (int, bool) {
+	result := 1
+	for {
+		if exp&1 == 1 {
+			var ok bool
+			if result, ok = mulInt(result, base); !ok {
+				return 0, false
+			}
+		}
+		exp >>= 1
+		if exp == 0 {
+			return result, true
+		}
+		var ok bool
+		if base, ok = mulInt(base, base); !ok {
+			return 0, false
+		}
+	}
+}
+
+// toNumber returns v as a float and whether it is integral, or nil if v is not a number
+func toNumber(v interface{}) (*float64, bool) {
+	var f float64
+	switch n := v.(type) {
+	case int:
+		f = float64(n)
+		return &f, true
+	case bool:
+		f = float64(boolInt(n))
+		return &f, true
+	case float64:
+		return &n, false
+	}
+	return nil, false
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func equal(a, b interface{}) bool {
+	if af, _ := toNumber(a); af != nil {
+		if bf, _ := toNumber(b); bf != nil {
+			return *af == *bf
+		}
+		return false
+	}
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	case undefined:
+		_, ok := b.(undefined)
+		return ok
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		return ok && slices.EqualFunc(av, bv, equal)
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		return ok && maps.EqualFunc(av, bv, equal)
+	}
+	return false
+}
+
+func compare(a, b interface{}) (int, error) {
+	if af, _ := toNumber(a); af != nil {
+		if bf, _ := toNumber(b); bf != nil {
+			switch {
+			case *af < *bf:
+				return -1, nil
+			case *af > *bf:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	if as, ok := a.(string); ok {
+		if bs, ok := b.(string); ok {
+			return strings.Compare(as, bs), nil
+		}
+	}
+	if al, ok := a.([]interface{}); ok {
+		if bl, ok := b.([]interface{}); ok {
+			for i := 0; i < len(al) && i < len(bl); i++ {
+				if c, err := compare(al[i], bl[i]); err != nil || c != 0 {
+					return c, err
+				}
+			}
+			return len(al) - len(bl), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %s and %s", typeName(a), typeName(b))
+}
+
+func contains(container, item interface{}) (bool, error) {
+	switch c := container.(type) {
+	case string:
+		s, ok := item.(string)
+		if !ok {
+			return false, fmt.Errorf("'in <string>' requires string as left operand, not %s", typeName(item))
+		}
+		return strings.Contains(c, s), nil
+	case []interface{}:
+		return slices.ContainsFunc(c, func(v interface{}) bool { return equal(v, item) }), nil
+	case map[string]interface{}:
+		s, ok := item.(string)
+		if !ok {
+			return false, nil
+		}
+		_, found := c[s]
+		return found, nil
+	}
+	return false, fmt.Errorf("argument of type %s is not iterable", typeName(container))
+}
+
+// iterate returns the items of a list, the characters of a string or the sorted keys of a dict
+func iterate(v interface{}, line int) ([]interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		return v, nil
+	case map[string]interface{}:
+		keys := make([]interface{}, 0, len(v))
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			keys = append(keys, k)
+		}
+		return keys, nil
+	case string:
+		items := make([]interface{}, 0, len(v))
+		for _, r := range v {
+			items = append(items, string(r))
+		}
+		return items, nil
+	case undefined:
+		return nil, nil
+	}
+	return nil, errorf(line, "%s is not iterable", typeName(v))
+}
+
+// getAttr looks up an attribute: a dict key, a method, or an item of a list by index
+func getAttr(obj interface{}, attr string, line int) (interface{}, error) {
+	switch o := obj.(type) {
+	case undefined:
+		return nil, errorf(line, "%q is undefined", o.name)
+	case map[string]interface{}:
+		if v, ok := o[attr]; ok {
+			return v, nil
+		}
+	case []interface{}:
+		if i, err := strconv.Atoi(attr); err == nil {
+			return getItem(obj, i, line)
+		}
+	}
+	if m := method(obj, attr); m != nil {
+		return m, nil
+	}
+	return undefined{name: attr}, nil
+}
+
+func getItem(obj, index interface{}, line int) (interface{}, error) {
+	switch o := obj.(type) {
+	case undefined:
+		return nil, errorf(line, "%q is undefined", o.name)
+	case map[string]interface{}:
+		if k, ok := index.(string); ok {
+			if v, ok := o[k]; ok {
+				return v, nil
+			}
+			return undefined{name: k}, nil
+		}
+	case []interface{}:
+		if i, ok := index.(int); ok {
+			if i < 0 {
+				i += len(o)
+			}
+			if i < 0 || i >= len(o) {
+				return undefined{name: strconv.Itoa(i)}, nil
+			}
+			return o[i], nil
+		}
+	case string:
+		if i, ok := index.(int); ok {
+			runes := []rune(o)
+			if i < 0 {
+				i += len(runes)
+			}
+			if i < 0 || i >= len(runes) {
+				return undefined{name: strconv.Itoa(i)}, nil
+			}
+			return string(runes[i]), nil
+		}
+	}
+	if k, ok := index.(string); ok {
+		return getAttr(obj, k, line)
+	}
+	return nil, errorf(line, "%s cannot be indexed by %s", typeName(obj), typeName(index))
+}
+
+// method returns the bound method name of obj, or nil if there is no such method
+func method(obj interface{}, name string) function {
+	switch o := obj.(type) {
+	case string:
+		return stringMethod(o, name)
+	case map[string]interface{}:
+		switch name {
+		case "get":
+			return func(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+				if len(args) == 0 {
+					return nil, fmt.Errorf("get expected at least 1 argument")
+				}
+				if v, ok := o[toString(args[0])]; ok {
+					return v, nil
+				}
+				if len(args) > 1 {
+					return args[1], nil
+				}
+				return nil, nil
+			}
+		case "keys", "values", "items":
+			return func([]interface{}, map[string]interface{}) (interface{}, error) {
+				out := make([]interface{}, 0, len(o))
+				for _, k := range slices.Sorted(maps.Keys(o)) {
+					switch name {
+					case "keys":
+						out = append(out, k)
+					case "values":
+						out = append(out, o[k])
+					default:
+						out = append(out, []interface{}{k, o[k]})
+					}
+				}
+				return out, nil
+			}
+		}
+	}
+	return nil
+}
+
+func stringMethod(s, name string) function {
+	stringArg := func(args []interface{}, i int, def string) string {
+		if i < len(args) && args[i] != nil {
+			return toString(args[i])
+		}
+		return def
+	}
+	simple := map[string]func(string) string{
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"title":      title,
+		"capitalize": capitalize,
+	}
+	if f, ok := simple[name]; ok {
+		return func([]interface{}, map[string]interface{}) (interface{}, error) { return f(s), nil }
+	}
+	switch name {
+	case "strip", "lstrip", "rstrip":
+		return func(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+			cutset := stringArg(args, 0, " \t\n\r\v\f")
+			switch name {
+			case "lstrip":
+				return strings.TrimLeft(s, cutset), nil
+			case "rstrip":
+				return strings.TrimRight(s, cutset), nil
+			}
+			return strings.Trim(s, cutset), nil
+		}
+	case "startswith", "endswith":
+		return func(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+			affixes := args
+			if len(args) == 1 {
+				if list, ok := args[0].([]interface{}); ok {
+					affixes = list
+				}
+			}
+			for _, a := range affixes {
+				if name == "startswith" && strings.HasPrefix(s, toString(a)) ||
+					name == "endswith" && strings.HasSuffix(s, toString(a)) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+	case "split":
+		return func(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+			var parts []string
+			if sep := stringArg(args, 0, ""); sep == "" {
+				parts = strings.Fields(s)
+			} else if len(args) > 1 {
+				n, _ := args[1].(int)
+				parts = strings.SplitN(s, sep, n+1)
+			} else {
+				parts = strings.Split(s, sep)
+			}
+			out := make([]interface{}, len(parts))
+			for i, p := range parts {
+				out[i] = p
+			}
+			return out, nil
+		}
+	case "replace":
+		return func(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+			if len(args) < 2 {
+				return nil, fmt.Errorf("replace expected 2 arguments")
+			}
+			return strings.ReplaceAll(s, toString(args[0]), toString(args[1])), nil
+		}
+	case "format":
+		return func(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+			return formatString(s, args)
+		}
+	}
+	return nil
+}
+
+// formatString implements the positional subset of str.format: {} and {0}
+func formatString(format string, args []interface{}) (string, error) {
+	var b strings.Builder
+	next := 0
+	for {
+		open := strings.IndexByte(format, '{')
+		if open < 0 {
+			b.WriteString(format)
+			return b.String(), nil
+		}
+		if strings.HasPrefix(format[open:], "{{") {
+			b.WriteString(format[:open+1])
+			format = format[open+2:]
+			continue
+		}
+		end := strings.IndexByte(format[open:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("single '{' encountered in format string")
+		}
+		b.WriteString(format[:open])
+		field := format[open+1 : open+end]
+		i := next
+		if field != "" {
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				return "", fmt.Errorf("unsupported format field %q", field)
+			}
+			i = n
+		}
+		if i >= len(args) {
+			return "", fmt.Errorf("format index %d out of range", i)
+		}
+		b.WriteString(toString(args[i]))
+		next = i + 1
+		format = format[open+end+1:]
+	}
+}
+
+func title(s string) string {
+	runes := []rune(strings.ToLower(s))
+	start := true
+	for i, r := range runes {
+		isLetter := strings.ContainsRune("abcdefghijklmnopqrstuvwxyz", r) || r > 127
+		if start && isLetter {
+			runes[i] = []rune(strings.ToUpper(string(r)))[0]
+		}
+		start = !isLetter && !(r >= '0' && r <= '9')
+	}
+	return string(runes)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(strings.ToLower(s))
+	return strings.ToUpper(string(runes[0])) + string(runes[1:])
+}
+
+// truthy reports whether v is true in a boolean context, following Python
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case nil, undefined:
+		return false
+	case bool:
+		return v
+	case int:
+		return v != 0
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+// toString formats v as Jinja prints it
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case undefined:
+		return ""
+	}
+	return repr(v)
+}
+
+// repr formats v as Python's repr, which is how values nested in lists and dicts are printed
+func repr(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		switch {
+		case math.IsInf(v, 1):
+			return "inf"
+		case math.IsInf(v, -1):
+			return "-inf"
+		case math.IsNaN(v):
+			return "nan"
+		case v == math.Trunc(v) && math.Abs(v) < 1e16:
+			return strconv.FormatFloat(v, 'f', 1, 64)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`).Replace(v) + "'"
+	case undefined:
+		return ""
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = repr(item)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case map[string]interface{}:
+		parts := make([]string, 0, len(v))
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			parts = append(parts, repr(k)+": "+repr(v[k]))
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	case function:
+		return "<function>"
+	}
+	return fmt.Sprint(v)
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "NoneType"
+	case bool:
+		return "bool"
+	case int:
+		return "int"
+	case float64:
+		return "float"
+	case string:
+		return "str"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "dict"
+	case undefined:
+		return "Undefined"
+	case function:
+		return "function"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// lineError attaches a line number to err unless it already has one
+func lineError(line int, err error) error {
+	var e *Error
+	if errors.As(err, &e) && e.Line != 0 {
+		return err
+	}
+	return &Error{Line: line, Msg: err.Error()}
+}
@@ -0,0 +1,736 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package localpolicy
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"maps"
+	"math"
+	"math/big"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// environment holds the filters and globals available to a render
+type environment struct {
+	filters map[string]function
+	globals map[string]interface{}
+	now     func() time.Time
+	rand    io.Reader
+	logs    []string
+}
+
+func newEnvironment(opts *options) *environment {
+	env := &environment{now: opts.now, rand: opts.rand}
+	env.filters = map[string]function{
+		"abs":        filterAbs,
+		"capitalize": stringFilter(capitalize),
+		"count":      filterLength,
+		"d":          filterDefault,
+		"default":    filterDefault,
+		"e":          stringFilter(html.EscapeString),
+		"escape":     stringFilter(html.EscapeString),
+		"first":      filterFirst,
+		"float":      filterFloat,
+		"int":        filterInt,
+		"join":       filterJoin,
+		"last":       filterLast,
+		"length":     filterLength,
+		"list":       filterList,
+		"lower":      stringFilter(strings.ToLower),
+		"max":        minMax(1),
+		"min":        minMax(-1),
+		"replace":    filterReplace,
+		"reverse":    filterReverse,
+		"round":      filterRound,
+		"safe":       filterIdentity,
+		"sort":       filterSort,
+		"string":     stringFilter(func(s string) string { return s }),
+		"sum":        filterSum,
+		"title":      stringFilter(title),
+		"tojson":     filterToJSON,
+		"trim":       stringFilter(strings.TrimSpace),
+		"unique":     filterUnique,
+		"upper":      stringFilter(strings.ToUpper),
+
+		"pex_base64":             stringFilter(func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }),
+		"pex_clean_phone_number": stringFilter(cleanPhoneNumber),
+		"pex_find_first_match":   filterFindFirstMatch,
+		"pex_hash":               stringFilter(func(s string) string { h := sha256.Sum256([]byte(s)); return hex.EncodeToString(h[:]) }),
+		"pex_head":               filterHead,
+		"pex_in_subnet":          filterInSubnet,
+		"pex_md5":                stringFilter(func(s string) string { h := md5.Sum([]byte(s)); return hex.EncodeToString(h[:]) }),
+		"pex_regex_replace":      filterRegexReplace,
+		"pex_regex_search":       filterRegexSearch,
+		"pex_require_min_length": filterRequireMinLength,
+		"pex_strlen": func(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+			return utf8.RuneCountInString(toString(args[0])), nil
+		},
+		"pex_tail":       filterTail,
+		"pex_to_json":    filterToJSON,
+		"pex_update":     filterUpdate,
+		"pex_url_encode": stringFilter(url.QueryEscape),
+	}
+	env.globals = map[string]interface{}{
+		"pex_debug_log":  function(env.debugLog),
+		"pex_now":        function(env.pexNow),
+		"pex_random_pin": function(env.randomPin),
+		"pex_uuid4":      function(env.uuid4),
+		"range":          function(globalRange),
+	}
+	return env
+}
+
+// stringFilter adapts a string function to a filter, converting its input to a string
+func stringFilter(f func(string) string) function {
+	return func(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+		return f(toString(args[0])), nil
+	}
+}
+
+// arg returns the i-th filter argument, counting the filtered value as argument 0, or the keyword
+// argument key, or def
+func arg(args []interface{}, kwargs map[string]interface{}, i int, key string, def interface{}) interface{} {
+	if i < len(args) {
+		return args[i]
+	}
+	if v, ok := kwargs[key]; ok {
+		return v
+	}
+	return def
+}
+
+func intArg(args []interface{}, kwargs map[string]interface{}, i int, key string, def int) (int, error) {
+	switch v := arg(args, kwargs, i, key, def).(type) {
+	case int:
+		return v, nil
+	case float64:
+		return int(v), nil
+	case bool:
+		return boolInt(v), nil
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("%s must be an integer, not %q", key, v)
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("%s must be an integer, not %s", key, typeName(v))
+	}
+}
+
+func filterIdentity(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	return args[0], nil
+}
+
+func filterDefault(args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	def := arg(args, kwargs, 1, "default_value", "")
+	if _, ok := args[0].(undefined); ok {
+		return def, nil
+	}
+	if truthy(arg(args, kwargs, 2, "boolean", false)) && !truthy(args[0]) {
+		return def, nil
+	}
+	return args[0], nil
+}
+
+func filterAbs(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case int:
+		return max(v, -v), nil
+	case float64:
+		return math.Abs(v), nil
+	}
+	return nil, fmt.Errorf("bad operand type for abs(): %s", typeName(args[0]))
+}
+
+func filterLength(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case string:
+		return utf8.RuneCountInString(v), nil
+	case []interface{}:
+		return len(v), nil
+	case map[string]interface{}:
+		return len(v), nil
+	case undefined:
+		return 0, nil
+	}
+	return nil, fmt.Errorf("object of type %s has no len()", typeName(args[0]))
+}
+
+func filterFirst(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	items, err := iterate(args[0], 0)
+	if err != nil || len(items) == 0 {
+		return undefined{name: "first"}, err
+	}
+	return items[0], nil
+}
+
+func filterLast(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	items, err := iterate(args[0], 0)
+	if err != nil || len(items) == 0 {
+		return undefined{name: "last"}, err
+	}
+	return items[len(items)-1], nil
+}
+
+func filterInt(args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	def := arg(args, kwargs, 1, "default", 0)
+	switch v := args[0].(type) {
+	case int:
+		return v, nil
+	case bool:
+		return boolInt(v), nil
+	case float64:
+		return int(v), nil
+	case string:
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return n, nil
+		}
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return int(f), nil
+		}
+	}
+	return def, nil
+}
+
+func filterFloat(args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	def := arg(args, kwargs, 1, "default", 0.0)
+	switch v := args[0].(type) {
+	case int:
+		return float64(v), nil
+	case bool:
+		return float64(boolInt(v)), nil
+	case float64:
+		return v, nil
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return f, nil
+		}
+	}
+	return def, nil
+}
+
+func filterJoin(args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	items, err := iterate(args[0], 0)
+	if err != nil {
+		return nil, err
+	}
+	attr, hasAttr := arg(args, kwargs, 2, "attribute", nil).(string)
+	parts := make([]string, len(items))
+	for i, item := range items {
+		if hasAttr {
+			if item, err = getAttr(item, attr, 0); err != nil {
+				return nil, err
+			}
+		}
+		parts[i] = toString(item)
+	}
+	return strings.Join(parts, toString(arg(args, kwargs, 1, "d", ""))), nil
+}
+
+func filterList(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	items, err := iterate(args[0], 0)
+	return slices.Clone(items), err
+}
+
+func filterReplace(args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("expected old and new arguments")
+	}
+	count, err := intArg(args, kwargs, 3, "count", -1)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Replace(toString(args[0]), toString(args[1]), toString(args[2]), count), nil
+}
+
+func filterReverse(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	if s, ok := args[0].(string); ok {
+		runes := []rune(s)
+		slices.Reverse(runes)
+		return string(runes), nil
+	}
+	items, err := iterate(args[0], 0)
+	if err != nil {
+		return nil, err
+	}
+	items = slices.Clone(items)
+	slices.Reverse(items)
+	return items, nil
+}
+
+func filterRound(args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	f, _ := toNumber(args[0])
+	if f == nil {
+		return nil, fmt.Errorf("cannot round %s", typeName(args[0]))
+	}
+	precision, err := intArg(args, kwargs, 1, "precision", 0)
+	if err != nil {
+		return nil, err
+	}
+	scale := math.Pow(10, float64(precision))
+	switch method := toString(arg(args, kwargs, 2, "method", "common")); method {
+	case "common":
+		return math.Round(*f*scale) / scale, nil
+	case "ceil":
+		return math.Ceil(*f*scale) / scale, nil
+	case "floor":
+		return math.Floor(*f*scale) / scale, nil
+	default:
+		return nil, fmt.Errorf("method must be common, ceil or floor, not %q", method)
+	}
+}
+
+func filterSort(args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	items, err := iterate(args[0], 0)
+	if err != nil {
+		return nil, err
+	}
+	items = slices.Clone(items)
+	reverse := truthy(arg(args, kwargs, 1, "reverse", false))
+	attr, hasAttr := arg(args, kwargs, 3, "attribute", nil).(string)
+	key := func(v interface{}) interface{} {
+		if hasAttr {
+			v, _ = getAttr(v, attr, 0)
+		}
+		if s, ok := v.(string); ok && !truthy(arg(args, kwargs, 2, "case_sensitive", false)) {
+			return strings.ToLower(s)
+		}
+		return v
+	}
+	slices.SortStableFunc(items, func(a, b interface{}) int {
+		c, cmpErr := compare(key(a), key(b))
+		if cmpErr != nil && err == nil {
+			err = cmpErr
+		}
+		if reverse {
+			return -c
+		}
+		return c
+	})
+	return items, err
+}
+
+func filterSum(args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	items, err := iterate(args[0], 0)
+	if err != nil {
+		return nil, err
+	}
+	attr, hasAttr := arg(args, kwargs, 1, "attribute", nil).(string)
+	total := arg(args, kwargs, 2, "start", 0)
+	for _, item := range items {
+		if hasAttr {
+			if item, err = getAttr(item, attr, 0); err != nil {
+				return nil, err
+			}
+		}
+		if total, err = arithmetic("+", total, item); err != nil {
+			return nil, err
+		}
+	}
+	return total, nil
+}
+
+func filterUnique(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	items, err := iterate(args[0], 0)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, item := range items {
+		if !slices.ContainsFunc(out, func(v interface{}) bool { return equal(v, item) }) {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+// minMax returns the min (sign -1) or max (sign 1) filter
+func minMax(sign int) function {
+	return func(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+		items, err := iterate(args[0], 0)
+		if err != nil || len(items) == 0 {
+			return undefined{name: "min"}, err
+		}
+		best := items[0]
+		for _, item := range items[1:] {
+			c, err := compare(item, best)
+			if err != nil {
+				return nil, err
+			}
+			if c*sign > 0 {
+				best = item
+			}
+		}
+		return best, nil
+	}
+}
+
+// filterToJSON serializes a value as JSON, as pex_to_json and tojson do
+func filterToJSON(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(toJSONValue(args[0])); err != nil {
+		return nil, err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// toJSONValue replaces undefined values, which have no JSON form, with null
+func toJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case undefined, function:
+		return nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = toJSONValue(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = toJSONValue(item)
+		}
+		return out
+	}
+	return v
+}
+
+// cleanPhoneNumber strips everything but digits and a leading + from a phone number
+func cleanPhoneNumber(s string) string {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(s) {
+		if r >= '0' && r <= '9' || r == '+' && i == 0 {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func filterHead(args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	if arg(args, kwargs, 1, "length", nil) == nil {
+		return nil, fmt.Errorf("expected length argument")
+	}
+	n, err := intArg(args, kwargs, 1, "length", 0)
+	if err != nil {
+		return nil, err
+	}
+	runes := []rune(toString(args[0]))
+	return string(runes[:min(max(n, 0), len(runes))]), nil
+}
+
+func filterTail(args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	if arg(args, kwargs, 1, "length", nil) == nil {
+		return nil, fmt.Errorf("expected length argument")
+	}
+	n, err := intArg(args, kwargs, 1, "length", 0)
+	if err != nil {
+		return nil, err
+	}
+	runes := []rune(toString(args[0]))
+	return string(runes[len(runes)-min(max(n, 0), len(runes)):]), nil
+}
+
+func filterRequireMinLength(args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	n, err := intArg(args, kwargs, 1, "length", 0)
+	if err != nil {
+		return nil, err
+	}
+	s := toString(args[0])
+	if utf8.RuneCountInString(s) < n {
+		return nil, fmt.Errorf("%q is shorter than %d characters", s, n)
+	}
+	return s, nil
+}
+
+// compileRegex compiles a Python regular expression, which for the patterns used in policy is
+// the same syntax as Go's
+func compileRegex(pattern interface{}) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(toString(pattern))
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+	return re, nil
+}
+
+var pythonGroupRef = regexp.MustCompile(`\\(\d+)|\\g<(\w+)>`)
+
+// filterRegexReplace replaces every match of a pattern, accepting Python's \1 and \g<name> references
+func filterRegexReplace(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("expected pattern and replacement arguments")
+	}
+	re, err := compileRegex(args[1])
+	if err != nil {
+		return nil, err
+	}
+	repl := strings.ReplaceAll(toString(args[2]), "$", "$$")
+	repl = pythonGroupRef.ReplaceAllString(repl, "$${$1$2}")
+	return re.ReplaceAllString(toString(args[0]), repl), nil
+}
+
+// filterRegexSearch returns the groups of the first match of a pattern, the whole match if the
+// pattern has no groups, or None
+func filterRegexSearch(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("expected pattern argument")
+	}
+	re, err := compileRegex(args[1])
+	if err != nil {
+		return nil, err
+	}
+	match := re.FindStringSubmatch(toString(args[0]))
+	if match == nil {
+		return nil, nil
+	}
+	if len(match) == 1 {
+		return []interface{}{match[0]}, nil
+	}
+	groups := make([]interface{}, len(match)-1)
+	for i, g := range match[1:] {
+		groups[i] = g
+	}
+	return groups, nil
+}
+
+// filterFindFirstMatch returns the first item of a list that matches a pattern, or None
+func filterFindFirstMatch(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("expected pattern argument")
+	}
+	re, err := compileRegex(args[1])
+	if err != nil {
+		return nil, err
+	}
+	items, err := iterate(args[0], 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if loc := re.FindStringIndex(toString(item)); loc != nil && loc[0] == 0 {
+			return item, nil
+		}
+	}
+	return nil, nil
+}
+
+// filterInSubnet reports whether an address is in any of the given subnets, which may be passed
+// as separate arguments or as a list
+func filterInSubnet(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	ip := net.ParseIP(toString(args[0]))
+	if ip == nil {
+		return false, nil
+	}
+	var subnets []interface{}
+	for _, a := range args[1:] {
+		if list, ok := a.([]interface{}); ok {
+			subnets = append(subnets, list...)
+		} else {
+			subnets = append(subnets, a)
+		}
+	}
+	for _, s := range subnets {
+		_, network, err := net.ParseCIDR(toString(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet %q", toString(s))
+		}
+		if network.Contains(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// filterUpdate returns a copy of a dict updated with the keys of another
+func filterUpdate(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	dict, ok := args[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a dict, not %s", typeName(args[0]))
+	}
+	out := maps.Clone(dict)
+	for _, a := range args[1:] {
+		update, ok := a.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected a dict, not %s", typeName(a))
+		}
+		maps.Copy(out, update)
+	}
+	return out, nil
+}
+
+// debugLog records its arguments, which Infinity writes to the support log
+func (env *environment) debugLog(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = toString(a)
+	}
+	env.logs = append(env.logs, strings.Join(parts, ""))
+	return "", nil
+}
+
+// pexNow returns the current time in UTC or the named time zone, formatted as Python prints a datetime
+func (env *environment) pexNow(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	now := env.now().UTC()
+	if len(args) > 0 && args[0] != nil {
+		loc, err := time.LoadLocation(toString(args[0]))
+		if err != nil {
+			return nil, fmt.Errorf("unknown time zone %q", toString(args[0]))
+		}
+		now = now.In(loc)
+	}
+	return now.Format("2006-01-02 15:04:05.000000-07:00"), nil
+}
+
+// randomPin returns a random string of digits of the given length
+func (env *environment) randomPin(args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	n, err := intArg(append([]interface{}{nil}, args...), kwargs, 1, "length", 0)
+	if err != nil {
+		return nil, err
+	}
+	digits := make([]byte, max(n, 0))
+	for i := range digits {
+		d, err := randInt(env.rand, 10)
+		if err != nil {
+			return nil, err
+		}
+		digits[i] = byte('0' + d)
+	}
+	return string(digits), nil
+}
+
+// uuid4 returns a random version 4 UUID
+func (env *environment) uuid4([]interface{}, map[string]interface{}) (interface{}, error) {
+	var b [16]byte
+	if _, err := io.ReadFull(env.rand, b[:]); err != nil {
+		return nil, err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+}
+
+func randInt(r io.Reader, n int64) (int64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return new(big.Int).Mod(new(big.Int).SetBytes(b[:]), big.NewInt(n)).Int64(), nil
+}
+
+// maxRange is the largest range a template may create, as in Jinja's sandbox
+const maxRange = 100000
+
+func globalRange(args []interface{}, _ map[string]interface{}) (interface{}, error) {
+	bounds := make([]int, len(args))
+	for i, a := range args {
+		n, ok := a.(int)
+		if !ok {
+			return nil, fmt.Errorf("range arguments must be integers")
+		}
+		bounds[i] = n
+	}
+	start, stop, step := 0, 0, 1
+	switch len(bounds) {
+	case 1:
+		stop = bounds[0]
+	case 2:
+		start, stop = bounds[0], bounds[1]
+	case 3:
+		start, stop, step = bounds[0], bounds[1], bounds[2]
+	default:
+		return nil, fmt.Errorf("range expected 1 to 3 arguments")
+	}
+	if step == 0 {
+		return nil, fmt.Errorf("range step cannot be zero")
+	}
+	var out []interface{}
+	for i := start; step > 0 && i < stop || step < 0 && i > stop; i += step {
+		if len(out) == maxRange {
+			return nil, fmt.Errorf("range too big, the maximum is %d items", maxRange)
+		}
+		out = append(out, i)
+		if step > 0 && i > math.MaxInt-step || step < 0 && i < math.MinInt-step {
+			break
+		}
+	}
+	return out, nil
+}
+
+// tests are the functions available after "is"
+var tests = map[string]func(v interface{}, args []interface{}) (bool, error){
+	"defined":   func(v interface{}, _ []interface{}) (bool, error) { _, ok := v.(undefined); return !ok, nil },
+	"undefined": func(v interface{}, _ []interface{}) (bool, error) { _, ok := v.(undefined); return ok, nil },
+	"none":      func(v interface{}, _ []interface{}) (bool, error) { return v == nil, nil },
+	"string":    func(v interface{}, _ []interface{}) (bool, error) { _, ok := v.(string); return ok, nil },
+	"number": func(v interface{}, _ []interface{}) (bool, error) {
+		switch v.(type) {
+		case int, float64:
+			return true, nil
+		}
+		return false, nil
+	},
+	"mapping": func(v interface{}, _ []interface{}) (bool, error) {
+		_, ok := v.(map[string]interface{})
+		return ok, nil
+	},
+	"sequence": func(v interface{}, _ []interface{}) (bool, error) {
+		_, err := iterate(v, 0)
+		return err == nil && v != nil, nil
+	},
+	"lower": func(v interface{}, _ []interface{}) (bool, error) {
+		s := toString(v)
+		return s == strings.ToLower(s), nil
+	},
+	"upper": func(v interface{}, _ []interface{}) (bool, error) {
+		s := toString(v)
+		return s == strings.ToUpper(s), nil
+	},
+	"even": func(v interface{}, _ []interface{}) (bool, error) {
+		n, ok := v.(int)
+		return ok && n%2 == 0, nil
+	},
+	"odd": func(v interface{}, _ []interface{}) (bool, error) {
+		n, ok := v.(int)
+		return ok && n%2 != 0, nil
+	},
+	"divisibleby": func(v interface{}, args []interface{}) (bool, error) {
+		n, ok := v.(int)
+		d, dok := arg(args, nil, 0, "num", nil).(int)
+		if !ok || !dok || d == 0 {
+			return false, fmt.Errorf("divisibleby requires integers")
+		}
+		return n%d == 0, nil
+	},
+	"in": func(v interface{}, args []interface{}) (bool, error) {
+		if len(args) == 0 {
+			return false, fmt.Errorf("in requires a container")
+		}
+		return contains(args[0], v)
+	},
+	"sameas": func(v interface{}, args []interface{}) (bool, error) {
+		return len(args) > 0 && equal(v, args[0]), nil
+	},
+	"eq": func(v interface{}, args []interface{}) (bool, error) { return len(args) > 0 && equal(v, args[0]), nil },
+	"ne": func(v interface{}, args []interface{}) (bool, error) { return len(args) > 0 && !equal(v, args[0]), nil },
+}
@@ -0,0 +1,79 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package localpolicy
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilters(t *testing.T) {
+	ctx := Context{
+		"call_info": map[string]interface{}{
+			"remote_alias":   "sip:+44 (20) 7946-0018@example.com",
+			"remote_address": "10.44.1.7",
+			"local_alias":    "meet.alice@example.com",
+		},
+		"aliases": []interface{}{"sip:bob@example.com", "alice@example.com", "carol@example.org"},
+		"config":  map[string]interface{}{"name": "Sales", "pin": "1234"},
+	}
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"default", "{{ missing|default('x') }} {{ ''|d('y', true) }}", "x y"},
+		{"case", "{{ 'Hello World'|lower }} {{ 'abc'|upper }} {{ 'hello world'|title }}", "hello world ABC Hello World"},
+		{"length", "{{ aliases|length }} {{ 'abc'|count }}", "3 3"},
+		{"join", "{{ [1, 2, 3]|join(',') }}", "1,2,3"},
+		{"first last", "{{ aliases|first }} {{ aliases|last }}", "sip:bob@example.com carol@example.org"},
+		{"int float", "{{ '42'|int + 1 }} {{ 'x'|int(7) }} {{ '1.5'|float }}", "43 7 1.5"},
+		{"replace", "{{ 'a.b.c'|replace('.', '-') }}", "a-b-c"},
+		{"sort unique", "{{ [3, 1, 2, 1]|unique|sort }}", "[1, 2, 3]"},
+		{"round", "{{ 2.567|round(1) }}", "2.6"},
+		{"min max sum", "{{ [3, 1, 2]|min }} {{ [3, 1, 2]|max }} {{ [3, 1, 2]|sum }}", "1 3 6"},
+		{"tojson", "{{ config|tojson }}", `{"name":"Sales","pin":"1234"}`},
+		{"pex_to_json", "{{ {'a': '<b>', 'n': none}|pex_to_json }}", `{"a":"<b>","n":null}`},
+		{"pex_base64", "{{ 'alice'|pex_base64 }}", "YWxpY2U="},
+		{"pex_clean_phone_number", "{{ '+44 (20) 7946-0018'|pex_clean_phone_number }}", "+442079460018"},
+		{"pex_hash", "{{ 'abc'|pex_hash }}", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{"pex_md5", "{{ 'abc'|pex_md5 }}", "900150983cd24fb0d6963f7d28e17f72"},
+		{"pex_head pex_tail", "{{ '0123456789'|pex_head(3) }} {{ '0123456789'|pex_tail(4) }}", "012 6789"},
+		{"pex_strlen", "{{ 'héllo'|pex_strlen }}", "5"},
+		{"pex_url_encode", "{{ 'a b&c'|pex_url_encode }}", "a+b%26c"},
+		{"pex_in_subnet", "{{ call_info.remote_address|pex_in_subnet('192.168.0.0/16', '10.0.0.0/8') }} {{ '8.8.8.8'|pex_in_subnet(['10.0.0.0/8']) }}", "True False"},
+		{"pex_regex_replace", `{{ call_info.local_alias|pex_regex_replace('^meet\.(.+)@.*$', '\\1') }}`, "alice"},
+		{"pex_regex_search", `{{ call_info.remote_alias|pex_regex_search('^sip:(.+)@(.+)$') }} {{ 'x'|pex_regex_search('y') }}`, "['+44 (20) 7946-0018', 'example.com'] None"},
+		{"pex_find_first_match", `{{ aliases|pex_find_first_match('alice@') }}`, "alice@example.com"},
+		{"pex_update", "{{ config|pex_update({'pin': '0000'}) }} {{ config.pin }}", "{'name': 'Sales', 'pin': '0000'} 1234"},
+		{"pex_require_min_length", "{{ '1234'|pex_require_min_length(4) }}", "1234"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse(tt.template)
+			require.NoError(t, err)
+			got, err := tmpl.Render(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGlobals(t *testing.T) {
+	now := func() time.Time { return time.Date(2025, 3, 14, 15, 9, 26, 535000000, time.UTC) }
+	rand := bytes.NewReader(bytes.Repeat([]byte{0xab}, 64))
+
+	tmpl, err := Parse(`{{ pex_now() }}|{{ pex_now('Europe/Oslo') }}|{{ pex_random_pin(6)|length }}|{{ pex_uuid4() }}`)
+	require.NoError(t, err)
+	got, err := tmpl.Render(nil, WithNow(now), WithRand(rand))
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-14 15:09:26.535000+00:00|2025-03-14 16:09:26.535000+01:00|6|abababab-abab-4bab-abab-abababababab", got)
+}
@@ -0,0 +1,212 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package localpolicy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// segmentKind is the kind of a piece of template source
+type segmentKind int
+
+const (
+	segText  segmentKind = iota
+	segPrint             // {{ ... }}
+	segBlock             // {% ... %}
+)
+
+// segment is literal text or the inside of a {{ }} or {% %} tag
+type segment struct {
+	kind segmentKind
+	text string
+	line int
+}
+
+// Error is a template syntax or rendering error. Line is 0 when the error is not tied to a line.
+type Error struct {
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return e.Msg
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+func errorf(line int, format string, args ...interface{}) *Error {
+	return &Error{Line: line, Msg: fmt.Sprintf(format, args...)}
+}
+
+var endRaw = regexp.MustCompile(`\{%-?\s*endraw\s*(-?)%\}`)
+
+// nextTag returns the index of the next {{, {% or {#, or -1
+func nextTag(s string) int {
+	for i := 0; i+1 < len(s); i++ {
+		if s[i] == '{' && (s[i+1] == '{' || s[i+1] == '%' || s[i+1] == '#') {
+			return i
+		}
+	}
+	return -1
+}
+
+// split divides source into text and tags, applying "-" whitespace control and dropping comments
+func split(source string) ([]segment, error) {
+	var segments []segment
+	line := 1
+	trimNext := false
+	addText := func(text string, trimRight bool) {
+		if trimNext {
+			text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		}
+		if trimRight {
+			text = strings.TrimRightFunc(text, unicode.IsSpace)
+		}
+		if text != "" {
+			segments = append(segments, segment{kind: segText, text: text, line: line})
+		}
+	}
+
+	for {
+		start := nextTag(source)
+		if start < 0 {
+			addText(source, false)
+			return segments, nil
+		}
+		addText(source[:start], strings.HasPrefix(source[start+2:], "-"))
+		line += strings.Count(source[:start], "\n")
+
+		open := source[start+1]
+		closer := map[byte]string{'{': "}}", '%': "%}", '#': "#}"}[open]
+		end := strings.Index(source[start+2:], closer)
+		if end < 0 {
+			return nil, errorf(line, "unclosed %q", source[start:start+2])
+		}
+		inner := source[start+2 : start+2+end]
+		source = source[start+2+end+2:]
+		tagLine := line
+		line += strings.Count(inner, "\n")
+
+		inner = strings.TrimPrefix(inner, "-")
+		trimNext = strings.HasSuffix(inner, "-")
+		inner = strings.TrimSuffix(inner, "-")
+
+		switch {
+		case open == '{':
+			segments = append(segments, segment{kind: segPrint, text: inner, line: tagLine})
+		case open == '%' && strings.TrimSpace(inner) == "raw":
+			loc := endRaw.FindStringSubmatchIndex(source)
+			if loc == nil {
+				return nil, errorf(tagLine, "unclosed raw block")
+			}
+			addText(source[:loc[0]], strings.HasPrefix(source[loc[0]+2:], "-"))
+			line += strings.Count(source[:loc[1]], "\n")
+			trimNext = loc[3] > loc[2]
+			source = source[loc[1]:]
+		case open == '%':
+			segments = append(segments, segment{kind: segBlock, text: inner, line: tagLine})
+		}
+	}
+}
+
+// tokenKind is the kind of an expression token
+type tokenKind int
+
+const (
+	tokName tokenKind = iota
+	tokString
+	tokInt
+	tokFloat
+	tokOp
+	tokEOF
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// operators are matched longest first
+var operators = []string{"**", "//", "==", "!=", "<=", ">=", "(", ")", "[", "]", "{", "}", ".", ",", ":", "|", "~", "+", "-", "*", "/", "%", "<", ">", "="}
+
+// tokenize splits an expression into tokens
+func tokenize(src string, line int) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			tokens = append(tokens, token{tokName, src[i:j]})
+			i = j
+		case unicode.IsDigit(rune(c)):
+			j := i
+			kind := tokInt
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '_') {
+				j++
+			}
+			if j+1 < len(src) && src[j] == '.' && unicode.IsDigit(rune(src[j+1])) {
+				kind = tokFloat
+				j++
+				for j < len(src) && unicode.IsDigit(rune(src[j])) {
+					j++
+				}
+			}
+			tokens = append(tokens, token{kind, strings.ReplaceAll(src[i:j], "_", "")})
+			i = j
+		case c == '"' || c == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(src) && src[j] != c; j++ {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+					switch src[j] {
+					case 'n':
+						b.WriteByte('\n')
+					case 't':
+						b.WriteByte('\t')
+					case '\\', '\'', '"':
+						b.WriteByte(src[j])
+					default:
+						b.WriteByte('\\')
+						b.WriteByte(src[j])
+					}
+					continue
+				}
+				b.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, errorf(line, "unterminated string")
+			}
+			tokens = append(tokens, token{tokString, b.String()})
+			i = j + 1
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, token{tokOp, op})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, errorf(line, "unexpected character %q", c)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokEOF}), nil
+}
@@ -0,0 +1,206 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package localpolicy renders Pexip Infinity local policy templates so they can be tested before
+// they are deployed with a policy server.
+//
+// Infinity evaluates the internal service, participant and media location policy templates of a
+// policy server as Jinja2 templates. This package implements the subset of Jinja2 used by policy
+// (expressions, filters, tests, if, for and set) together with Infinity's pex_* filters and
+// functions, and decodes the rendered output as the JSON policy response.
+package localpolicy
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pexip/go-infinity-sdk/v41/config"
+)
+
+// Kind identifies one of the local policy templates of a policy server
+type Kind string
+
+const (
+	// ServicePolicy is the internal service policy template, rendered with call_info and service_config
+	ServicePolicy Kind = "service"
+	// ParticipantPolicy is the internal participant policy template, rendered with call_info and participant
+	ParticipantPolicy Kind = "participant"
+	// MediaLocationPolicy is the internal media location policy template, rendered with call_info and suggested_media_overflow_locations
+	MediaLocationPolicy Kind = "media_location"
+)
+
+// ErrInvalidResponse is returned when a template renders output that is not a JSON policy response
+var ErrInvalidResponse = errors.New("template did not render a valid policy response")
+
+// Context is the set of variables a template is rendered with, such as call_info and service_config.
+// Values may be any type that encodes to JSON, including the SDK's configuration and status types.
+type Context map[string]interface{}
+
+// Result is the outcome of evaluating a template
+type Result struct {
+	// Output is the rendered template
+	Output string
+	// Response is Output decoded as JSON
+	Response map[string]interface{}
+	// Logs holds the messages passed to pex_debug_log
+	Logs []string
+}
+
+// Action returns the action of the policy response, such as "continue" or "reject"
+func (r *Result) Action() string {
+	action, _ := r.Response["action"].(string)
+	return action
+}
+
+// Result returns the result object of the policy response
+func (r *Result) Result() map[string]interface{} {
+	result, _ := r.Response["result"].(map[string]interface{})
+	return result
+}
+
+// Option configures a render
+type Option func(*options)
+
+type options struct {
+	now  func() time.Time
+	rand io.Reader
+}
+
+// WithNow sets the clock used by pex_now
+func WithNow(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithRand sets the source of randomness used by pex_random_pin and pex_uuid4
+func WithRand(r io.Reader) Option {
+	return func(o *options) {
+		o.rand = r
+	}
+}
+
+// Template is a parsed local policy template
+type Template struct {
+	body []node
+}
+
+// Parse parses a template, returning an *Error for syntax errors
+func Parse(source string) (*Template, error) {
+	body, err := parse(source)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{body: body}, nil
+}
+
+// Render renders the template with ctx, returning an *Error for rendering errors
+func (t *Template) Render(ctx Context, opts ...Option) (string, error) {
+	out, _, err := t.render(ctx, opts)
+	return out, err
+}
+
+// Evaluate renders the template with ctx and decodes its output as a policy response. The result is
+// returned along with ErrInvalidResponse when the output is not a JSON object with an action.
+func (t *Template) Evaluate(ctx Context, opts ...Option) (*Result, error) {
+	out, logs, err := t.render(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Output: out, Logs: logs}
+	if err := json.Unmarshal([]byte(out), &result.Response); err != nil {
+		return result, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+	if result.Action() == "" {
+		return result, fmt.Errorf("%w: missing action", ErrInvalidResponse)
+	}
+	return result, nil
+}
+
+func (t *Template) render(ctx Context, opts []Option) (string, []string, error) {
+	o := &options{now: time.Now, rand: rand.Reader}
+	for _, opt := range opts {
+		opt(o)
+	}
+	vars, err := normalize(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	s := &state{env: newEnvironment(o)}
+	s.push(vars)
+	if err := s.execute(t.body); err != nil {
+		return "", nil, err
+	}
+	return s.out.String(), s.env.logs, nil
+}
+
+// Evaluate parses source and evaluates it with ctx
+func Evaluate(source string, ctx Context, opts ...Option) (*Result, error) {
+	t, err := Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	return t.Evaluate(ctx, opts...)
+}
+
+// TemplateFor returns the template of the given kind from a policy server, or an empty string if
+// the server does not use internal policy of that kind
+func TemplateFor(server *config.PolicyServer, kind Kind) string {
+	switch {
+	case kind == ServicePolicy && server.EnableInternalServicePolicy:
+		return server.InternalServicePolicyTemplate
+	case kind == ParticipantPolicy && server.EnableInternalParticipantPolicy:
+		return server.InternalParticipantPolicyTemplate
+	case kind == MediaLocationPolicy && server.EnableInternalMediaLocationPolicy:
+		return server.InternalMediaLocationPolicyTemplate
+	}
+	return ""
+}
+
+// normalize converts ctx to the plain values templates operate on by round-tripping it through JSON
+func normalize(ctx Context) (map[string]interface{}, error) {
+	data, err := json.Marshal(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode context: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var vars map[string]interface{}
+	if err := dec.Decode(&vars); err != nil {
+		return nil, fmt.Errorf("failed to decode context: %w", err)
+	}
+	if vars == nil {
+		vars = make(map[string]interface{})
+	}
+	return convertNumbers(vars).(map[string]interface{}), nil
+}
+
+// convertNumbers replaces json.Number with int where the number is integral and float64 otherwise
+func convertNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := strconv.Atoi(v.String()); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertNumbers(item)
+		}
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = convertNumbers(item)
+		}
+	}
+	return v
+}
@@ -0,0 +1,187 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package localpolicy
+
+import (
+	"testing"
+
+	"github.com/pexip/go-infinity-sdk/v41/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// defaultServiceTemplate is the service policy template Infinity ships with
+const defaultServiceTemplate = `{
+  {% if service_config %}
+    "action" : "continue",
+    "result" : {{service_config|pex_to_json}}
+  {% else %}
+    "action" : "reject",
+    "result" : {}
+  {% endif %}
+}`
+
+func TestEvaluate_DefaultServiceTemplate(t *testing.T) {
+	ctx := Context{
+		"call_info":      map[string]interface{}{"local_alias": "meet@example.com"},
+		"service_config": map[string]interface{}{"name": "Sales", "service_type": "conference", "pin": "1234"},
+	}
+	result, err := Evaluate(defaultServiceTemplate, ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "continue", result.Action())
+	assert.Equal(t, "Sales", result.Result()["name"])
+
+	result, err = Evaluate(defaultServiceTemplate, Context{"service_config": nil})
+	require.NoError(t, err)
+	assert.Equal(t, "reject", result.Action())
+	assert.Empty(t, result.Result())
+}
+
+func TestEvaluate_SDKTypes(t *testing.T) {
+	template := `{"action": "continue", "result": {{ service_config|pex_update({"pin": "9999"})|pex_to_json }}}`
+	result, err := Evaluate(template, Context{"service_config": config.Conference{Name: "Board", ServiceType: "conference", PIN: "1234"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Board", result.Result()["name"])
+	assert.Equal(t, "9999", result.Result()["pin"])
+}
+
+func TestEvaluate_InvalidResponse(t *testing.T) {
+	result, err := Evaluate(`{"action": "continue", "result": {{ service_config }}}`, Context{"service_config": map[string]interface{}{"name": "x"}})
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+	require.NotNil(t, result)
+	assert.Equal(t, `{"action": "continue", "result": {'name': 'x'}}`, result.Output)
+
+	_, err = Evaluate(`{"result": {}}`, nil)
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+}
+
+func TestEvaluate_DebugLog(t *testing.T) {
+	template := `{{ pex_debug_log("alias: ", call_info.remote_alias) }}{"action": "continue", "result": {}}`
+	result, err := Evaluate(template, Context{"call_info": map[string]interface{}{"remote_alias": "sip:alice@example.com"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alias: sip:alice@example.com"}, result.Logs)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		line     int
+	}{
+		{"unclosed if", "{% if a %}\nx", 1},
+		{"unclosed print", "a\n{{ b", 2},
+		{"unknown tag", "\n\n{% include 'x' %}", 3},
+		{"bad expression", "{{ a + }}", 1},
+		{"stray endfor", "{% endfor %}", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.template)
+			var perr *Error
+			require.ErrorAs(t, err, &perr)
+			assert.Equal(t, tt.line, perr.Line)
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	ctx := Context{
+		"call_info": map[string]interface{}{
+			"local_alias":  "meet.alice@example.com",
+			"remote_alias": "sip:bob@example.com",
+			"protocol":     "sip",
+			"bandwidth":    2048,
+		},
+		"aliases": []interface{}{"a", "b", "c"},
+		"score":   1.5,
+	}
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"text", "hello", "hello"},
+		{"variable", "{{ call_info.protocol }}", "sip"},
+		{"subscript", "{{ call_info['protocol'] }}", "sip"},
+		{"undefined", "[{{ missing }}]", "[]"},
+		{"arithmetic", "{{ call_info.bandwidth // 1000 + 2 * 3 }}", "8"},
+		{"float", "{{ score * 2 }}", "3.0"},
+		{"concat", "{{ call_info.protocol ~ ':' ~ 1 }}", "sip:1"},
+		{"comparison", "{{ call_info.bandwidth > 1000 and call_info.protocol == 'sip' }}", "True"},
+		{"in", "{{ 'alice' in call_info.local_alias }}", "True"},
+		{"not in", "{{ 'd' not in aliases }}", "True"},
+		{"conditional", "{{ 'yes' if call_info.protocol == 'h323' else 'no' }}", "no"},
+		{"if elif else", "{% if score > 2 %}a{% elif score > 1 %}b{% else %}c{% endif %}", "b"},
+		{"is defined", "{{ call_info is defined }} {{ missing is defined }} {{ missing is not defined }}", "True False True"},
+		{"for loop", "{% for a in aliases %}{{ loop.index }}{{ a }}{% if not loop.last %},{% endif %}{% endfor %}", "1a,2b,3c"},
+		{"for else", "{% for a in [] %}x{% else %}empty{% endfor %}", "empty"},
+		{"for filter", "{% for a in aliases if a != 'b' %}{{ a }}{% endfor %}", "ac"},
+		{"for unpack", "{% for k, v in {'x': 1, 'y': 2}.items() %}{{ k }}={{ v }};{% endfor %}", "x=1;y=2;"},
+		{"set", "{% set user = call_info.local_alias.split('@')[0] %}{{ user }}", "meet.alice"},
+		{"slice", "{{ call_info.protocol[1:] }} {{ aliases[::-1] }}", "ip ['c', 'b', 'a']"},
+		{"methods", "{{ call_info.local_alias.startswith('meet.') }} {{ 'A b'.lower() }}", "True a b"},
+		{"whitespace control", "a  {%- if true -%}  b  {%- endif -%}  c", "abc"},
+		{"comment", "a{# ignored #}b", "ab"},
+		{"raw", "{% raw %}{{ x }}{% endraw %}", "{{ x }}"},
+		{"literals", "{{ [1, 'a', none, true] }} {{ {'k': 1.0} }}", "[1, 'a', None, True] {'k': 1.0}"},
+		{"none", "{{ none }}", "None"},
+		{"range", "{% for i in range(3) %}{{ i }}{% endfor %}", "012"},
+		{"range bounds", "{{ range(100000)|length }} {{ range(9223372036854775806, 9223372036854775807, 5) }}", "100000 [9223372036854775806]"},
+		{"large integers", "{{ 2 ** 62 }} {{ 9223372036854775807 - 1 }} {{ -7 // 2 }}", "4611686018427387904 9223372036854775806 -4"},
+		{"format", "{{ '{}-{}'.format('a', 2) }}", "a-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse(tt.template)
+			require.NoError(t, err)
+			got, err := tmpl.Render(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		line     int
+	}{
+		{"undefined attribute", "\n{{ missing.name }}", 2},
+		{"unknown filter", "{{ 'a'|nope }}", 1},
+		{"type error", "\n\n{{ 1 + 'a' }}", 3},
+		{"division by zero", "{{ 1 / 0 }}", 1},
+		{"filter error", "{% if true %}\n{{ '12'|pex_require_min_length(4) }}{% endif %}", 2},
+		{"missing filter argument", "{{ '0123'|pex_head }}", 1},
+		{"range too big", "{% for i in range(100001) %}{% endfor %}", 1},
+		{"power overflow", "{{ 2 ** 1000 }}", 1},
+		{"addition overflow", "{{ 9223372036854775807 + 1 }}", 1},
+		{"multiplication overflow", "{{ 4611686018427387904 * 2 }}", 1},
+		{"negation overflow", "{{ -(-9223372036854775807 - 1) }}", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse(tt.template)
+			require.NoError(t, err)
+			_, err = tmpl.Render(nil)
+			var rerr *Error
+			require.ErrorAs(t, err, &rerr)
+			assert.Equal(t, tt.line, rerr.Line)
+		})
+	}
+}
+
+func TestTemplateFor(t *testing.T) {
+	server := &config.PolicyServer{
+		EnableInternalServicePolicy:       true,
+		InternalServicePolicyTemplate:     "service",
+		InternalParticipantPolicyTemplate: "participant",
+	}
+	assert.Equal(t, "service", TemplateFor(server, ServicePolicy))
+	assert.Empty(t, TemplateFor(server, ParticipantPolicy))
+	assert.Empty(t, TemplateFor(server, MediaLocationPolicy))
+}
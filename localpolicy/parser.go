@@ -0,0 +1,706 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package localpolicy
+
+import (
+	"strconv"
+	"strings"
+)
+
+// node is a statement of a parsed template
+type node interface{}
+
+type textNode struct {
+	text string
+}
+
+type printNode struct {
+	expr expr
+	line int
+}
+
+type ifBranch struct {
+	cond expr
+	body []node
+}
+
+type ifNode struct {
+	branches []ifBranch
+	els      []node
+}
+
+type forNode struct {
+	vars []string
+	iter expr
+	cond expr
+	body []node
+	els  []node
+	line int
+}
+
+type setNode struct {
+	names []string
+	value expr
+	line  int
+}
+
+// expr is an expression of a parsed template
+type expr interface{}
+
+type literal struct {
+	value interface{}
+}
+
+type name struct {
+	name string
+	line int
+}
+
+type listExpr struct {
+	items []expr
+}
+
+type dictExpr struct {
+	keys   []expr
+	values []expr
+}
+
+type attrExpr struct {
+	obj  expr
+	attr string
+	line int
+}
+
+type indexExpr struct {
+	obj   expr
+	index expr
+	line  int
+}
+
+type sliceExpr struct {
+	obj               expr
+	start, stop, step expr
+	line              int
+}
+
+type callExpr struct {
+	fn     expr
+	args   []expr
+	kwargs map[string]expr
+	line   int
+}
+
+type filterExpr struct {
+	value  expr
+	name   string
+	args   []expr
+	kwargs map[string]expr
+	line   int
+}
+
+type testExpr struct {
+	value  expr
+	name   string
+	args   []expr
+	negate bool
+	line   int
+}
+
+type unaryExpr struct {
+	op      string
+	operand expr
+	line    int
+}
+
+type binaryExpr struct {
+	op          string
+	left, right expr
+	line        int
+}
+
+type condExpr struct {
+	cond, then, els expr
+}
+
+// parser turns template segments into statements
+type parser struct {
+	segments []segment
+	pos      int
+}
+
+func parse(source string) ([]node, error) {
+	segments, err := split(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{segments: segments}
+	body, end, err := p.parseBody()
+	if err != nil {
+		return nil, err
+	}
+	if end != "" {
+		return nil, errorf(p.segments[p.pos-1].line, "unexpected %q", end)
+	}
+	return body, nil
+}
+
+// parseBody parses statements until the end of the template or a block tag it does not handle, which it
+// returns with the parser positioned after it
+func (p *parser) parseBody() ([]node, string, error) {
+	var body []node
+	for p.pos < len(p.segments) {
+		seg := p.segments[p.pos]
+		p.pos++
+		switch seg.kind {
+		case segText:
+			body = append(body, &textNode{text: seg.text})
+		case segPrint:
+			e, err := parseExpr(seg.text, seg.line)
+			if err != nil {
+				return nil, "", err
+			}
+			body = append(body, &printNode{expr: e, line: seg.line})
+		case segBlock:
+			keyword, rest, _ := strings.Cut(strings.TrimSpace(seg.text), " ")
+			rest = strings.TrimSpace(rest)
+			switch keyword {
+			case "if":
+				n, err := p.parseIf(rest, seg.line)
+				if err != nil {
+					return nil, "", err
+				}
+				body = append(body, n)
+			case "for":
+				n, err := p.parseFor(rest, seg.line)
+				if err != nil {
+					return nil, "", err
+				}
+				body = append(body, n)
+			case "set":
+				n, err := parseSet(rest, seg.line)
+				if err != nil {
+					return nil, "", err
+				}
+				body = append(body, n)
+			case "elif", "else", "endif", "endfor":
+				return body, strings.TrimSpace(seg.text), nil
+			default:
+				return nil, "", errorf(seg.line, "unknown tag %q", keyword)
+			}
+		}
+	}
+	return body, "", nil
+}
+
+func (p *parser) parseIf(cond string, line int) (*ifNode, error) {
+	n := &ifNode{}
+	for {
+		c, err := parseExpr(cond, line)
+		if err != nil {
+			return nil, err
+		}
+		body, end, err := p.parseBody()
+		if err != nil {
+			return nil, err
+		}
+		n.branches = append(n.branches, ifBranch{cond: c, body: body})
+
+		switch keyword, rest, _ := strings.Cut(end, " "); keyword {
+		case "elif":
+			cond, line = rest, p.segments[p.pos-1].line
+		case "else":
+			els, end, err := p.parseBody()
+			if err != nil {
+				return nil, err
+			}
+			if end != "endif" {
+				return nil, errorf(line, "expected endif, found %q", end)
+			}
+			n.els = els
+			return n, nil
+		case "endif":
+			return n, nil
+		default:
+			return nil, errorf(line, "unclosed if")
+		}
+	}
+}
+
+func (p *parser) parseFor(header string, line int) (*forNode, error) {
+	tokens, err := tokenize(header, line)
+	if err != nil {
+		return nil, err
+	}
+	e := &exprParser{tokens: tokens, line: line}
+	n := &forNode{line: line}
+	for {
+		t := e.next()
+		if t.kind != tokName {
+			return nil, errorf(line, "expected loop variable")
+		}
+		n.vars = append(n.vars, t.text)
+		if !e.accept(tokOp, ",") {
+			break
+		}
+	}
+	if !e.accept(tokName, "in") {
+		return nil, errorf(line, "expected 'in' in for loop")
+	}
+	if n.iter, err = e.parseOr(); err != nil {
+		return nil, err
+	}
+	if e.accept(tokName, "if") {
+		if n.cond, err = e.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+	if err = e.expectEnd(); err != nil {
+		return nil, err
+	}
+
+	body, end, err := p.parseBody()
+	if err != nil {
+		return nil, err
+	}
+	n.body = body
+	if end == "else" {
+		if n.els, end, err = p.parseBody(); err != nil {
+			return nil, err
+		}
+	}
+	if end != "endfor" {
+		return nil, errorf(line, "unclosed for")
+	}
+	return n, nil
+}
+
+func parseSet(stmt string, line int) (*setNode, error) {
+	target, value, ok := strings.Cut(stmt, "=")
+	if !ok {
+		return nil, errorf(line, "expected '=' in set")
+	}
+	n := &setNode{line: line}
+	for _, name := range strings.Split(target, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, errorf(line, "expected variable name in set")
+		}
+		n.names = append(n.names, name)
+	}
+	var err error
+	n.value, err = parseExpr(value, line)
+	return n, err
+}
+
+// exprParser parses an expression with Jinja's operator precedence
+type exprParser struct {
+	tokens []token
+	pos    int
+	line   int
+}
+
+func parseExpr(src string, line int) (expr, error) {
+	tokens, err := tokenize(src, line)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens, line: line}
+	e, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	return e, p.expectEnd()
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *exprParser) is(kind tokenKind, text string) bool {
+	t := p.peek()
+	return t.kind == kind && t.text == text
+}
+
+func (p *exprParser) accept(kind tokenKind, text string) bool {
+	if p.is(kind, text) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) expect(text string) error {
+	if !p.accept(tokOp, text) {
+		return errorf(p.line, "expected %q, found %q", text, p.peek().text)
+	}
+	return nil
+}
+
+func (p *exprParser) expectEnd() error {
+	if t := p.peek(); t.kind != tokEOF {
+		return errorf(p.line, "unexpected %q", t.text)
+	}
+	return nil
+}
+
+// parseExpression parses a conditional expression: a if cond else b
+func (p *exprParser) parseExpression() (expr, error) {
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept(tokName, "if") {
+		return e, nil
+	}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	var els expr = &literal{value: undefined{name: "else"}}
+	if p.accept(tokName, "else") {
+		if els, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+	}
+	return &condExpr{cond: cond, then: e, els: els}, nil
+}
+
+func (p *exprParser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.accept(tokName, "or") {
+		var right expr
+		right, err = p.parseAnd()
+		left = &binaryExpr{op: "or", left: left, right: right, line: p.line}
+	}
+	return left, err
+}
+
+func (p *exprParser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	for err == nil && p.accept(tokName, "and") {
+		var right expr
+		right, err = p.parseNot()
+		left = &binaryExpr{op: "and", left: left, right: right, line: p.line}
+	}
+	return left, err
+}
+
+func (p *exprParser) parseNot() (expr, error) {
+	if p.accept(tokName, "not") {
+		operand, err := p.parseNot()
+		return &unaryExpr{op: "not", operand: operand, line: p.line}, err
+	}
+	return p.parseCompare()
+}
+
+var comparisons = map[string]bool{"==": true, "!=": true, "<": true, ">": true, "<=": true, ">=": true}
+
+func (p *exprParser) parseCompare() (expr, error) {
+	left, err := p.parseAdd()
+	for err == nil {
+		var op string
+		switch t := p.peek(); {
+		case t.kind == tokOp && comparisons[t.text]:
+			op = t.text
+			p.pos++
+		case p.is(tokName, "in"):
+			op = "in"
+			p.pos++
+		case p.is(tokName, "not") && p.tokens[p.pos+1].kind == tokName && p.tokens[p.pos+1].text == "in":
+			op = "not in"
+			p.pos += 2
+		default:
+			return left, nil
+		}
+		var right expr
+		right, err = p.parseAdd()
+		left = &binaryExpr{op: op, left: left, right: right, line: p.line}
+	}
+	return left, err
+}
+
+func (p *exprParser) parseAdd() (expr, error) {
+	left, err := p.parseConcat()
+	for err == nil && (p.is(tokOp, "+") || p.is(tokOp, "-")) {
+		op := p.next().text
+		var right expr
+		right, err = p.parseConcat()
+		left = &binaryExpr{op: op, left: left, right: right, line: p.line}
+	}
+	return left, err
+}
+
+func (p *exprParser) parseConcat() (expr, error) {
+	left, err := p.parseMul()
+	for err == nil && p.accept(tokOp, "~") {
+		var right expr
+		right, err = p.parseMul()
+		left = &binaryExpr{op: "~", left: left, right: right, line: p.line}
+	}
+	return left, err
+}
+
+func (p *exprParser) parseMul() (expr, error) {
+	left, err := p.parsePow()
+	for err == nil && (p.is(tokOp, "*") || p.is(tokOp, "/") || p.is(tokOp, "//") || p.is(tokOp, "%")) {
+		op := p.next().text
+		var right expr
+		right, err = p.parsePow()
+		left = &binaryExpr{op: op, left: left, right: right, line: p.line}
+	}
+	return left, err
+}
+
+func (p *exprParser) parsePow() (expr, error) {
+	left, err := p.parseUnary()
+	for err == nil && p.accept(tokOp, "**") {
+		var right expr
+		right, err = p.parseUnary()
+		left = &binaryExpr{op: "**", left: left, right: right, line: p.line}
+	}
+	return left, err
+}
+
+func (p *exprParser) parseUnary() (expr, error) {
+	if p.is(tokOp, "-") || p.is(tokOp, "+") {
+		op := p.next().text
+		operand, err := p.parseUnary()
+		return &unaryExpr{op: op, operand: operand, line: p.line}, err
+	}
+	e, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if e, err = p.parsePostfix(e); err != nil {
+		return nil, err
+	}
+	return p.parseFilters(e)
+}
+
+func (p *exprParser) parsePrimary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		s := t.text
+		// Adjacent strings are concatenated
+		for p.peek().kind == tokString {
+			s += p.next().text
+		}
+		return &literal{value: s}, nil
+	case tokInt:
+		n, err := strconv.Atoi(t.text)
+		if err != nil {
+			return nil, errorf(p.line, "invalid number %q", t.text)
+		}
+		return &literal{value: n}, nil
+	case tokFloat:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, errorf(p.line, "invalid number %q", t.text)
+		}
+		return &literal{value: f}, nil
+	case tokName:
+		switch t.text {
+		case "true", "True":
+			return &literal{value: true}, nil
+		case "false", "False":
+			return &literal{value: false}, nil
+		case "none", "None":
+			return &literal{value: nil}, nil
+		}
+		return &name{name: t.text, line: p.line}, nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			if p.accept(tokOp, ")") {
+				return &listExpr{}, nil
+			}
+			e, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if p.is(tokOp, ",") {
+				items := []expr{e}
+				for p.accept(tokOp, ",") && !p.is(tokOp, ")") {
+					if e, err = p.parseExpression(); err != nil {
+						return nil, err
+					}
+					items = append(items, e)
+				}
+				return &listExpr{items: items}, p.expect(")")
+			}
+			return e, p.expect(")")
+		case "[":
+			l := &listExpr{}
+			for !p.accept(tokOp, "]") {
+				e, err := p.parseExpression()
+				if err != nil {
+					return nil, err
+				}
+				l.items = append(l.items, e)
+				if !p.accept(tokOp, ",") {
+					return l, p.expect("]")
+				}
+			}
+			return l, nil
+		case "{":
+			d := &dictExpr{}
+			for !p.accept(tokOp, "}") {
+				k, err := p.parseExpression()
+				if err != nil {
+					return nil, err
+				}
+				if err = p.expect(":"); err != nil {
+					return nil, err
+				}
+				v, err := p.parseExpression()
+				if err != nil {
+					return nil, err
+				}
+				d.keys = append(d.keys, k)
+				d.values = append(d.values, v)
+				if !p.accept(tokOp, ",") {
+					return d, p.expect("}")
+				}
+			}
+			return d, nil
+		}
+	}
+	if t.kind == tokEOF {
+		return nil, errorf(p.line, "unexpected end of expression")
+	}
+	return nil, errorf(p.line, "unexpected %q", t.text)
+}
+
+// parsePostfix parses attribute access, subscripts, slices and calls
+func (p *exprParser) parsePostfix(e expr) (expr, error) {
+	for {
+		switch {
+		case p.accept(tokOp, "."):
+			t := p.next()
+			if t.kind != tokName && t.kind != tokInt {
+				return nil, errorf(p.line, "expected attribute name after '.'")
+			}
+			e = &attrExpr{obj: e, attr: t.text, line: p.line}
+		case p.accept(tokOp, "["):
+			var parts [3]expr
+			isSlice := false
+			for i := 0; i < 3; i++ {
+				if !p.is(tokOp, ":") && !p.is(tokOp, "]") {
+					part, err := p.parseExpression()
+					if err != nil {
+						return nil, err
+					}
+					parts[i] = part
+				}
+				if !p.accept(tokOp, ":") {
+					break
+				}
+				isSlice = true
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			if isSlice {
+				e = &sliceExpr{obj: e, start: parts[0], stop: parts[1], step: parts[2], line: p.line}
+			} else {
+				e = &indexExpr{obj: e, index: parts[0], line: p.line}
+			}
+		case p.is(tokOp, "("):
+			args, kwargs, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			e = &callExpr{fn: e, args: args, kwargs: kwargs, line: p.line}
+		default:
+			return e, nil
+		}
+	}
+}
+
+// parseFilters parses "| filter(args)" and "is [not] test(args)" suffixes
+func (p *exprParser) parseFilters(e expr) (expr, error) {
+	for {
+		switch {
+		case p.accept(tokOp, "|"):
+			t := p.next()
+			if t.kind != tokName {
+				return nil, errorf(p.line, "expected filter name after '|'")
+			}
+			f := &filterExpr{value: e, name: t.text, line: p.line}
+			if p.is(tokOp, "(") {
+				var err error
+				if f.args, f.kwargs, err = p.parseArgs(); err != nil {
+					return nil, err
+				}
+			}
+			e = f
+		case p.accept(tokName, "is"):
+			test := &testExpr{value: e, negate: p.accept(tokName, "not"), line: p.line}
+			t := p.next()
+			if t.kind != tokName {
+				return nil, errorf(p.line, "expected test name after 'is'")
+			}
+			test.name = t.text
+			if p.is(tokOp, "(") {
+				var err error
+				if test.args, _, err = p.parseArgs(); err != nil {
+					return nil, err
+				}
+			} else if k := p.peek().kind; k == tokString || k == tokInt || k == tokFloat {
+				arg, err := p.parsePrimary()
+				if err != nil {
+					return nil, err
+				}
+				test.args = []expr{arg}
+			}
+			e = test
+		default:
+			return e, nil
+		}
+	}
+}
+
+func (p *exprParser) parseArgs() ([]expr, map[string]expr, error) {
+	if err := p.expect("("); err != nil {
+		return nil, nil, err
+	}
+	var args []expr
+	var kwargs map[string]expr
+	for !p.accept(tokOp, ")") {
+		if p.peek().kind == tokName && p.tokens[p.pos+1].kind == tokOp && p.tokens[p.pos+1].text == "=" {
+			key := p.next().text
+			p.next()
+			value, err := p.parseExpression()
+			if err != nil {
+				return nil, nil, err
+			}
+			if kwargs == nil {
+				kwargs = make(map[string]expr)
+			}
+			kwargs[key] = value
+		} else {
+			arg, err := p.parseExpression()
+			if err != nil {
+				return nil, nil, err
+			}
+			args = append(args, arg)
+		}
+		if !p.accept(tokOp, ",") {
+			return args, kwargs, p.expect(")")
+		}
+	}
+	return args, kwargs, nil
+}
@@ -0,0 +1,119 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package infinity
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/pexip/go-infinity-sdk/v41/internal/redact"
+)
+
+// maxLoggedBody is the number of bytes of a request or response body that is logged
+const maxLoggedBody = 4096
+
+// logAttempt logs an attempt before it is sent, with its redacted headers and body if body logging is enabled
+func (c *Client) logAttempt(ctx context.Context, req *Request, attempt int, header http.Header, body []byte) {
+	if c.logger == nil || !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", req.Endpoint),
+		slog.Int("attempt", attempt),
+	}
+	if c.logBodies {
+		attrs = append(attrs, slog.Any("headers", redact.Header(header)))
+		if body != nil {
+			attrs = append(attrs, slog.String("body", loggedBody(body, header.Get("Content-Type"))))
+		}
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "sending request", attrs...)
+}
+
+// logResponse logs the response to an attempt, with its redacted body if body logging is enabled
+func (c *Client) logResponse(ctx context.Context, req *Request, attempt int, resp *Response, err error, duration time.Duration) {
+	if c.logger == nil || !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", req.Endpoint),
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", duration),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode), slog.String("served_by", resp.ServedBy))
+		if c.logBodies && len(resp.Body) > 0 {
+			attrs = append(attrs, slog.String("body", loggedBody(resp.Body, resp.Headers.Get("Content-Type"))))
+		}
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "received response", attrs...)
+}
+
+// logRetry logs the decision to retry a failed attempt
+func (c *Client) logRetry(ctx context.Context, req *Request, attempt int, resp *Response, err error, backoff time.Duration) {
+	if c.logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", req.Endpoint),
+		slog.Int("attempt", attempt),
+		slog.Duration("backoff", backoff),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelWarn, "retrying request", attrs...)
+}
+
+// logOutcome logs the final result of a request: successes at debug level, client errors
+// at warn level and server or transport errors at error level
+func (c *Client) logOutcome(ctx context.Context, req *Request, attempts int, resp *Response, err error, duration time.Duration) {
+	if c.logger == nil {
+		return
+	}
+	level := slog.LevelDebug
+	switch {
+	case err != nil && resp.statusCode() >= 400 && resp.statusCode() < 500:
+		level = slog.LevelWarn
+	case err != nil:
+		level = slog.LevelError
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", req.Endpoint),
+		slog.Int("attempts", attempts),
+		slog.Duration("duration", duration),
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		c.logger.LogAttrs(ctx, level, "request failed", attrs...)
+		return
+	}
+	c.logger.LogAttrs(ctx, level, "request completed", attrs...)
+}
+
+// loggedBody returns body with secrets redacted, truncated to maxLoggedBody bytes
+func loggedBody(body []byte, contentType string) string {
+	masked := redact.Body(body, contentType)
+	if len(masked) > maxLoggedBody {
+		return string(masked[:maxLoggedBody]) + "...(truncated)"
+	}
+	return string(masked)
+}
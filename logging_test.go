@@ -0,0 +1,109 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package infinity
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logRecords decodes the JSON log lines written to buf
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestClient_WithLogger(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client, err := New(
+		WithBaseURL(server.URL),
+		WithRetryConfig(&RetryConfig{MaxRetries: 1, BackoffMin: time.Millisecond, BackoffMax: time.Millisecond, Multiplier: 1}),
+		WithLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	)
+	require.NoError(t, err)
+
+	err = client.GetJSON(t.Context(), "configuration/v1/conference/", nil, nil)
+	require.Error(t, err)
+
+	var got []string
+	for _, record := range logRecords(t, &buf) {
+		got = append(got, record["level"].(string)+" "+record["msg"].(string))
+	}
+	assert.Equal(t, []string{
+		"DEBUG sending request",
+		"DEBUG received response",
+		"WARN retrying request",
+		"DEBUG sending request",
+		"DEBUG received response",
+		"WARN request failed",
+	}, got)
+	assert.NotContains(t, buf.String(), `"body"`)
+}
+
+func TestClient_WithBodyLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"ldap","ldap_bind_password":"from-server"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client, err := New(
+		WithBaseURL(server.URL),
+		WithBasicAuth("admin", "s3cret"),
+		WithLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		WithBodyLogging(),
+	)
+	require.NoError(t, err)
+
+	body := map[string]string{"name": "ldap", "ldap_bind_password": "hunter2", "private_key": "pem"}
+	var result map[string]string
+	err = client.PutJSON(t.Context(), "configuration/v1/ldap_sync_source/1/", body, &result)
+	require.NoError(t, err)
+
+	logs := buf.String()
+	assert.Contains(t, logs, `ldap_bind_password\":\"[REDACTED]`)
+	assert.Contains(t, logs, `"Authorization":["[REDACTED]"]`)
+	for _, secret := range []string{"hunter2", "pem", "from-server", "YWRtaW46czNjcmV0"} {
+		assert.NotContains(t, logs, secret)
+	}
+
+	records := logRecords(t, &buf)
+	assert.Equal(t, "request completed", records[len(records)-1]["msg"])
+	assert.Equal(t, "DEBUG", records[len(records)-1]["level"])
+}
+
+func TestWithLogger_Nil(t *testing.T) {
+	_, err := New(WithLogger(nil))
+	require.Error(t, err)
+}
@@ -0,0 +1,152 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package options
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidFilter is returned when a filter uses a field, operator or ordering
+// that the resource being listed does not support
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// Operator is a Tastypie field lookup used to filter list results
+type Operator string
+
+const (
+	Exact       Operator = "exact"
+	IExact      Operator = "iexact"
+	Contains    Operator = "contains"
+	IContains   Operator = "icontains"
+	StartsWith  Operator = "startswith"
+	IStartsWith Operator = "istartswith"
+	EndsWith    Operator = "endswith"
+	IEndsWith   Operator = "iendswith"
+	In          Operator = "in"
+	GT          Operator = "gt"
+	GTE         Operator = "gte"
+	LT          Operator = "lt"
+	LTE         Operator = "lte"
+	IsNull      Operator = "isnull"
+)
+
+// Common operator sets for describing filterable fields
+var (
+	TextOperators    = []Operator{Exact, IExact, Contains, IContains, StartsWith, IStartsWith, EndsWith, IEndsWith, In, IsNull}
+	NumericOperators = []Operator{Exact, In, GT, GTE, LT, LTE, IsNull}
+	TimeOperators    = []Operator{Exact, GT, GTE, LT, LTE, IsNull}
+	BoolOperators    = []Operator{Exact}
+)
+
+// Condition is a single field lookup within a Filter
+type Condition struct {
+	Field    string
+	Operator Operator
+	Value    interface{}
+}
+
+// Filter is a set of conditions and an ordering applied to a list operation.
+// Conditions are combined with AND by the API.
+type Filter struct {
+	Conditions []Condition
+	Ordering   []string
+}
+
+// NewFilter creates an empty filter
+func NewFilter() *Filter {
+	return &Filter{}
+}
+
+// Where adds a condition on field using the given operator
+func (f *Filter) Where(field string, op Operator, value interface{}) *Filter {
+	f.Conditions = append(f.Conditions, Condition{Field: field, Operator: op, Value: value})
+	return f
+}
+
+// OrderBy sets the fields to order results by. Prefix a field with "-" for
+// descending order, e.g. "-creation_time".
+func (f *Filter) OrderBy(fields ...string) *Filter {
+	f.Ordering = append(f.Ordering, fields...)
+	return f
+}
+
+// FilterSchema describes the fields of a resource that may be filtered and ordered on
+type FilterSchema struct {
+	Fields   map[string][]Operator
+	Ordering []string
+}
+
+// Validate checks the filter against schema. A nil filter or schema is always valid.
+func (f *Filter) Validate(schema *FilterSchema) error {
+	if f == nil || schema == nil {
+		return nil
+	}
+	for _, c := range f.Conditions {
+		ops, ok := schema.Fields[c.Field]
+		if !ok {
+			return fmt.Errorf("%w: field %q is not filterable", ErrInvalidFilter, c.Field)
+		}
+		if !slices.Contains(ops, c.Operator) {
+			return fmt.Errorf("%w: operator %q is not supported on field %q", ErrInvalidFilter, c.Operator, c.Field)
+		}
+		if c.Operator == IsNull {
+			if _, ok := c.Value.(bool); !ok {
+				return fmt.Errorf("%w: operator %q on field %q requires a bool value", ErrInvalidFilter, c.Operator, c.Field)
+			}
+		}
+	}
+	for _, field := range f.Ordering {
+		if !slices.Contains(schema.Ordering, strings.TrimPrefix(field, "-")) {
+			return fmt.Errorf("%w: field %q cannot be used for ordering", ErrInvalidFilter, field)
+		}
+	}
+	return nil
+}
+
+// ToURLValues converts the filter to url.Values for query parameters
+func (f *Filter) ToURLValues() url.Values {
+	params := url.Values{}
+	if f == nil {
+		return params
+	}
+	for _, c := range f.Conditions {
+		params.Add(c.Field+"__"+string(c.Operator), formatFilterValue(c.Value))
+	}
+	for _, field := range f.Ordering {
+		params.Add("order_by", field)
+	}
+	return params
+}
+
+func formatFilterValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ",")
+	case []int:
+		values := make([]string, len(v))
+		for i, n := range v {
+			values[i] = strconv.Itoa(n)
+		}
+		return strings.Join(values, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,146 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package options
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_ToURLValues(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   *Filter
+		expected url.Values
+	}{
+		{
+			name:     "nil filter",
+			filter:   nil,
+			expected: url.Values{},
+		},
+		{
+			name: "conditions and ordering",
+			filter: NewFilter().
+				Where("tag", Exact, "x").
+				Where("creation_time", GTE, created).
+				Where("service_type", In, []string{"conference", "lecture"}).
+				Where("id", In, []int{1, 2}).
+				Where("description", IsNull, true).
+				OrderBy("-creation_time", "name"),
+			expected: url.Values{
+				"tag__exact":          []string{"x"},
+				"creation_time__gte":  []string{"2025-01-02T03:04:05Z"},
+				"service_type__in":    []string{"conference,lecture"},
+				"id__in":              []string{"1,2"},
+				"description__isnull": []string{"true"},
+				"order_by":            []string{"-creation_time", "name"},
+			},
+		},
+		{
+			name:   "repeated field",
+			filter: NewFilter().Where("priority", GT, 10).Where("priority", LT, 20),
+			expected: url.Values{
+				"priority__gt": []string{"10"},
+				"priority__lt": []string{"20"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.filter.ToURLValues())
+		})
+	}
+}
+
+func TestFilter_Validate(t *testing.T) {
+	schema := &FilterSchema{
+		Fields: map[string][]Operator{
+			"name":          TextOperators,
+			"creation_time": TimeOperators,
+		},
+		Ordering: []string{"name", "creation_time"},
+	}
+
+	tests := []struct {
+		name    string
+		filter  *Filter
+		schema  *FilterSchema
+		wantErr string
+	}{
+		{
+			name:   "valid filter",
+			filter: NewFilter().Where("name", StartsWith, "sales").OrderBy("-creation_time"),
+			schema: schema,
+		},
+		{
+			name:   "nil filter",
+			filter: nil,
+			schema: schema,
+		},
+		{
+			name:   "nil schema",
+			filter: NewFilter().Where("anything", Exact, "x"),
+			schema: nil,
+		},
+		{
+			name:    "unknown field",
+			filter:  NewFilter().Where("tag", Exact, "x"),
+			schema:  schema,
+			wantErr: `invalid filter: field "tag" is not filterable`,
+		},
+		{
+			name:    "unsupported operator",
+			filter:  NewFilter().Where("creation_time", Contains, "2025"),
+			schema:  schema,
+			wantErr: `invalid filter: operator "contains" is not supported on field "creation_time"`,
+		},
+		{
+			name:    "isnull requires bool",
+			filter:  NewFilter().Where("name", IsNull, "yes"),
+			schema:  schema,
+			wantErr: `invalid filter: operator "isnull" on field "name" requires a bool value`,
+		},
+		{
+			name:    "unknown ordering",
+			filter:  NewFilter().OrderBy("-tag"),
+			schema:  schema,
+			wantErr: `invalid filter: field "-tag" cannot be used for ordering`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate(tt.schema)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidFilter)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBaseListOptions_ToURLValues_WithFilter(t *testing.T) {
+	opts := BaseListOptions{
+		Limit:  10,
+		Filter: NewFilter().Where("tag", Exact, "x").OrderBy("name"),
+	}
+
+	expected := url.Values{
+		"limit":      []string{"10"},
+		"tag__exact": []string{"x"},
+		"order_by":   []string{"name"},
+	}
+	assert.Equal(t, expected, opts.ToURLValues())
+}
@@ -15,15 +15,21 @@ import (
 	"time"
 )
 
-// BaseListOptions provides common pagination parameters for all list operations
+// BaseListOptions provides common pagination and filtering parameters for all list operations
 type BaseListOptions struct {
 	Limit  int
 	Offset int
+	Filter *Filter
+}
+
+// base returns the options shared by every list options type
+func (opts *BaseListOptions) base() *BaseListOptions {
+	return opts
 }
 
 // ToURLValues converts BaseListOptions to url.Values for query parameters
 func (opts *BaseListOptions) ToURLValues() url.Values {
-	params := url.Values{}
+	params := opts.Filter.ToURLValues()
 	if opts.Limit > 0 {
 		params.Set("limit", strconv.Itoa(opts.Limit))
 	}
@@ -33,7 +39,8 @@ func (opts *BaseListOptions) ToURLValues() url.Values {
 	return params
 }
 
-// SearchableListOptions extends BaseListOptions with search functionality
+// SearchableListOptions extends BaseListOptions with a name search. Use Filter for
+// lookups on other fields or with other operators.
 type SearchableListOptions struct {
 	BaseListOptions
 	Search string
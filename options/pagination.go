@@ -0,0 +1,78 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package options
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// Meta represents the pagination metadata returned with every list response
+type Meta struct {
+	Limit      int    `json:"limit"`
+	Next       string `json:"next"`
+	Offset     int    `json:"offset"`
+	Previous   string `json:"previous"`
+	TotalCount int    `json:"total_count"`
+}
+
+// PageFunc fetches the page of results starting at offset and returns the objects
+// on that page along with its pagination metadata.
+type PageFunc[T any] func(ctx context.Context, offset int) ([]T, Meta, error)
+
+// Paginate returns an iterator over every object of a list operation, starting at
+// offset. Iteration follows meta.next and stops when there are no more pages, when
+// the context is cancelled, or when a page fails to load. Errors are yielded once
+// together with the zero value of T, after any objects from previous pages have
+// already been yielded.
+func Paginate[T any](ctx context.Context, offset int, fetch PageFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			objects, meta, err := fetch(ctx, offset)
+			if err != nil {
+				yield(zero, fmt.Errorf("failed to fetch page at offset %d: %w", offset, err))
+				return
+			}
+
+			for _, obj := range objects {
+				if !yield(obj, nil) {
+					return
+				}
+			}
+
+			if meta.Next == "" || len(objects) == 0 {
+				return
+			}
+			offset = meta.Offset + len(objects)
+		}
+	}
+}
+
+// PaginateList returns an iterator over every object of a list operation, starting at the
+// offset in opts and requesting each page with a copy of opts so that the caller's options
+// are left untouched
+func PaginateList[T any, O any, P interface {
+	*O
+	base() *BaseListOptions
+}](ctx context.Context, opts P, list func(ctx context.Context, opts P) ([]T, Meta, error)) iter.Seq2[T, error] {
+	var base O
+	if opts != nil {
+		base = *opts
+	}
+	return Paginate(ctx, P(&base).base().Offset, func(ctx context.Context, offset int) ([]T, Meta, error) {
+		pageOpts := base
+		P(&pageOpts).base().Offset = offset
+		return list(ctx, &pageOpts)
+	})
+}
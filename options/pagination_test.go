@@ -0,0 +1,160 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package options
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate(t *testing.T) {
+	pages := map[int][]int{
+		0: {1, 2},
+		2: {3, 4},
+		4: {5},
+	}
+	var offsets []int
+	fetch := func(ctx context.Context, offset int) ([]int, Meta, error) {
+		offsets = append(offsets, offset)
+		meta := Meta{Limit: 2, Offset: offset, TotalCount: 5}
+		if offset < 4 {
+			meta.Next = "/next"
+		}
+		return pages[offset], meta, nil
+	}
+
+	var got []int
+	for v, err := range Paginate(t.Context(), 0, fetch) {
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, got)
+	assert.Equal(t, []int{0, 2, 4}, offsets)
+}
+
+func TestPaginate_StartOffset(t *testing.T) {
+	var offsets []int
+	fetch := func(ctx context.Context, offset int) ([]int, Meta, error) {
+		offsets = append(offsets, offset)
+		return []int{offset}, Meta{Offset: offset}, nil
+	}
+
+	var got []int
+	for v, err := range Paginate(t.Context(), 7, fetch) {
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+
+	assert.Equal(t, []int{7}, got)
+	assert.Equal(t, []int{7}, offsets)
+}
+
+func TestPaginate_Error(t *testing.T) {
+	fetchErr := errors.New("server error")
+	fetch := func(ctx context.Context, offset int) ([]int, Meta, error) {
+		if offset > 0 {
+			return nil, Meta{}, fetchErr
+		}
+		return []int{1, 2}, Meta{Offset: offset, Next: "/next"}, nil
+	}
+
+	var got []int
+	var gotErr error
+	for v, err := range Paginate(t.Context(), 0, fetch) {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		got = append(got, v)
+	}
+
+	assert.Equal(t, []int{1, 2}, got)
+	assert.ErrorIs(t, gotErr, fetchErr)
+	assert.Contains(t, gotErr.Error(), "offset 2")
+}
+
+func TestPaginate_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	calls := 0
+	fetch := func(ctx context.Context, offset int) ([]int, Meta, error) {
+		calls++
+		cancel()
+		return []int{offset}, Meta{Offset: offset, Next: "/next"}, nil
+	}
+
+	var got []int
+	var gotErr error
+	for v, err := range Paginate(ctx, 0, fetch) {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		got = append(got, v)
+	}
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []int{0}, got)
+	assert.ErrorIs(t, gotErr, context.Canceled)
+}
+
+func TestPaginate_StopEarly(t *testing.T) {
+	calls := 0
+	fetch := func(ctx context.Context, offset int) ([]int, Meta, error) {
+		calls++
+		return []int{1, 2, 3}, Meta{Offset: offset, Next: "/next"}, nil
+	}
+
+	for v, err := range Paginate(t.Context(), 0, fetch) {
+		require.NoError(t, err)
+		if v == 2 {
+			break
+		}
+	}
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestPaginateList(t *testing.T) {
+	opts := &TimeFilteredListOptions{SearchableListOptions: SearchableListOptions{
+		BaseListOptions: BaseListOptions{Limit: 2, Offset: 1},
+		Search:          "sales",
+	}}
+	var offsets []int
+	list := func(ctx context.Context, opts *TimeFilteredListOptions) ([]int, Meta, error) {
+		assert.Equal(t, "sales", opts.Search)
+		offsets = append(offsets, opts.Offset)
+		meta := Meta{Offset: opts.Offset}
+		if opts.Offset < 3 {
+			meta.Next = "/next"
+		}
+		return []int{opts.Offset, opts.Offset + 1}, meta, nil
+	}
+
+	var got []int
+	for v, err := range PaginateList(t.Context(), opts, list) {
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 4}, got)
+	assert.Equal(t, []int{1, 3}, offsets)
+	assert.Equal(t, 1, opts.Offset, "the caller's options are left untouched")
+
+	got = nil
+	for v, err := range PaginateList(t.Context(), (*BaseListOptions)(nil), func(ctx context.Context, opts *BaseListOptions) ([]int, Meta, error) {
+		return []int{opts.Offset}, Meta{}, nil
+	}) {
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{0}, got)
+}
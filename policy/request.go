@@ -0,0 +1,106 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package policy
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Request holds the call information Infinity sends with every policy request
+type Request struct {
+	Protocol          string // e.g. sip, h323, webrtc, mssip, rtmp, teams
+	NodeIP            string // address of the Conferencing Node making the request
+	LocalAlias        string // alias that was dialed
+	RemoteAlias       string // alias of the caller
+	RemoteDisplayName string
+	RemoteAddress     string
+	RemotePort        int
+	CallDirection     string // dial_in, dial_out or non_dial
+	Bandwidth         int
+	Vendor            string
+	Location          string // system location of the Conferencing Node
+	Registered        bool   // whether the caller is registered to Infinity
+	CallTag           string
+	VersionID         string
+	PseudoVersionID   string
+	TriggerReason     string
+
+	// Query holds every parameter of the request, including those without a field above
+	Query url.Values
+}
+
+// ServiceRequest asks for the configuration of the service a call is placed to
+type ServiceRequest struct {
+	Request
+}
+
+// ParticipantRequest asks for the properties of a participant joining a service
+type ParticipantRequest struct {
+	Request
+	ServiceName       string
+	ServiceTag        string
+	UniqueServiceName string
+	IDPUUID           string
+}
+
+// MediaLocationRequest asks for the system locations that should handle a participant's media
+type MediaLocationRequest struct {
+	Request
+	ServiceName       string
+	ServiceTag        string
+	UniqueServiceName string
+}
+
+// AvatarRequest asks for the image shown for an alias
+type AvatarRequest struct {
+	Request
+	Alias  string
+	Width  int
+	Height int
+}
+
+// RegistrationRequest asks whether a device may register an alias
+type RegistrationRequest struct {
+	Request
+	Alias string
+}
+
+// DirectoryRequest asks for directory entries that match a search
+type DirectoryRequest struct {
+	Request
+	Search string
+	Limit  int
+}
+
+func parseRequest(q url.Values) Request {
+	return Request{
+		Protocol:          q.Get("protocol"),
+		NodeIP:            q.Get("node_ip"),
+		LocalAlias:        q.Get("local_alias"),
+		RemoteAlias:       q.Get("remote_alias"),
+		RemoteDisplayName: q.Get("remote_display_name"),
+		RemoteAddress:     q.Get("remote_address"),
+		RemotePort:        atoi(q.Get("remote_port")),
+		CallDirection:     q.Get("call_direction"),
+		Bandwidth:         atoi(q.Get("bandwidth")),
+		Vendor:            q.Get("vendor"),
+		Location:          q.Get("location"),
+		Registered:        q.Get("registered") == "True" || q.Get("registered") == "true",
+		CallTag:           q.Get("call_tag"),
+		VersionID:         q.Get("version_id"),
+		PseudoVersionID:   q.Get("pseudo_version_id"),
+		TriggerReason:     q.Get("trigger"),
+		Query:             q,
+	}
+}
+
+// atoi returns 0 for missing or malformed numbers
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,87 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package policy
+
+// Response statuses and actions
+const (
+	StatusSuccess = "success"
+
+	ActionContinue = "continue"
+	ActionReject   = "reject"
+)
+
+// Response is the JSON answer to a policy request
+type Response struct {
+	Status string      `json:"status"`
+	Action string      `json:"action,omitempty"`
+	Result interface{} `json:"result,omitempty"`
+}
+
+// Continue lets Infinity carry on with its own configuration, as if there were no policy server
+func Continue() *Response {
+	return &Response{Status: StatusSuccess, Action: ActionContinue}
+}
+
+// Reject rejects the call, participant or registration
+func Reject() *Response {
+	return &Response{Status: StatusSuccess, Action: ActionReject}
+}
+
+// Result answers with result, which Infinity uses instead of its own configuration
+func Result(result interface{}) *Response {
+	return &Response{Status: StatusSuccess, Action: ActionContinue, Result: result}
+}
+
+// ServiceConfiguration is the result of a service lookup. Answer with a map for fields it does not cover.
+type ServiceConfiguration struct {
+	ServiceType       string `json:"service_type"` // conference, lecture, two_stage_dialing, gateway, ...
+	Name              string `json:"name"`
+	ServiceTag        string `json:"service_tag,omitempty"`
+	Description       string `json:"description,omitempty"`
+	PIN               string `json:"pin,omitempty"`
+	GuestPIN          string `json:"guest_pin,omitempty"`
+	AllowGuests       *bool  `json:"allow_guests,omitempty"`
+	GuestsCanPresent  *bool  `json:"guests_can_present,omitempty"`
+	View              string `json:"view,omitempty"`
+	LocalDisplayName  string `json:"local_display_name,omitempty"`
+	LocalAlias        string `json:"local_alias,omitempty"`
+	RemoteAlias       string `json:"remote_alias,omitempty"`
+	OutgoingProtocol  string `json:"outgoing_protocol,omitempty"`
+	CallType          string `json:"call_type,omitempty"`
+	MaxCallrateIn     int    `json:"max_callrate_in,omitempty"`
+	MaxCallrateOut    int    `json:"max_callrate_out,omitempty"`
+	ParticipantLimit  int    `json:"participant_limit,omitempty"`
+	IVRTheme          string `json:"ivr_theme_name,omitempty"`
+	Locked            *bool  `json:"locked,omitempty"`
+	EnableChat        string `json:"enable_chat,omitempty"`
+	CryptoMode        string `json:"crypto_mode,omitempty"`
+	EnableOverlayText *bool  `json:"enable_overlay_text,omitempty"`
+}
+
+// ParticipantProperties is the result of a participant policy decision
+type ParticipantProperties struct {
+	PreauthenticatedRole string `json:"preauthenticated_role,omitempty"` // chair or guest
+	RemoteDisplayName    string `json:"remote_display_name,omitempty"`
+	RemoteAlias          string `json:"remote_alias,omitempty"`
+	CallTag              string `json:"call_tag,omitempty"`
+	Bandwidth            int    `json:"bandwidth,omitempty"`
+	RejectReason         string `json:"reject_reason,omitempty"`
+}
+
+// MediaLocation is the result of a media location decision
+type MediaLocation struct {
+	Location                  string `json:"location"`
+	PrimaryOverflowLocation   string `json:"primary_overflow_location,omitempty"`
+	SecondaryOverflowLocation string `json:"secondary_overflow_location,omitempty"`
+}
+
+// DirectoryEntry is one result of a directory lookup
+type DirectoryEntry struct {
+	Username    string `json:"username,omitempty"`
+	Alias       string `json:"alias"`
+	Description string `json:"description,omitempty"`
+}
@@ -0,0 +1,275 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package policy implements the HTTP side of an external policy server, as configured with
+// config.PolicyServer. Server parses Infinity's policy requests into typed structs, calls the lookups and
+// policies it was given and encodes their answers in the documented JSON format.
+package policy
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ErrNotFound is returned by an AvatarLookup that has no image for an alias
+var ErrNotFound = errors.New("not found")
+
+// ServiceLookup answers service configuration requests
+type ServiceLookup interface {
+	LookupService(ctx context.Context, req *ServiceRequest) (*Response, error)
+}
+
+// ParticipantPolicy answers participant properties requests
+type ParticipantPolicy interface {
+	ParticipantProperties(ctx context.Context, req *ParticipantRequest) (*Response, error)
+}
+
+// MediaLocationPolicy answers media location requests
+type MediaLocationPolicy interface {
+	MediaLocation(ctx context.Context, req *MediaLocationRequest) (*Response, error)
+}
+
+// RegistrationPolicy answers registration alias requests
+type RegistrationPolicy interface {
+	Registration(ctx context.Context, req *RegistrationRequest) (*Response, error)
+}
+
+// DirectoryLookup answers directory information requests
+type DirectoryLookup interface {
+	Directory(ctx context.Context, req *DirectoryRequest) (*Response, error)
+}
+
+// AvatarLookup answers avatar requests with an image and its content type, or ErrNotFound
+type AvatarLookup interface {
+	Avatar(ctx context.Context, req *AvatarRequest) (image []byte, contentType string, err error)
+}
+
+// ServiceLookupFunc adapts a function to the ServiceLookup interface
+type ServiceLookupFunc func(ctx context.Context, req *ServiceRequest) (*Response, error)
+
+// LookupService calls f
+func (f ServiceLookupFunc) LookupService(ctx context.Context, req *ServiceRequest) (*Response, error) {
+	return f(ctx, req)
+}
+
+// ParticipantPolicyFunc adapts a function to the ParticipantPolicy interface
+type ParticipantPolicyFunc func(ctx context.Context, req *ParticipantRequest) (*Response, error)
+
+// ParticipantProperties calls f
+func (f ParticipantPolicyFunc) ParticipantProperties(ctx context.Context, req *ParticipantRequest) (*Response, error) {
+	return f(ctx, req)
+}
+
+// MediaLocationPolicyFunc adapts a function to the MediaLocationPolicy interface
+type MediaLocationPolicyFunc func(ctx context.Context, req *MediaLocationRequest) (*Response, error)
+
+// MediaLocation calls f
+func (f MediaLocationPolicyFunc) MediaLocation(ctx context.Context, req *MediaLocationRequest) (*Response, error) {
+	return f(ctx, req)
+}
+
+// Server is an http.Handler for the policy requests of Infinity. Requests for lookups that were not
+// configured are answered with 404, so Infinity falls back to its own configuration.
+type Server struct {
+	mux      *http.ServeMux
+	username string
+	password string
+
+	service       ServiceLookup
+	participant   ParticipantPolicy
+	mediaLocation MediaLocationPolicy
+	registration  RegistrationPolicy
+	directory     DirectoryLookup
+	avatar        AvatarLookup
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithBasicAuth requires the username and password configured on the policy server
+func WithBasicAuth(username, password string) Option {
+	return func(s *Server) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// WithServiceLookup answers service configuration requests with lookup
+func WithServiceLookup(lookup ServiceLookup) Option {
+	return func(s *Server) { s.service = lookup }
+}
+
+// WithParticipantPolicy answers participant properties requests with policy
+func WithParticipantPolicy(policy ParticipantPolicy) Option {
+	return func(s *Server) { s.participant = policy }
+}
+
+// WithMediaLocationPolicy answers media location requests with policy
+func WithMediaLocationPolicy(policy MediaLocationPolicy) Option {
+	return func(s *Server) { s.mediaLocation = policy }
+}
+
+// WithRegistrationPolicy answers registration alias requests with policy
+func WithRegistrationPolicy(policy RegistrationPolicy) Option {
+	return func(s *Server) { s.registration = policy }
+}
+
+// WithDirectoryLookup answers directory information requests with lookup
+func WithDirectoryLookup(lookup DirectoryLookup) Option {
+	return func(s *Server) { s.directory = lookup }
+}
+
+// WithAvatarLookup answers avatar requests with lookup
+func WithAvatarLookup(lookup AvatarLookup) Option {
+	return func(s *Server) { s.avatar = lookup }
+}
+
+// NewServer creates a new policy server
+func NewServer(opts ...Option) *Server {
+	s := &Server{mux: http.NewServeMux()}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.mux.HandleFunc("GET /policy/v1/service/configuration", s.handleService)
+	s.mux.HandleFunc("GET /policy/v1/participant/properties", s.handleParticipant)
+	s.mux.HandleFunc("GET /policy/v1/participant/location", s.handleMediaLocation)
+	s.mux.HandleFunc("GET /policy/v1/participant/avatar/{alias}", s.handleAvatar)
+	s.mux.HandleFunc("GET /policy/v1/registrations/{alias}", s.handleRegistration)
+	s.mux.HandleFunc("GET /policy/v1/registrations", s.handleDirectory)
+	return s
+}
+
+// ServeHTTP routes a policy request to its lookup or policy
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="policy"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.username == "" && s.password == "" {
+		return true
+	}
+	username, password, ok := r.BasicAuth()
+	return ok &&
+		subtle.ConstantTimeCompare([]byte(username), []byte(s.username)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(password), []byte(s.password)) == 1
+}
+
+func (s *Server) handleService(w http.ResponseWriter, r *http.Request) {
+	if s.service == nil {
+		http.NotFound(w, r)
+		return
+	}
+	req := &ServiceRequest{Request: parseRequest(r.URL.Query())}
+	writeResponse(w, r)(s.service.LookupService(r.Context(), req))
+}
+
+func (s *Server) handleParticipant(w http.ResponseWriter, r *http.Request) {
+	if s.participant == nil {
+		http.NotFound(w, r)
+		return
+	}
+	q := r.URL.Query()
+	req := &ParticipantRequest{
+		Request:           parseRequest(q),
+		ServiceName:       q.Get("service_name"),
+		ServiceTag:        q.Get("service_tag"),
+		UniqueServiceName: q.Get("unique_service_name"),
+		IDPUUID:           q.Get("idp_uuid"),
+	}
+	writeResponse(w, r)(s.participant.ParticipantProperties(r.Context(), req))
+}
+
+func (s *Server) handleMediaLocation(w http.ResponseWriter, r *http.Request) {
+	if s.mediaLocation == nil {
+		http.NotFound(w, r)
+		return
+	}
+	q := r.URL.Query()
+	req := &MediaLocationRequest{
+		Request:           parseRequest(q),
+		ServiceName:       q.Get("service_name"),
+		ServiceTag:        q.Get("service_tag"),
+		UniqueServiceName: q.Get("unique_service_name"),
+	}
+	writeResponse(w, r)(s.mediaLocation.MediaLocation(r.Context(), req))
+}
+
+func (s *Server) handleRegistration(w http.ResponseWriter, r *http.Request) {
+	if s.registration == nil {
+		http.NotFound(w, r)
+		return
+	}
+	req := &RegistrationRequest{Request: parseRequest(r.URL.Query()), Alias: r.PathValue("alias")}
+	writeResponse(w, r)(s.registration.Registration(r.Context(), req))
+}
+
+func (s *Server) handleDirectory(w http.ResponseWriter, r *http.Request) {
+	if s.directory == nil {
+		http.NotFound(w, r)
+		return
+	}
+	q := r.URL.Query()
+	req := &DirectoryRequest{Request: parseRequest(q), Search: q.Get("q"), Limit: atoi(q.Get("limit"))}
+	writeResponse(w, r)(s.directory.Directory(r.Context(), req))
+}
+
+func (s *Server) handleAvatar(w http.ResponseWriter, r *http.Request) {
+	if s.avatar == nil {
+		http.NotFound(w, r)
+		return
+	}
+	q := r.URL.Query()
+	req := &AvatarRequest{
+		Request: parseRequest(q),
+		Alias:   r.PathValue("alias"),
+		Width:   atoi(q.Get("width")),
+		Height:  atoi(q.Get("height")),
+	}
+	image, contentType, err := s.avatar.Avatar(r.Context(), req)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		http.NotFound(w, r)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		if contentType == "" {
+			contentType = http.DetectContentType(image)
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(image)
+	}
+}
+
+// writeResponse returns a function that encodes the answer of a lookup or policy. Errors are answered with
+// 500, so Infinity falls back to its own configuration.
+func writeResponse(w http.ResponseWriter, r *http.Request) func(*Response, error) {
+	return func(resp *Response, err error) {
+		switch {
+		case errors.Is(err, ErrNotFound):
+			http.NotFound(w, r)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		case resp == nil:
+			resp = Continue()
+		}
+		if resp.Status == "" {
+			resp.Status = StatusSuccess
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, handler http.Handler, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.SetBasicAuth("policy", "secret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+type avatars map[string][]byte
+
+func (a avatars) Avatar(ctx context.Context, req *AvatarRequest) ([]byte, string, error) {
+	image, ok := a[req.Alias]
+	if !ok {
+		return nil, "", ErrNotFound
+	}
+	return image, "image/jpeg", nil
+}
+
+func TestServer_ServiceLookup(t *testing.T) {
+	server := NewServer(
+		WithBasicAuth("policy", "secret"),
+		WithServiceLookup(ServiceLookupFunc(func(ctx context.Context, req *ServiceRequest) (*Response, error) {
+			assert.Equal(t, "sip", req.Protocol)
+			assert.Equal(t, "dial_in", req.CallDirection)
+			assert.Equal(t, 2048, req.Bandwidth)
+			assert.True(t, req.Registered)
+			assert.Equal(t, "x", req.Query.Get("custom"))
+			switch req.LocalAlias {
+			case "meet.sales@example.com":
+				return Result(&ServiceConfiguration{ServiceType: "conference", Name: "Sales", PIN: "1234"}), nil
+			case "meet.blocked@example.com":
+				return Reject(), nil
+			default:
+				return Continue(), nil
+			}
+		})),
+	)
+
+	rec := get(t, server, "/policy/v1/service/configuration?protocol=sip&call_direction=dial_in&bandwidth=2048&registered=True&custom=x&local_alias=meet.sales@example.com")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"status":"success","action":"continue","result":{"service_type":"conference","name":"Sales","pin":"1234"}}`, rec.Body.String())
+
+	rec = get(t, server, "/policy/v1/service/configuration?protocol=sip&call_direction=dial_in&bandwidth=2048&registered=True&custom=x&local_alias=meet.blocked@example.com")
+	assert.JSONEq(t, `{"status":"success","action":"reject"}`, rec.Body.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/policy/v1/service/configuration", nil)
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestServer_ParticipantAndMediaLocation(t *testing.T) {
+	server := NewServer(
+		WithParticipantPolicy(ParticipantPolicyFunc(func(ctx context.Context, req *ParticipantRequest) (*Response, error) {
+			assert.Equal(t, "Sales", req.ServiceName)
+			return Result(&ParticipantProperties{PreauthenticatedRole: "chair"}), nil
+		})),
+		WithMediaLocationPolicy(MediaLocationPolicyFunc(func(ctx context.Context, req *MediaLocationRequest) (*Response, error) {
+			return nil, errors.New("location database unavailable")
+		})),
+	)
+
+	rec := get(t, server, "/policy/v1/participant/properties?service_name=Sales")
+	assert.JSONEq(t, `{"status":"success","action":"continue","result":{"preauthenticated_role":"chair"}}`, rec.Body.String())
+
+	rec = get(t, server, "/policy/v1/participant/location?service_name=Sales")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	// Lookups that were not configured fall back to Infinity's own configuration
+	rec = get(t, server, "/policy/v1/registrations/alice@example.com")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServer_Avatar(t *testing.T) {
+	server := NewServer(WithAvatarLookup(avatars{"alice@example.com": []byte("jpeg")}))
+
+	rec := get(t, server, "/policy/v1/participant/avatar/alice@example.com?width=100&height=100")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/jpeg", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "jpeg", rec.Body.String())
+
+	rec = get(t, server, "/policy/v1/participant/avatar/bob@example.com")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,459 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package quality turns media stream history into call quality metrics. Each participant's streams
+// are reduced to packet loss, jitter and a MOS-style estimate; calls are flagged as poor against
+// configurable thresholds and aggregated per conference, node and location, and the remote networks
+// and vendors with the worst calls are ranked.
+package quality
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	infinity "github.com/pexip/go-infinity-sdk/v41"
+	"github.com/pexip/go-infinity-sdk/v41/history"
+)
+
+// Options sets the thresholds and groupings of an analysis. Zero fields take their defaults.
+type Options struct {
+	// MaxLoss is the packet loss percentage above which a call is poor, by default 2
+	MaxLoss float64
+	// MaxJitter is the jitter in milliseconds above which a call is poor, by default 30
+	MaxJitter float64
+	// MinMOS is the estimated MOS below which a call is poor, by default 3.5
+	MinMOS float64
+	// Percentile is the percentile reported for each metric, between 0 and 100, by default 95
+	Percentile float64
+	// Locations maps conferencing node names and addresses to system locations, see NodeLocations
+	Locations map[string]string
+	// IPv4Prefix and IPv6Prefix group remote addresses into networks, by default /24 and /48
+	IPv4Prefix int
+	IPv6Prefix int
+	// MinCalls is the number of calls a network or vendor needs to be ranked, by default 5
+	MinCalls int
+	// Top is the number of networks and vendors ranked, by default 10
+	Top int
+}
+
+func (o *Options) withDefaults() Options {
+	opts := Options{}
+	if o != nil {
+		opts = *o
+	}
+	if opts.MaxLoss == 0 {
+		opts.MaxLoss = 2
+	}
+	if opts.MaxJitter == 0 {
+		opts.MaxJitter = 30
+	}
+	if opts.MinMOS == 0 {
+		opts.MinMOS = 3.5
+	}
+	if opts.Percentile == 0 {
+		opts.Percentile = 95
+	}
+	if opts.IPv4Prefix == 0 {
+		opts.IPv4Prefix = 24
+	}
+	if opts.IPv6Prefix == 0 {
+		opts.IPv6Prefix = 48
+	}
+	if opts.MinCalls == 0 {
+		opts.MinCalls = 5
+	}
+	if opts.Top == 0 {
+		opts.Top = 10
+	}
+	return opts
+}
+
+// Call is the quality of a single participant's call
+type Call struct {
+	ParticipantID int       `json:"participant_id"`
+	ConferenceID  int       `json:"conference_id"`
+	Conference    string    `json:"conference"`
+	DisplayName   string    `json:"display_name"`
+	StartTime     time.Time `json:"start_time"`
+	RemoteAddress string    `json:"remote_address"`
+	Network       string    `json:"network"`
+	Vendor        string    `json:"vendor"`
+	Node          string    `json:"node"`
+	Location      string    `json:"location"`
+	Streams       int       `json:"streams"`
+	// RxLoss and TxLoss are the percentages of packets lost towards and from Infinity across all streams
+	RxLoss float64 `json:"rx_loss"`
+	TxLoss float64 `json:"tx_loss"`
+	// Loss is the worse of RxLoss and TxLoss
+	Loss float64 `json:"loss"`
+	// Jitter is the highest jitter of any stream, in milliseconds
+	Jitter float64 `json:"jitter"`
+	// MOS is the lowest MOS estimate of the call's audio streams, or of all its streams if it has no audio
+	MOS float64 `json:"mos"`
+	// Reasons lists the thresholds the call breached; the call is poor if there are any
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// Poor reports whether the call breached any threshold
+func (c *Call) Poor() bool {
+	return len(c.Reasons) > 0
+}
+
+// Distribution summarises a metric over calls. Worst is the maximum for loss and jitter and the minimum for MOS.
+type Distribution struct {
+	Mean       float64 `json:"mean"`
+	Median     float64 `json:"median"`
+	Percentile float64 `json:"percentile"`
+	Worst      float64 `json:"worst"`
+}
+
+// Group is the quality of the calls sharing a conference, node, location, network or vendor
+type Group struct {
+	Key       string  `json:"key"`
+	Name      string  `json:"name"`
+	Calls     int     `json:"calls"`
+	PoorCalls int     `json:"poor_calls"`
+	PoorRate  float64 `json:"poor_rate"`
+	Streams   int     `json:"streams"`
+	// Loss, Jitter and MOS are distributions of the calls' values. Percentile is taken from the
+	// good end, so that the given percentage of calls are at least that good.
+	Loss   Distribution `json:"loss"`
+	Jitter Distribution `json:"jitter"`
+	MOS    Distribution `json:"mos"`
+}
+
+// Report is the result of an analysis
+type Report struct {
+	// Calls are the calls with media streams, in the order they were added
+	Calls []Call `json:"calls"`
+	// Unmeasured is the number of calls without media streams, which are left out of the report
+	Unmeasured int `json:"unmeasured"`
+	// Percentile is the percentile reported in each Distribution
+	Percentile float64 `json:"percentile"`
+	Overall    Group   `json:"overall"`
+	// Conferences, Nodes and Locations are sorted by name
+	Conferences []Group `json:"conferences"`
+	Nodes       []Group `json:"nodes"`
+	Locations   []Group `json:"locations"`
+	// WorstNetworks and WorstVendors are ranked by poor call rate, then by mean MOS
+	WorstNetworks []Group `json:"worst_networks"`
+	WorstVendors  []Group `json:"worst_vendors"`
+}
+
+// PoorCalls returns the calls that breached a threshold
+func (r *Report) PoorCalls() []Call {
+	var poor []Call
+	for _, c := range r.Calls {
+		if c.Poor() {
+			poor = append(poor, c)
+		}
+	}
+	return poor
+}
+
+// Analyzer accumulates calls from CDRs. It implements history.CDRWriter, so it can be passed to
+// history.Service.Export directly or alongside a file writer.
+type Analyzer struct {
+	opts       Options
+	calls      []Call
+	unmeasured int
+}
+
+// NewAnalyzer creates an Analyzer with the given options, which may be nil
+func NewAnalyzer(opts *Options) *Analyzer {
+	return &Analyzer{opts: opts.withDefaults()}
+}
+
+// Add adds the call of a CDR
+func (a *Analyzer) Add(cdr *history.CDR) {
+	if len(cdr.Streams) == 0 {
+		a.unmeasured++
+		return
+	}
+	a.calls = append(a.calls, a.newCall(cdr))
+}
+
+// Write adds the call of a CDR
+func (a *Analyzer) Write(cdr *history.CDR) error {
+	a.Add(cdr)
+	return nil
+}
+
+// Flush does nothing
+func (a *Analyzer) Flush() error {
+	return nil
+}
+
+// Close does nothing
+func (a *Analyzer) Close() error {
+	return nil
+}
+
+// Report aggregates the calls added so far
+func (a *Analyzer) Report() *Report {
+	o := a.opts
+	r := &Report{
+		Calls:      slices.Clone(a.calls),
+		Unmeasured: a.unmeasured,
+		Percentile: o.Percentile,
+		Overall:    newGroup("", "", pointers(a.calls), o.Percentile),
+	}
+	r.Conferences = groupBy(a.calls, o.Percentile, func(c *Call) (string, string) {
+		return strconv.Itoa(c.ConferenceID), c.Conference
+	})
+	r.Nodes = groupBy(a.calls, o.Percentile, func(c *Call) (string, string) { return c.Node, c.Node })
+	r.Locations = groupBy(a.calls, o.Percentile, func(c *Call) (string, string) { return c.Location, c.Location })
+	networks := groupBy(a.calls, o.Percentile, func(c *Call) (string, string) { return c.Network, c.Network })
+	vendors := groupBy(a.calls, o.Percentile, func(c *Call) (string, string) { return c.Vendor, c.Vendor })
+	r.WorstNetworks = worst(networks, o.MinCalls, o.Top)
+	r.WorstVendors = worst(vendors, o.MinCalls, o.Top)
+	return r
+}
+
+// Analyze exports the CDRs of participants who started between from and to and analyzes their calls.
+// If opts has no Locations, they are read with NodeLocations.
+func Analyze(ctx context.Context, client *infinity.Client, from, to time.Time, opts *Options) (*Report, error) {
+	o := opts.withDefaults()
+	if o.Locations == nil {
+		locations, err := NodeLocations(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		o.Locations = locations
+	}
+	a := NewAnalyzer(&o)
+	if _, err := client.History().Export(ctx, a, &history.ExportOptions{From: from, To: to}); err != nil {
+		return nil, fmt.Errorf("failed to export call history: %w", err)
+	}
+	return a.Report(), nil
+}
+
+// NodeLocations maps the names, hostnames and addresses of conferencing nodes to the names of their system locations
+func NodeLocations(ctx context.Context, client *infinity.Client) (map[string]string, error) {
+	locations := make(map[string]string)
+	for l, err := range client.Config().AllSystemLocations(ctx, nil) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list system locations: %w", err)
+		}
+		locations[strings.TrimSuffix(l.ResourceURI, "/")] = l.Name
+	}
+	nodes := make(map[string]string)
+	for w, err := range client.Config().AllWorkerVMs(ctx, nil) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list worker VMs: %w", err)
+		}
+		location, ok := locations[strings.TrimSuffix(w.SystemLocation, "/")]
+		if !ok {
+			location = w.SystemLocation
+		}
+		for _, key := range []string{w.Name, w.Hostname, w.Address} {
+			if key != "" {
+				nodes[key] = location
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// newCall reduces a CDR's streams to the quality of the call
+func (a *Analyzer) newCall(cdr *history.CDR) Call {
+	o := a.opts
+	c := Call{
+		ParticipantID: cdr.ParticipantID,
+		ConferenceID:  cdr.ConferenceID,
+		Conference:    cdr.ConferenceName,
+		DisplayName:   cdr.DisplayName,
+		StartTime:     cdr.StartTime,
+		RemoteAddress: cdr.RemoteAddress,
+		Network:       network(cdr.RemoteAddress, o.IPv4Prefix, o.IPv6Prefix),
+		Vendor:        cdr.Vendor,
+		Node:          cdr.MediaNode,
+		Streams:       len(cdr.Streams),
+		MOS:           math.Inf(1),
+	}
+	if c.Node == "" {
+		c.Node = cdr.Streams[0].Node
+	}
+	c.Location = o.Locations[c.Node]
+
+	var rx, rxLost, tx, txLost int64
+	audio := slices.ContainsFunc(cdr.Streams, isAudio)
+	for _, s := range cdr.Streams {
+		rx += int64(s.RxPackets)
+		rxLost += int64(s.RxPacketsLost)
+		tx += int64(s.TxPackets)
+		txLost += int64(s.TxPacketsLost)
+		jitter := max(s.RxJitter, s.TxJitter)
+		c.Jitter = max(c.Jitter, jitter)
+		if !audio || isAudio(s) {
+			loss := max(rxLossPercent(int64(s.RxPackets), int64(s.RxPacketsLost)), txLossPercent(int64(s.TxPackets), int64(s.TxPacketsLost)))
+			c.MOS = min(c.MOS, EstimateMOS(loss, jitter))
+		}
+	}
+	c.RxLoss = rxLossPercent(rx, rxLost)
+	c.TxLoss = txLossPercent(tx, txLost)
+	c.Loss = max(c.RxLoss, c.TxLoss)
+
+	if c.Loss > o.MaxLoss {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("packet loss %.1f%% above %g%%", c.Loss, o.MaxLoss))
+	}
+	if c.Jitter > o.MaxJitter {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("jitter %.0fms above %gms", c.Jitter, o.MaxJitter))
+	}
+	if c.MOS < o.MinMOS {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("MOS %.2f below %g", c.MOS, o.MinMOS))
+	}
+	return c
+}
+
+func isAudio(s history.MediaStream) bool {
+	return strings.EqualFold(s.StreamType, "audio")
+}
+
+// rxLossPercent returns lost packets as a percentage of packets sent to Infinity. Received packets
+// exclude those lost, so the packets sent are the sum of both.
+func rxLossPercent(received, lost int64) float64 {
+	if received+lost <= 0 {
+		return 0
+	}
+	return 100 * float64(lost) / float64(received+lost)
+}
+
+// txLossPercent returns lost packets as a percentage of packets sent by Infinity, which include those lost
+func txLossPercent(sent, lost int64) float64 {
+	if sent <= 0 {
+		return 0
+	}
+	return min(100*float64(lost)/float64(sent), 100)
+}
+
+// EstimateMOS estimates the mean opinion score, between 1 and 4.5, of a stream with the given packet
+// loss percentage and jitter in milliseconds. It uses a simplified ITU-T G.107 E-model that treats
+// jitter as added delay; history records no round-trip time, so network delay is not accounted for.
+func EstimateMOS(loss, jitter float64) float64 {
+	delay := 2*jitter + 10
+	r := 93.2 - delay/40
+	if delay >= 160 {
+		r = 93.2 - (delay-120)/10
+	}
+	r -= 2.5 * loss
+	switch {
+	case r <= 0:
+		return 1
+	case r >= 100:
+		return 4.5
+	}
+	return 1 + 0.035*r + 7e-6*r*(r-60)*(100-r)
+}
+
+// network returns the network of a remote address: its IPv4 or IPv6 prefix, or the address itself if it is not an IP
+func network(address string, v4, v6 int) string {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return address
+	}
+	bits := v6
+	if addr.Unmap().Is4() {
+		addr, bits = addr.Unmap(), v4
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return address
+	}
+	return prefix.String()
+}
+
+// groupBy groups calls by key, sorted by name
+func groupBy(calls []Call, percentile float64, key func(*Call) (string, string)) []Group {
+	byKey := make(map[string][]*Call)
+	names := make(map[string]string)
+	for i := range calls {
+		k, name := key(&calls[i])
+		byKey[k] = append(byKey[k], &calls[i])
+		names[k] = name
+	}
+	groups := make([]Group, 0, len(byKey))
+	for k, calls := range byKey {
+		groups = append(groups, newGroup(k, names[k], calls, percentile))
+	}
+	slices.SortFunc(groups, func(a, b Group) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.Key, b.Key))
+	})
+	return groups
+}
+
+func pointers(calls []Call) []*Call {
+	ptrs := make([]*Call, len(calls))
+	for i := range calls {
+		ptrs[i] = &calls[i]
+	}
+	return ptrs
+}
+
+func newGroup(key, name string, calls []*Call, percentile float64) Group {
+	g := Group{Key: key, Name: name, Calls: len(calls)}
+	if len(calls) == 0 {
+		return g
+	}
+	loss := make([]float64, len(calls))
+	jitter := make([]float64, len(calls))
+	mos := make([]float64, len(calls))
+	for i, c := range calls {
+		if c.Poor() {
+			g.PoorCalls++
+		}
+		g.Streams += c.Streams
+		loss[i], jitter[i], mos[i] = c.Loss, c.Jitter, c.MOS
+	}
+	g.PoorRate = float64(g.PoorCalls) / float64(g.Calls)
+	g.Loss = distribution(loss, percentile, false)
+	g.Jitter = distribution(jitter, percentile, false)
+	g.MOS = distribution(mos, percentile, true)
+	return g
+}
+
+// distribution summarises values, taking the percentile from the low end if lowIsWorse
+func distribution(values []float64, percentile float64, lowIsWorse bool) Distribution {
+	slices.Sort(values)
+	if lowIsWorse {
+		slices.Reverse(values)
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return Distribution{
+		Mean:       sum / float64(len(values)),
+		Median:     nearestRank(values, 50),
+		Percentile: nearestRank(values, percentile),
+		Worst:      values[len(values)-1],
+	}
+}
+
+// nearestRank returns the p-th percentile of sorted values by the nearest-rank method
+func nearestRank(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
+
+// worst returns at most top groups of at least minCalls calls, ranked worst first
+func worst(groups []Group, minCalls, top int) []Group {
+	ranked := slices.DeleteFunc(groups, func(g Group) bool { return g.Calls < minCalls || g.Key == "" })
+	slices.SortStableFunc(ranked, func(a, b Group) int {
+		return cmp.Or(cmp.Compare(b.PoorRate, a.PoorRate), cmp.Compare(a.MOS.Mean, b.MOS.Mean))
+	})
+	return ranked[:min(len(ranked), top)]
+}
@@ -0,0 +1,173 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package quality
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	infinity "github.com/pexip/go-infinity-sdk/v41"
+	"github.com/pexip/go-infinity-sdk/v41/history"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCDRs() []*history.CDR {
+	return []*history.CDR{
+		{
+			ParticipantID: 1, ConferenceID: 10, ConferenceName: "Sales", Vendor: "Pexip", RemoteAddress: "10.0.0.5", MediaNode: "node-a",
+			Streams: []history.MediaStream{
+				{StreamType: "audio", RxPackets: 1000, TxPackets: 1000},
+				{StreamType: "video", RxPackets: 1000, TxPackets: 1000, RxJitter: 5},
+			},
+		},
+		{
+			ParticipantID: 2, ConferenceID: 10, ConferenceName: "Sales", Vendor: "Acme", RemoteAddress: "10.0.0.9", MediaNode: "node-a",
+			Streams: []history.MediaStream{
+				{StreamType: "audio", RxPackets: 800, RxPacketsLost: 200, TxPackets: 1000},
+				{StreamType: "video", RxPackets: 50000, TxPackets: 50000},
+			},
+		},
+		{
+			ParticipantID: 3, ConferenceID: 11, ConferenceName: "Support", Vendor: "Acme", RemoteAddress: "192.168.1.1",
+			Streams: []history.MediaStream{
+				{Node: "node-b", StreamType: "video", RxPackets: 1000, TxPackets: 1000, TxJitter: 50},
+			},
+		},
+		{ParticipantID: 4, ConferenceID: 11, ConferenceName: "Support", Vendor: "Acme"},
+	}
+}
+
+func TestEstimateMOS(t *testing.T) {
+	assert.InDelta(t, 4.4, EstimateMOS(0, 0), 0.01)
+	assert.InDelta(t, 2.21, EstimateMOS(20, 0), 0.01)
+	assert.Less(t, EstimateMOS(0, 100), EstimateMOS(0, 10))
+	assert.Less(t, EstimateMOS(5, 0), EstimateMOS(1, 0))
+	assert.Equal(t, 1.0, EstimateMOS(100, 0))
+}
+
+func TestLossPercent(t *testing.T) {
+	assert.Equal(t, 5.0, rxLossPercent(950, 50), "received packets exclude those lost")
+	assert.Equal(t, 5.0, txLossPercent(1000, 50), "sent packets include those lost")
+	assert.Equal(t, 0.0, rxLossPercent(0, 0))
+	assert.Equal(t, 0.0, txLossPercent(0, 10))
+}
+
+func TestNetwork(t *testing.T) {
+	assert.Equal(t, "10.1.2.0/24", network("10.1.2.3", 24, 48))
+	assert.Equal(t, "10.1.2.0/24", network("::ffff:10.1.2.3", 24, 48))
+	assert.Equal(t, "10.0.0.0/8", network("10.1.2.3", 8, 48))
+	assert.Equal(t, "2001:db8:1::/48", network("2001:db8:1:2::5", 24, 48))
+	assert.Equal(t, "sip.example.com", network("sip.example.com", 24, 48))
+}
+
+func TestAnalyzer_Report(t *testing.T) {
+	a := NewAnalyzer(&Options{
+		Locations: map[string]string{"node-a": "Oslo", "node-b": "London"},
+		MinCalls:  1,
+	})
+	for _, cdr := range testCDRs() {
+		require.NoError(t, a.Write(cdr))
+	}
+	report := a.Report()
+
+	require.Len(t, report.Calls, 3)
+	assert.Equal(t, 1, report.Unmeasured)
+
+	good, lossy, jittery := report.Calls[0], report.Calls[1], report.Calls[2]
+	assert.False(t, good.Poor())
+	assert.Equal(t, "10.0.0.0/24", good.Network)
+	assert.Equal(t, "Oslo", good.Location)
+	assert.InDelta(t, 4.4, good.MOS, 0.01)
+
+	assert.InDelta(t, 200.0/51000*100, lossy.RxLoss, 1e-9)
+	assert.InDelta(t, 200.0/51000*100, lossy.Loss, 1e-9)
+	assert.InDelta(t, 2.21, lossy.MOS, 0.01, "MOS is estimated from the audio stream")
+	assert.Equal(t, []string{"MOS 2.21 below 3.5"}, lossy.Reasons)
+
+	assert.Equal(t, "node-b", jittery.Node, "node falls back to the streams' node")
+	assert.Equal(t, "London", jittery.Location)
+	assert.Equal(t, 50.0, jittery.Jitter)
+	assert.Equal(t, []string{"jitter 50ms above 30ms"}, jittery.Reasons)
+
+	assert.Equal(t, 3, report.Overall.Calls)
+	assert.Equal(t, 2, report.Overall.PoorCalls)
+	assert.Equal(t, 5, report.Overall.Streams)
+	assert.InDelta(t, 2.21, report.Overall.MOS.Worst, 0.01)
+	assert.InDelta(t, 4.35, report.Overall.MOS.Median, 0.01)
+	assert.Equal(t, 50.0, report.Overall.Jitter.Percentile)
+	assert.Equal(t, 5.0, report.Overall.Jitter.Median)
+	assert.Len(t, report.PoorCalls(), 2)
+
+	require.Len(t, report.Conferences, 2)
+	assert.Equal(t, "Sales", report.Conferences[0].Name)
+	assert.Equal(t, "10", report.Conferences[0].Key)
+	assert.Equal(t, 2, report.Conferences[0].Calls)
+	require.Len(t, report.Locations, 2)
+	assert.Equal(t, "London", report.Locations[0].Key)
+	require.Len(t, report.Nodes, 2)
+
+	require.Len(t, report.WorstVendors, 2)
+	assert.Equal(t, "Acme", report.WorstVendors[0].Key)
+	assert.Equal(t, 1.0, report.WorstVendors[0].PoorRate)
+	require.Len(t, report.WorstNetworks, 2)
+	assert.Equal(t, "192.168.1.0/24", report.WorstNetworks[0].Key)
+	assert.Equal(t, "10.0.0.0/24", report.WorstNetworks[1].Key)
+}
+
+func TestAnalyzer_Thresholds(t *testing.T) {
+	a := NewAnalyzer(&Options{MaxLoss: 0.1, MaxJitter: 60, MinMOS: 2, Top: 1})
+	for _, cdr := range testCDRs() {
+		a.Add(cdr)
+	}
+	report := a.Report()
+
+	assert.Equal(t, []string{"packet loss 0.4% above 0.1%"}, report.Calls[1].Reasons)
+	assert.False(t, report.Calls[2].Poor())
+	assert.Empty(t, report.WorstVendors, "no vendor has the default minimum of 5 calls")
+}
+
+func TestAnalyze(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var objects []map[string]interface{}
+		switch strings.TrimPrefix(r.URL.Path, "/api/admin/") {
+		case "configuration/v1/system_location/":
+			objects = []map[string]interface{}{{"id": 1, "name": "Oslo", "resource_uri": "/api/admin/configuration/v1/system_location/1/"}}
+		case "configuration/v1/worker_vm/":
+			objects = []map[string]interface{}{{"name": "node-a", "address": "10.44.0.1", "system_location": "/api/admin/configuration/v1/system_location/1/"}}
+		case "history/v1/participant/":
+			assert.Equal(t, "2025-03-01T00:00:00Z", r.URL.Query().Get("start_time__gte"))
+			objects = []map[string]interface{}{{"id": 1, "conference_id": 10, "vendor": "Acme", "media_node": "10.44.0.1", "start_time": "2025-03-01T09:00:00"}}
+		case "history/v1/conference/":
+			objects = []map[string]interface{}{{"id": 10, "name": "Sales"}}
+		case "history/v1/media_stream/":
+			objects = []map[string]interface{}{{"participant_id": 1, "stream_type": "audio", "rx_packets": 90, "rx_packets_lost": 10}}
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"objects": objects})
+	}))
+	defer server.Close()
+
+	client, err := infinity.New(infinity.WithBaseURL(server.URL))
+	require.NoError(t, err)
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	report, err := Analyze(t.Context(), client, from, from.Add(24*time.Hour), nil)
+	require.NoError(t, err)
+
+	require.Len(t, report.Calls, 1)
+	call := report.Calls[0]
+	assert.Equal(t, "Sales", call.Conference)
+	assert.Equal(t, "Oslo", call.Location)
+	assert.Equal(t, 10.0, call.Loss)
+	assert.True(t, call.Poor())
+}
@@ -0,0 +1,209 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package infinity
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APICategory identifies one of the Management API categories
+type APICategory string
+
+const (
+	CategoryConfiguration APICategory = "configuration"
+	CategoryStatus        APICategory = "status"
+	CategoryHistory       APICategory = "history"
+	CategoryCommand       APICategory = "command"
+)
+
+// categoryOf returns the API category of an endpoint such as "configuration/v1/conference/"
+func categoryOf(endpoint string) APICategory {
+	category, _, _ := strings.Cut(strings.TrimPrefix(endpoint, "/"), "/")
+	return APICategory(category)
+}
+
+// QueueStats reports how requests were held back by the client-side limits
+type QueueStats struct {
+	Requests int64         // Attempts that passed through the limits
+	Queued   int64         // Attempts that had to wait
+	WaitTime time.Duration // Total time spent waiting
+	MaxWait  time.Duration // Longest single wait
+}
+
+// tokenBucket is a token bucket rate limiter
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rps float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: rps, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// reserve takes a token and returns how long the caller must wait before using it
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a token taken by a reservation that was not used
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	delay := b.reserve()
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// limit combines an optional rate limit and an optional concurrency limit
+type limit struct {
+	bucket *tokenBucket
+	sem    chan struct{}
+}
+
+// requestLimiter applies client-wide and per-category limits to each attempt of a request
+type requestLimiter struct {
+	global     limit
+	categories map[APICategory]*limit
+
+	mu    sync.Mutex
+	stats map[APICategory]*QueueStats
+}
+
+func newRequestLimiter() *requestLimiter {
+	return &requestLimiter{
+		categories: make(map[APICategory]*limit),
+		stats:      make(map[APICategory]*QueueStats),
+	}
+}
+
+// requestLimiter returns the client's limiter, creating it on first use
+func (c *Client) requestLimiter() *requestLimiter {
+	if c.limiter == nil {
+		c.limiter = newRequestLimiter()
+	}
+	return c.limiter
+}
+
+func (l *requestLimiter) category(category APICategory) *limit {
+	if l.categories[category] == nil {
+		l.categories[category] = &limit{}
+	}
+	return l.categories[category]
+}
+
+// acquire waits until a request to category may be sent. The returned function must be called
+// once the response has been read.
+func (l *requestLimiter) acquire(ctx context.Context, category APICategory) (func(), error) {
+	limits := []*limit{&l.global}
+	if cl := l.categories[category]; cl != nil {
+		limits = append(limits, cl)
+	}
+
+	start := time.Now()
+	var held []chan struct{}
+	release := func() {
+		for _, sem := range held {
+			<-sem
+		}
+	}
+	// Tokens already taken are given back if a later limit is not acquired, as no request is sent
+	var taken []*tokenBucket
+	abort := func() {
+		for _, bucket := range taken {
+			bucket.cancel()
+		}
+		release()
+	}
+
+	for _, lim := range limits {
+		if lim.bucket != nil {
+			if err := lim.bucket.wait(ctx); err != nil {
+				abort()
+				return nil, err
+			}
+			taken = append(taken, lim.bucket)
+		}
+	}
+	for _, lim := range limits {
+		if lim.sem != nil {
+			select {
+			case lim.sem <- struct{}{}:
+				held = append(held, lim.sem)
+			case <-ctx.Done():
+				abort()
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	l.record(category, time.Since(start))
+	return release, nil
+}
+
+func (l *requestLimiter) record(category APICategory, wait time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := l.stats[category]
+	if stats == nil {
+		stats = &QueueStats{}
+		l.stats[category] = stats
+	}
+	stats.Requests++
+	// Waits below a millisecond are scheduling noise rather than queueing
+	if wait >= time.Millisecond {
+		stats.Queued++
+		stats.WaitTime += wait
+		stats.MaxWait = max(stats.MaxWait, wait)
+	}
+}
+
+// QueueStats returns the time spent waiting for the client-side rate and concurrency limits,
+// by API category. It is empty if no limits are configured.
+func (c *Client) QueueStats() map[APICategory]QueueStats {
+	stats := make(map[APICategory]QueueStats)
+	if c.limiter == nil {
+		return stats
+	}
+	c.limiter.mu.Lock()
+	defer c.limiter.mu.Unlock()
+	for category, s := range c.limiter.stats {
+		stats[category] = *s
+	}
+	return stats
+}
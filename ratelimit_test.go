@@ -0,0 +1,147 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package infinity
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryOf(t *testing.T) {
+	assert.Equal(t, CategoryConfiguration, categoryOf("configuration/v1/conference/"))
+	assert.Equal(t, CategoryStatus, categoryOf("/status/v1/participant/"))
+	assert.Equal(t, CategoryCommand, categoryOf("command/v1/participant/disconnect/"))
+	assert.Equal(t, CategoryHistory, categoryOf("history/v1/conference/"))
+}
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	bucket := newTokenBucket(10, 2)
+
+	assert.Zero(t, bucket.reserve())
+	assert.Zero(t, bucket.reserve())
+	assert.InDelta(t, 100*time.Millisecond, bucket.reserve(), float64(10*time.Millisecond))
+
+	bucket.cancel()
+	assert.InDelta(t, 100*time.Millisecond, bucket.reserve(), float64(10*time.Millisecond))
+}
+
+func TestRequestLimiter_AcquireCancelled(t *testing.T) {
+	l := newRequestLimiter()
+	l.global.bucket = newTokenBucket(0.001, 1)
+	l.category(CategoryCommand).bucket = newTokenBucket(0.001, 1)
+	l.category(CategoryCommand).bucket.reserve()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	_, err := l.acquire(ctx, CategoryCommand)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The global token taken before the category wait failed is given back
+	assert.Zero(t, l.global.bucket.reserve())
+}
+
+func TestClient_WithRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := New(WithBaseURL(server.URL), WithRateLimit(20, 1))
+	require.NoError(t, err)
+
+	start := time.Now()
+	for range 3 {
+		require.NoError(t, client.GetJSON(t.Context(), "configuration/v1/conference/", nil, nil))
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	stats := client.QueueStats()[CategoryConfiguration]
+	assert.Equal(t, int64(3), stats.Requests)
+	assert.Equal(t, int64(2), stats.Queued)
+	assert.Greater(t, stats.WaitTime, stats.MaxWait)
+}
+
+func TestClient_WithCategoryRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := New(WithBaseURL(server.URL), WithCategoryRateLimit(CategoryCommand, 0.1, 1))
+	require.NoError(t, err)
+
+	require.NoError(t, client.PostJSON(t.Context(), "command/v1/conference/lock/", nil, nil))
+	for range 3 {
+		require.NoError(t, client.GetJSON(t.Context(), "status/v1/conference/", nil, nil))
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	err = client.PostJSON(ctx, "command/v1/conference/lock/", nil, nil)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, client.QueueStats()[CategoryStatus].Queued)
+}
+
+func TestClient_WithMaxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(&inFlight, 1)
+		for {
+			m := atomic.LoadInt64(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt64(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt64(&inFlight, -1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := New(WithBaseURL(server.URL), WithMaxConcurrentRequests(2))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Go(func() {
+			assert.NoError(t, client.GetJSON(t.Context(), "status/v1/conference/", nil, nil))
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(2), atomic.LoadInt64(&maxInFlight))
+	assert.Equal(t, int64(6), client.QueueStats()[CategoryStatus].Requests)
+}
+
+func TestLimitOptions_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		option ClientOption
+	}{
+		{name: "zero rate", option: WithRateLimit(0, 1)},
+		{name: "zero burst", option: WithRateLimit(1, 0)},
+		{name: "zero category rate", option: WithCategoryRateLimit(CategoryStatus, 0, 1)},
+		{name: "zero concurrency", option: WithMaxConcurrentRequests(0)},
+		{name: "zero category concurrency", option: WithCategoryMaxConcurrentRequests(CategoryCommand, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.option)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,405 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package reconcile brings Pexip Infinity configuration in line with a declared desired state.
+// It reads the live configuration through the Configuration API, computes a plan of creates,
+// updates and deletes keyed on each resource's natural key, and applies that plan in dependency order.
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/pexip/go-infinity-sdk/v41/config"
+	"github.com/pexip/go-infinity-sdk/v41/internal/resource"
+)
+
+// Kind identifies a type of configuration resource managed by the reconciler
+type Kind string
+
+const (
+	KindSystemLocation     Kind = "system_location"
+	KindWorkerVM           Kind = "worker_vm"
+	KindConference         Kind = "conference"
+	KindConferenceAlias    Kind = "conference_alias"
+	KindEndUser            Kind = "end_user"
+	KindDevice             Kind = "device"
+	KindGatewayRoutingRule Kind = "gateway_routing_rule"
+)
+
+// kindOrder is the order in which kinds are created and updated so that referenced
+// resources exist before the resources that refer to them. Deletes run in reverse.
+var kindOrder = []Kind{
+	KindSystemLocation,
+	KindWorkerVM,
+	KindConference,
+	KindConferenceAlias,
+	KindEndUser,
+	KindDevice,
+	KindGatewayRoutingRule,
+}
+
+// Action is the operation a Change performs
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// ErrInvalidDesiredState is returned when the desired state cannot be planned, for example
+// because of duplicate natural keys or references to resources that do not exist
+var ErrInvalidDesiredState = errors.New("invalid desired state")
+
+// DesiredState is the full set of resources that should exist. Each resource is matched to
+// live configuration by its natural key: name for system locations, worker VMs and conferences,
+// alias for conference aliases and devices, primary email address for end users and priority
+// for gateway routing rules.
+//
+// Reference fields such as ConferenceAlias.Conference, WorkerVM.SystemLocation or
+// Conference.SystemLocation may hold either a resource URI or the natural key of the
+// referenced resource, which is resolved when the plan is computed and applied.
+//
+// Fields left at their zero value that are omitted from the resource's JSON are not managed:
+// they are neither compared nor changed on update.
+type DesiredState struct {
+	SystemLocations     []config.SystemLocation
+	WorkerVMs           []config.WorkerVM
+	Conferences         []config.Conference
+	ConferenceAliases   []config.ConferenceAlias
+	EndUsers            []config.EndUser
+	Devices             []config.Device
+	GatewayRoutingRules []config.GatewayRoutingRule
+}
+
+// Options configures a Reconciler
+type Options struct {
+	// Prune deletes live resources that are not part of the desired state. Pruning must be
+	// scoped with PruneTag and/or PruneSyncTag; only resources whose tag and sync tag match
+	// are deleted. Conference aliases are in scope when their conference is.
+	Prune        bool
+	PruneTag     string
+	PruneSyncTag string
+}
+
+// Reconciler computes and applies plans against the Configuration API
+type Reconciler struct {
+	resources map[Kind]*kindResource
+	opts      Options
+}
+
+// New creates a new Reconciler using the given Configuration API service
+func New(svc *config.Service, opts *Options) *Reconciler {
+	r := &Reconciler{resources: newResources(svc)}
+	if opts != nil {
+		r.opts = *opts
+	}
+	return r
+}
+
+// Change is a single operation in a Plan
+type Change struct {
+	Kind   Kind
+	Action Action
+	Key    string
+	// ID is the live resource ID for updates and deletes, zero for an update that sets
+	// references to resources of the same kind after they have been created
+	ID int
+	// Fields lists the fields that differ for updates
+	Fields []string
+
+	desired object
+	live    object
+}
+
+// Plan is an ordered list of changes that brings live configuration to the desired state
+type Plan struct {
+	Changes []Change
+
+	// uris maps the natural key of every known resource to its resource URI, and is
+	// extended with newly created resources while the plan is applied
+	uris map[Kind]map[string]string
+}
+
+// IsEmpty reports whether the plan has no changes
+func (p *Plan) IsEmpty() bool {
+	return len(p.Changes) == 0
+}
+
+// WriteTo writes a human readable summary of the plan to w, one change per line
+func (p *Plan) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	if p.IsEmpty() {
+		b.WriteString("no changes\n")
+	}
+	for _, c := range p.Changes {
+		switch c.Action {
+		case ActionCreate:
+			fmt.Fprintf(&b, "+ %s %q\n", c.Kind, c.Key)
+		case ActionUpdate:
+			fmt.Fprintf(&b, "~ %s %q (%s)\n", c.Kind, c.Key, strings.Join(c.Fields, ", "))
+		case ActionDelete:
+			fmt.Fprintf(&b, "- %s %q\n", c.Kind, c.Key)
+		}
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Plan reads the live configuration and computes the changes required to reach desired.
+// Nothing is modified; the returned plan can be inspected as a dry run and then passed to Apply.
+func (r *Reconciler) Plan(ctx context.Context, desired *DesiredState) (*Plan, error) {
+	if r.opts.Prune && r.opts.PruneTag == "" && r.opts.PruneSyncTag == "" {
+		return nil, errors.New("prune requires PruneTag or PruneSyncTag to be set")
+	}
+
+	want, err := desired.objects()
+	if err != nil {
+		return nil, err
+	}
+
+	wantKeys := make(map[Kind]map[string]bool)
+	for kind, objects := range want {
+		wantKeys[kind] = make(map[string]bool)
+		for _, obj := range objects {
+			key := keyOf(obj, r.resources[kind].key)
+			if key == "" {
+				return nil, fmt.Errorf("%w: %s is missing %s", ErrInvalidDesiredState, kind, r.resources[kind].key)
+			}
+			if wantKeys[kind][key] {
+				return nil, fmt.Errorf("%w: duplicate %s %q", ErrInvalidDesiredState, kind, key)
+			}
+			wantKeys[kind][key] = true
+		}
+	}
+
+	live, err := r.readLive(ctx, want)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{uris: make(map[Kind]map[string]string)}
+	for kind, objects := range live {
+		plan.uris[kind] = make(map[string]string)
+		for key, obj := range objects {
+			plan.uris[kind][key], _ = obj["resource_uri"].(string)
+		}
+	}
+
+	for _, kind := range kindOrder {
+		res := r.resources[kind]
+		// References to resources of the same kind that are created by the plan, such as a system location
+		// overflowing to a new one, are set by updates once every resource of the kind has been created
+		var deferred []Change
+		for _, obj := range want[kind] {
+			key := keyOf(obj, res.key)
+			if err = r.checkRefs(kind, obj, plan.uris, wantKeys); err != nil {
+				return nil, err
+			}
+			pending := res.pendingRefs(kind, obj, live[kind])
+			current, ok := live[kind][key]
+			if !ok {
+				create := Change{Kind: kind, Action: ActionCreate, Key: key, desired: obj}
+				if len(pending) > 0 {
+					create.desired = without(obj, pending)
+					deferred = append(deferred, Change{Kind: kind, Action: ActionUpdate, Key: key, Fields: pending, desired: obj})
+				}
+				plan.Changes = append(plan.Changes, create)
+				continue
+			}
+			if fields := res.diff(plan.resolve(kind, obj, r.resources), current); len(fields) > 0 {
+				update := Change{
+					Kind:    kind,
+					Action:  ActionUpdate,
+					Key:     key,
+					ID:      resource.ID(current),
+					Fields:  fields,
+					desired: obj,
+					live:    current,
+				}
+				if len(pending) > 0 {
+					deferred = append(deferred, update)
+				} else {
+					plan.Changes = append(plan.Changes, update)
+				}
+			}
+		}
+		plan.Changes = append(plan.Changes, deferred...)
+	}
+
+	if r.opts.Prune {
+		plan.Changes = append(plan.Changes, r.pruneChanges(live, wantKeys)...)
+	}
+	return plan, nil
+}
+
+// Apply executes the changes in plan in order, stopping at the first failure
+func (r *Reconciler) Apply(ctx context.Context, plan *Plan) error {
+	created := make(map[Kind]map[string]object)
+	for _, c := range plan.Changes {
+		res := r.resources[c.Kind]
+
+		var err error
+		if c.Action == ActionUpdate && c.live == nil {
+			// Setting references of a resource created earlier in the plan, which is read back first
+			if created[c.Kind] == nil {
+				if created[c.Kind], err = r.readKind(ctx, c.Kind); err != nil {
+					return err
+				}
+			}
+			c.live = created[c.Kind][c.Key]
+			if c.live == nil {
+				return fmt.Errorf("failed to update %s %q: not found after it was created", c.Kind, c.Key)
+			}
+			c.ID = resource.ID(c.live)
+		}
+		switch c.Action {
+		case ActionCreate:
+			var uri string
+			if uri, err = res.create(ctx, plan.resolve(c.Kind, c.desired, r.resources)); err == nil {
+				if plan.uris[c.Kind] == nil {
+					plan.uris[c.Kind] = make(map[string]string)
+				}
+				plan.uris[c.Kind][c.Key] = uri
+			}
+		case ActionUpdate:
+			err = res.update(ctx, c.ID, merge(c.live, plan.resolve(c.Kind, c.desired, r.resources)))
+		case ActionDelete:
+			err = res.delete(ctx, c.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s %s %q: %w", c.Action, c.Kind, c.Key, err)
+		}
+	}
+	return nil
+}
+
+// readLive lists every kind that is desired, referenced by a desired kind or subject to pruning,
+// and indexes the results by natural key
+func (r *Reconciler) readLive(ctx context.Context, want map[Kind][]object) (map[Kind]map[string]object, error) {
+	needed := make(map[Kind]bool)
+	for _, kind := range kindOrder {
+		if len(want[kind]) > 0 || r.opts.Prune {
+			needed[kind] = true
+			for _, ref := range r.resources[kind].refs {
+				needed[ref] = true
+			}
+		}
+	}
+
+	live := make(map[Kind]map[string]object)
+	for _, kind := range kindOrder {
+		if !needed[kind] {
+			continue
+		}
+		objects, err := r.readKind(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+		live[kind] = objects
+	}
+	return live, nil
+}
+
+// readKind lists the live resources of kind, indexed by natural key
+func (r *Reconciler) readKind(ctx context.Context, kind Kind) (map[string]object, error) {
+	res := r.resources[kind]
+	objects, err := res.list(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", kind, err)
+	}
+	live := make(map[string]object, len(objects))
+	for _, obj := range objects {
+		live[keyOf(obj, res.key)] = resource.Flatten(obj)
+	}
+	return live, nil
+}
+
+// checkRefs verifies that every reference in obj names a resource that exists or will be created
+func (r *Reconciler) checkRefs(kind Kind, obj object, uris map[Kind]map[string]string, wantKeys map[Kind]map[string]bool) error {
+	for field, target := range r.resources[kind].refs {
+		for _, ref := range refValues(obj[field]) {
+			if isResourceURI(ref) || uris[target][ref] != "" || wantKeys[target][ref] {
+				continue
+			}
+			return fmt.Errorf("%w: %s %q refers to unknown %s %q", ErrInvalidDesiredState, kind, keyOf(obj, r.resources[kind].key), target, ref)
+		}
+	}
+	return nil
+}
+
+// pruneChanges returns deletes for live resources that are not desired and are in the prune scope
+func (r *Reconciler) pruneChanges(live map[Kind]map[string]object, wantKeys map[Kind]map[string]bool) []Change {
+	var changes []Change
+	kinds := slices.Clone(kindOrder)
+	slices.Reverse(kinds)
+	for _, kind := range kinds {
+		var keys []string
+		for key, obj := range live[kind] {
+			if !wantKeys[kind][key] && r.inPruneScope(kind, obj, live) {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			changes = append(changes, Change{Kind: kind, Action: ActionDelete, Key: key, ID: resource.ID(live[kind][key]), live: live[kind][key]})
+		}
+	}
+	return changes
+}
+
+func (r *Reconciler) inPruneScope(kind Kind, obj object, live map[Kind]map[string]object) bool {
+	if kind == KindConferenceAlias {
+		conference, _ := obj["conference"].(string)
+		for _, parent := range live[KindConference] {
+			if parent["resource_uri"] == conference {
+				return r.inPruneScope(KindConference, parent, live)
+			}
+		}
+		return false
+	}
+
+	res := r.resources[kind]
+	if r.opts.PruneTag != "" && (!res.hasTag || obj["tag"] != r.opts.PruneTag) {
+		return false
+	}
+	if r.opts.PruneSyncTag != "" && (!res.hasSyncTag || obj["sync_tag"] != r.opts.PruneSyncTag) {
+		return false
+	}
+	return true
+}
+
+// resolve returns a copy of obj with natural key references replaced by resource URIs.
+// References to resources that do not exist yet are left untouched.
+func (p *Plan) resolve(kind Kind, obj object, resources map[Kind]*kindResource) object {
+	resolved := make(object, len(obj))
+	for field, value := range obj {
+		resolved[field] = value
+	}
+	for field, target := range resources[kind].refs {
+		switch v := obj[field].(type) {
+		case string:
+			if uri := p.uris[target][v]; uri != "" && !isResourceURI(v) {
+				resolved[field] = uri
+			}
+		case []interface{}:
+			values := make([]interface{}, len(v))
+			for i, item := range v {
+				values[i] = item
+				if s, ok := item.(string); ok && !isResourceURI(s) && p.uris[target][s] != "" {
+					values[i] = p.uris[target][s]
+				}
+			}
+			resolved[field] = values
+		}
+	}
+	return resolved
+}
@@ -0,0 +1,273 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package reconcile
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pexip/go-infinity-sdk/v41/config"
+	"github.com/pexip/go-infinity-sdk/v41/interfaces"
+	"github.com/pexip/go-infinity-sdk/v41/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	conferenceURI1 = "/api/admin/configuration/v1/conference/1/"
+	conferenceURI2 = "/api/admin/configuration/v1/conference/2/"
+)
+
+func mockList(m *interfaces.HTTPClientMock, endpoint string, body string) {
+	m.On("GetJSON", mock.Anything, endpoint, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		if err := json.Unmarshal([]byte(body), args.Get(3)); err != nil {
+			panic(err)
+		}
+	})
+}
+
+func mockLiveState(m *interfaces.HTTPClientMock) {
+	mockList(m, "configuration/v1/system_location/", `{"meta": {}, "objects": []}`)
+	mockList(m, "configuration/v1/worker_vm/", `{"meta": {}, "objects": []}`)
+	mockList(m, "configuration/v1/end_user/", `{"meta": {}, "objects": []}`)
+	mockList(m, "configuration/v1/device/", `{"meta": {}, "objects": []}`)
+	mockList(m, "configuration/v1/gateway_routing_rule/", `{"meta": {}, "objects": []}`)
+	mockList(m, "configuration/v1/conference/", `{"meta": {}, "objects": [
+		{"id": 1, "name": "sales", "description": "old", "tag": "git", "service_type": "conference", "resource_uri": "`+conferenceURI1+`",
+		 "aliases": [{"id": 11, "alias": "sales@example.com", "conference": "`+conferenceURI1+`", "resource_uri": "/api/admin/configuration/v1/conference_alias/11/"}]},
+		{"id": 2, "name": "stale", "tag": "git", "service_type": "conference", "resource_uri": "`+conferenceURI2+`"},
+		{"id": 3, "name": "manual", "service_type": "conference", "resource_uri": "/api/admin/configuration/v1/conference/3/"}
+	]}`)
+	mockList(m, "configuration/v1/conference_alias/", `{"meta": {}, "objects": [
+		{"id": 11, "alias": "sales@example.com", "conference": "`+conferenceURI1+`", "resource_uri": "/api/admin/configuration/v1/conference_alias/11/"},
+		{"id": 12, "alias": "stale@example.com", "conference": "`+conferenceURI2+`", "resource_uri": "/api/admin/configuration/v1/conference_alias/12/"}
+	]}`)
+}
+
+func desiredState() *DesiredState {
+	return &DesiredState{
+		Conferences: []config.Conference{
+			{Name: "sales", Description: "new", Tag: "git", ServiceType: "conference"},
+			{Name: "support", Tag: "git", ServiceType: "conference"},
+		},
+		ConferenceAliases: []config.ConferenceAlias{
+			{Alias: "sales@example.com", Conference: "sales"},
+			{Alias: "support@example.com", Conference: "support"},
+		},
+	}
+}
+
+func TestReconciler_Plan(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+	mockLiveState(client)
+
+	r := New(config.New(client), &Options{Prune: true, PruneTag: "git"})
+	plan, err := r.Plan(t.Context(), desiredState())
+	require.NoError(t, err)
+
+	var summary strings.Builder
+	_, err = plan.WriteTo(&summary)
+	require.NoError(t, err)
+
+	expected := `~ conference "sales" (description)
++ conference "support"
++ conference_alias "support@example.com"
+- conference_alias "stale@example.com"
+- conference "stale"
+`
+	assert.Equal(t, expected, summary.String())
+	assert.Equal(t, 1, plan.Changes[0].ID)
+	assert.Equal(t, 12, plan.Changes[3].ID)
+	assert.Equal(t, 2, plan.Changes[4].ID)
+}
+
+func TestReconciler_Plan_NoPrune(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+	mockLiveState(client)
+
+	r := New(config.New(client), nil)
+	plan, err := r.Plan(t.Context(), desiredState())
+	require.NoError(t, err)
+
+	for _, c := range plan.Changes {
+		assert.NotEqual(t, ActionDelete, c.Action)
+	}
+	assert.Len(t, plan.Changes, 3)
+}
+
+func TestReconciler_Plan_NoChanges(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+	mockLiveState(client)
+
+	desired := &DesiredState{
+		Conferences:       []config.Conference{{Name: "sales", Description: "old", Tag: "git"}},
+		ConferenceAliases: []config.ConferenceAlias{{Alias: "sales@example.com", Conference: "sales"}},
+	}
+
+	r := New(config.New(client), nil)
+	plan, err := r.Plan(t.Context(), desired)
+	require.NoError(t, err)
+
+	var summary strings.Builder
+	_, err = plan.WriteTo(&summary)
+	require.NoError(t, err)
+
+	assert.True(t, plan.IsEmpty())
+	assert.Equal(t, "no changes\n", summary.String())
+}
+
+func TestReconciler_Plan_InvalidDesiredState(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired *DesiredState
+		wantErr string
+	}{
+		{
+			name: "duplicate key",
+			desired: &DesiredState{
+				Conferences: []config.Conference{{Name: "sales"}, {Name: "sales"}},
+			},
+			wantErr: `invalid desired state: duplicate conference "sales"`,
+		},
+		{
+			name: "missing key",
+			desired: &DesiredState{
+				Devices: []config.Device{{Description: "no alias"}},
+			},
+			wantErr: "invalid desired state: device is missing alias",
+		},
+		{
+			name: "unknown reference",
+			desired: &DesiredState{
+				ConferenceAliases: []config.ConferenceAlias{{Alias: "x@example.com", Conference: "missing"}},
+			},
+			wantErr: `invalid desired state: conference_alias "x@example.com" refers to unknown conference "missing"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := interfaces.NewHTTPClientMock()
+			mockLiveState(client)
+
+			r := New(config.New(client), nil)
+			_, err := r.Plan(t.Context(), tt.desired)
+
+			assert.ErrorIs(t, err, ErrInvalidDesiredState)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestReconciler_Plan_PruneRequiresScope(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+
+	r := New(config.New(client), &Options{Prune: true})
+	_, err := r.Plan(t.Context(), desiredState())
+
+	assert.EqualError(t, err, "prune requires PruneTag or PruneSyncTag to be set")
+	client.AssertNotCalled(t, "GetJSON", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReconciler_Apply(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+	mockLiveState(client)
+
+	client.On("PutJSON", mock.Anything, "configuration/v1/conference/1/", mock.MatchedBy(func(req *config.ConferenceUpdateRequest) bool {
+		return req.Name == "sales" && req.Description == "new" && req.Tag == "git" &&
+			req.Aliases != nil && len(*req.Aliases) == 1 && (*req.Aliases)[0] == "/api/admin/configuration/v1/conference_alias/11/"
+	}), mock.Anything).Return(nil)
+	client.On("PostWithResponse", mock.Anything, "configuration/v1/conference/", mock.MatchedBy(func(req *config.ConferenceCreateRequest) bool {
+		return req.Name == "support" && req.Tag == "git"
+	}), nil).Return(&types.PostResponse{ResourceURI: "https://admin.example.com/api/admin/configuration/v1/conference/4/"}, nil)
+	client.On("PostWithResponse", mock.Anything, "configuration/v1/conference_alias/", &config.ConferenceAliasCreateRequest{
+		Alias:      "support@example.com",
+		Conference: "/api/admin/configuration/v1/conference/4/",
+	}, nil).Return(&types.PostResponse{ResourceURI: "/api/admin/configuration/v1/conference_alias/13/"}, nil)
+	client.On("DeleteJSON", mock.Anything, "configuration/v1/conference_alias/12/", nil).Return(nil)
+	client.On("DeleteJSON", mock.Anything, "configuration/v1/conference/2/", nil).Return(nil)
+
+	r := New(config.New(client), &Options{Prune: true, PruneTag: "git"})
+	plan, err := r.Plan(t.Context(), desiredState())
+	require.NoError(t, err)
+
+	err = r.Apply(t.Context(), plan)
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
+
+func TestReconciler_Apply_Error(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+	mockLiveState(client)
+
+	client.On("PostWithResponse", mock.Anything, "configuration/v1/conference/", mock.Anything, nil).Return(nil, assert.AnError)
+
+	desired := &DesiredState{
+		Conferences:       []config.Conference{{Name: "support"}},
+		ConferenceAliases: []config.ConferenceAlias{{Alias: "support@example.com", Conference: "support"}},
+	}
+
+	r := New(config.New(client), nil)
+	plan, err := r.Plan(t.Context(), desired)
+	require.NoError(t, err)
+
+	err = r.Apply(t.Context(), plan)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ErrorContains(t, err, `failed to create conference "support"`)
+	client.AssertNotCalled(t, "PostWithResponse", mock.Anything, "configuration/v1/conference_alias/", mock.Anything, nil)
+}
+
+func TestReconciler_Apply_SelfReferences(t *testing.T) {
+	const osloURI = "/api/admin/configuration/v1/system_location/1/"
+	const londonURI = "/api/admin/configuration/v1/system_location/2/"
+	oslo, london := "oslo", "london"
+
+	client := interfaces.NewHTTPClientMock()
+	client.On("GetJSON", mock.Anything, "configuration/v1/system_location/", mock.Anything, mock.Anything).Return(nil).Once()
+	mockList(client, "configuration/v1/system_location/", `{"meta": {}, "objects": [
+		{"id": 1, "name": "oslo", "mtu": 1500, "resource_uri": "`+osloURI+`"},
+		{"id": 2, "name": "london", "mtu": 1500, "resource_uri": "`+londonURI+`"}
+	]}`)
+	client.On("PostWithResponse", mock.Anything, "configuration/v1/system_location/", mock.MatchedBy(func(req *config.SystemLocationCreateRequest) bool {
+		return req.Name == "oslo" && req.OverflowLocation1 == nil
+	}), nil).Return(&types.PostResponse{ResourceURI: osloURI}, nil)
+	client.On("PostWithResponse", mock.Anything, "configuration/v1/system_location/", mock.MatchedBy(func(req *config.SystemLocationCreateRequest) bool {
+		return req.Name == "london" && req.OverflowLocation1 == nil && req.TranscodingLocation == nil
+	}), nil).Return(&types.PostResponse{ResourceURI: londonURI}, nil)
+	client.On("PutJSON", mock.Anything, "configuration/v1/system_location/1/", mock.MatchedBy(func(req *config.SystemLocationUpdateRequest) bool {
+		return req.MTU == 1500 && req.OverflowLocation1 != nil && *req.OverflowLocation1 == londonURI
+	}), mock.Anything).Return(nil)
+	client.On("PutJSON", mock.Anything, "configuration/v1/system_location/2/", mock.MatchedBy(func(req *config.SystemLocationUpdateRequest) bool {
+		return req.OverflowLocation1 != nil && *req.OverflowLocation1 == osloURI &&
+			req.TranscodingLocation != nil && *req.TranscodingLocation == osloURI
+	}), mock.Anything).Return(nil)
+
+	r := New(config.New(client), nil)
+	plan, err := r.Plan(t.Context(), &DesiredState{
+		SystemLocations: []config.SystemLocation{
+			{Name: "oslo", OverflowLocation1: &london},
+			{Name: "london", OverflowLocation1: &oslo, TranscodingLocation: &oslo},
+		},
+	})
+	require.NoError(t, err)
+
+	var summary strings.Builder
+	_, err = plan.WriteTo(&summary)
+	require.NoError(t, err)
+	assert.Equal(t, `+ system_location "oslo"
++ system_location "london"
+~ system_location "oslo" (overflow_location1)
+~ system_location "london" (overflow_location1, transcoding_location)
+`, summary.String())
+
+	require.NoError(t, r.Apply(t.Context(), plan))
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,230 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package reconcile
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pexip/go-infinity-sdk/v41/config"
+	"github.com/pexip/go-infinity-sdk/v41/internal/resource"
+)
+
+type object = resource.Object
+
+// kindResource describes how the reconciler reads, writes and compares one Kind
+type kindResource struct {
+	key        string          // natural key field
+	refs       map[string]Kind // reference fields and the kind they refer to
+	writeOnly  []string        // fields not returned by the API, excluded from diffs
+	hasTag     bool
+	hasSyncTag bool
+
+	list   resource.ListFunc
+	create resource.CreateFunc
+	update resource.UpdateFunc
+	delete func(ctx context.Context, id int) error
+}
+
+func newResources(svc *config.Service) map[Kind]*kindResource {
+	return map[Kind]*kindResource{
+		KindSystemLocation: {
+			key: "name",
+			refs: map[string]Kind{
+				"overflow_location1":   KindSystemLocation,
+				"overflow_location2":   KindSystemLocation,
+				"transcoding_location": KindSystemLocation,
+			},
+			list:   resource.ListWith(svc.AllSystemLocations),
+			create: resource.CreateWith(svc.CreateSystemLocation),
+			update: resource.UpdateWith(svc.UpdateSystemLocation),
+			delete: svc.DeleteSystemLocation,
+		},
+		KindWorkerVM: {
+			key:       "name",
+			refs:      map[string]Kind{"system_location": KindSystemLocation},
+			writeOnly: []string{"password", "snmp_authentication_password", "snmp_privacy_password"},
+			list:      resource.ListWith(svc.AllWorkerVMs),
+			create:    resource.CreateWith(svc.CreateWorkerVM),
+			update:    resource.UpdateWith(svc.UpdateWorkerVM),
+			delete:    svc.DeleteWorkerVM,
+		},
+		KindConference: {
+			key:        "name",
+			refs:       map[string]Kind{"system_location": KindSystemLocation},
+			hasTag:     true,
+			hasSyncTag: true,
+			list:       resource.ListWith(svc.AllConferences),
+			create:     resource.CreateWith(svc.CreateConference),
+			update:     resource.UpdateWith(svc.UpdateConference),
+			delete:     svc.DeleteConference,
+		},
+		KindConferenceAlias: {
+			key:    "alias",
+			refs:   map[string]Kind{"conference": KindConference},
+			list:   resource.ListWith(svc.AllConferenceAliases),
+			create: resource.CreateWith(svc.CreateConferenceAlias),
+			update: resource.UpdateWith(svc.UpdateConferenceAlias),
+			delete: svc.DeleteConferenceAlias,
+		},
+		KindEndUser: {
+			key:        "primary_email_address",
+			hasSyncTag: true,
+			list:       resource.ListWith(svc.AllEndUsers),
+			create:     resource.CreateWith(svc.CreateEndUser),
+			update:     resource.UpdateWith(svc.UpdateEndUser),
+			delete:     svc.DeleteEndUser,
+		},
+		KindDevice: {
+			key:        "alias",
+			writeOnly:  []string{"password"},
+			hasTag:     true,
+			hasSyncTag: true,
+			list:       resource.ListWith(svc.AllDevices),
+			create:     resource.CreateWith(svc.CreateDevice),
+			update:     resource.UpdateWith(svc.UpdateDevice),
+			delete:     svc.DeleteDevice,
+		},
+		KindGatewayRoutingRule: {
+			key: "priority",
+			refs: map[string]Kind{
+				"match_source_location": KindSystemLocation,
+				"outgoing_location":     KindSystemLocation,
+			},
+			hasTag: true,
+			list:   resource.ListWith(svc.AllGatewayRoutingRules),
+			create: resource.CreateWith(svc.CreateGatewayRoutingRule),
+			update: resource.UpdateWith(svc.UpdateGatewayRoutingRule),
+			delete: svc.DeleteGatewayRoutingRule,
+		},
+	}
+}
+
+// diff returns the sorted list of desired fields whose value differs from live
+func (r *kindResource) diff(desired, live object) []string {
+	var fields []string
+	for field, value := range resource.Flatten(desired) {
+		if slices.Contains(resource.MetaFields, field) || slices.Contains(r.writeOnly, field) {
+			continue
+		}
+		if !reflect.DeepEqual(value, live[field]) {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// pendingRefs returns the sorted reference fields of obj that name a resource of its own kind
+// that does not exist yet
+func (r *kindResource) pendingRefs(kind Kind, obj object, live map[string]object) []string {
+	var fields []string
+	for field, target := range r.refs {
+		if target != kind {
+			continue
+		}
+		for _, ref := range refValues(obj[field]) {
+			if _, ok := live[ref]; !ok && !isResourceURI(ref) {
+				fields = append(fields, field)
+				break
+			}
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// without returns a copy of obj without fields
+func without(obj object, fields []string) object {
+	out := make(object, len(obj))
+	for field, value := range obj {
+		if !slices.Contains(fields, field) {
+			out[field] = value
+		}
+	}
+	return out
+}
+
+func keyOf(obj object, field string) string {
+	switch v := obj[field].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+func refValues(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var refs []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				refs = append(refs, s)
+			}
+		}
+		return refs
+	default:
+		return nil
+	}
+}
+
+func isResourceURI(s string) bool {
+	return strings.HasPrefix(s, "/api/admin/")
+}
+
+func (d *DesiredState) objects() (map[Kind][]object, error) {
+	want := make(map[Kind][]object)
+	if d == nil {
+		return want, nil
+	}
+	var err error
+	for _, add := range []func() error{
+		func() error { return appendObjects(want, KindSystemLocation, d.SystemLocations) },
+		func() error { return appendObjects(want, KindWorkerVM, d.WorkerVMs) },
+		func() error { return appendObjects(want, KindConference, d.Conferences) },
+		func() error { return appendObjects(want, KindConferenceAlias, d.ConferenceAliases) },
+		func() error { return appendObjects(want, KindEndUser, d.EndUsers) },
+		func() error { return appendObjects(want, KindDevice, d.Devices) },
+		func() error { return appendObjects(want, KindGatewayRoutingRule, d.GatewayRoutingRules) },
+	} {
+		if err = add(); err != nil {
+			return nil, err
+		}
+	}
+	return want, nil
+}
+
+func appendObjects[T any](want map[Kind][]object, kind Kind, items []T) error {
+	for _, item := range items {
+		obj, err := resource.ToObject(item)
+		if err != nil {
+			return err
+		}
+		want[kind] = append(want[kind], obj)
+	}
+	return nil
+}
+
+// merge overlays desired on top of live so that unmanaged fields keep their live value
+func merge(live, desired object) object {
+	merged := make(object, len(live)+len(desired))
+	for field, value := range live {
+		merged[field] = value
+	}
+	for field, value := range resource.WithoutMeta(desired) {
+		merged[field] = value
+	}
+	return merged
+}
@@ -24,6 +24,8 @@ type RetryConfig struct {
 	BackoffMax   time.Duration // Maximum backoff duration
 	Multiplier   float64       // Backoff multiplier for exponential backoff
 	JitterFactor float64       // Jitter factor to add randomness (0.0-1.0)
+	MaxElapsed   time.Duration // Retry budget: no retry is made that would end past this time since the first attempt (0 = no limit)
+	RetryCommand bool          // Retry POST requests to the Command API, which are not idempotent
 }
 
 // DefaultRetryConfig returns a sensible default retry configuration
@@ -109,3 +111,31 @@ func (rc *RetryConfig) CalculateBackoff(attempt int) time.Duration {
 
 	return time.Duration(backoff)
 }
+
+// Next implements RetryPolicy. A retriable failure is retried after the exponential backoff, or after
+// the delay requested by a Retry-After header if that is longer, until MaxRetries or MaxElapsed is reached.
+// A Retry-After delay longer than BackoffMax is not waited for; the failure is returned instead.
+// POST requests to the Command API are only retried if RetryCommand is set.
+func (rc *RetryConfig) Next(state *RetryState) (time.Duration, bool) {
+	if state.Attempt > rc.MaxRetries {
+		return 0, false
+	}
+	if isCommand(state.Request) && !rc.RetryCommand {
+		return 0, false
+	}
+	if !rc.IsRetriable(state.StatusCode(), state.Err) {
+		return 0, false
+	}
+
+	backoff := rc.CalculateBackoff(state.Attempt)
+	if retryAfter, ok := RetryAfter(state.Response); ok && retryAfter > backoff {
+		if rc.BackoffMax > 0 && retryAfter > rc.BackoffMax {
+			return 0, false
+		}
+		backoff = retryAfter
+	}
+	if rc.MaxElapsed > 0 && state.Elapsed+backoff > rc.MaxElapsed {
+		return 0, false
+	}
+	return backoff, true
+}
@@ -0,0 +1,71 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package infinity
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy decides whether a failed request is retried and how long to wait before the next attempt.
+// RetryConfig is the default implementation.
+type RetryPolicy interface {
+	// Next returns the delay before retrying the failed attempt described by state,
+	// and false if the request must not be retried
+	Next(state *RetryState) (time.Duration, bool)
+}
+
+// RetryState describes a failed attempt
+type RetryState struct {
+	Request  *Request
+	Attempt  int           // Number of attempts made so far, starting at 1
+	Response *Response     // Error response, nil if the request failed before a response was received
+	Err      error         // Transport error, nil if a response was received
+	Elapsed  time.Duration // Time since the first attempt started
+}
+
+// StatusCode returns the status code of the failed attempt, or zero if there was no response
+func (s *RetryState) StatusCode() int {
+	return s.Response.statusCode()
+}
+
+// RetryPolicyFunc adapts an ordinary function to a RetryPolicy
+type RetryPolicyFunc func(state *RetryState) (time.Duration, bool)
+
+// Next calls f(state)
+func (f RetryPolicyFunc) Next(state *RetryState) (time.Duration, bool) {
+	return f(state)
+}
+
+// RetryAfter returns the delay requested by the Retry-After header of resp,
+// which may be given in seconds or as an HTTP date
+func RetryAfter(resp *Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := strings.TrimSpace(resp.Headers.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// isCommand reports whether req invokes the Command API, whose POST requests are not idempotent
+func isCommand(req *Request) bool {
+	return req.Method == http.MethodPost && strings.HasPrefix(strings.TrimPrefix(req.Endpoint, "/"), "command/")
+}
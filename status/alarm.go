@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListAlarms retrieves a list of system alarms
@@ -20,6 +23,17 @@ func (s *Service) ListAlarms(ctx context.Context, opts *ListOptions) (*AlarmList
 	return &result, err
 }
 
+// AllAlarms returns an iterator over all system alarms, fetching further pages as needed
+func (s *Service) AllAlarms(ctx context.Context, opts *ListOptions) iter.Seq2[Alarm, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]Alarm, options.Meta, error) {
+		result, err := s.ListAlarms(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetAlarm retrieves a specific alarm by ID
 func (s *Service) GetAlarm(ctx context.Context, id int) (*Alarm, error) {
 	endpoint := fmt.Sprintf("status/v1/alarm/%d/", id)
@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListBackupRequests retrieves a list of backup request statuses
@@ -20,6 +23,17 @@ func (s *Service) ListBackupRequests(ctx context.Context, opts *ListOptions) (*B
 	return &result, err
 }
 
+// AllBackupRequests returns an iterator over all backup request statuses, fetching further pages as needed
+func (s *Service) AllBackupRequests(ctx context.Context, opts *ListOptions) iter.Seq2[BackupRequest, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]BackupRequest, options.Meta, error) {
+		result, err := s.ListBackupRequests(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetBackupRequest retrieves a specific backup request status by ID
 func (s *Service) GetBackupRequest(ctx context.Context, id int) (*BackupRequest, error) {
 	endpoint := fmt.Sprintf("status/v1/backup_request/%d/", id)
@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListCloudMonitoredLocations retrieves a list of cloud monitored location statuses
@@ -20,6 +23,17 @@ func (s *Service) ListCloudMonitoredLocations(ctx context.Context, opts *ListOpt
 	return &result, err
 }
 
+// AllCloudMonitoredLocations returns an iterator over all cloud monitored location statuses, fetching further pages as needed
+func (s *Service) AllCloudMonitoredLocations(ctx context.Context, opts *ListOptions) iter.Seq2[CloudMonitoredLocation, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]CloudMonitoredLocation, options.Meta, error) {
+		result, err := s.ListCloudMonitoredLocations(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetCloudMonitoredLocation retrieves a specific cloud monitored location status by ID
 func (s *Service) GetCloudMonitoredLocation(ctx context.Context, id int) (*CloudMonitoredLocation, error) {
 	endpoint := fmt.Sprintf("status/v1/cloud_monitored_location/%d/", id)
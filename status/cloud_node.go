@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListCloudNodes retrieves a list of cloud node statuses
@@ -20,6 +23,17 @@ func (s *Service) ListCloudNodes(ctx context.Context, opts *ListOptions) (*Cloud
 	return &result, err
 }
 
+// AllCloudNodes returns an iterator over all cloud node statuses, fetching further pages as needed
+func (s *Service) AllCloudNodes(ctx context.Context, opts *ListOptions) iter.Seq2[CloudNode, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]CloudNode, options.Meta, error) {
+		result, err := s.ListCloudNodes(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetCloudNode retrieves a specific cloud node status by ID
 func (s *Service) GetCloudNode(ctx context.Context, id string) (*CloudNode, error) {
 	endpoint := fmt.Sprintf("status/v1/cloud_node/%s/", id)
@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListCloudOverflowLocations retrieves a list of cloud overflow location statuses
@@ -20,6 +23,17 @@ func (s *Service) ListCloudOverflowLocations(ctx context.Context, opts *ListOpti
 	return &result, err
 }
 
+// AllCloudOverflowLocations returns an iterator over all cloud overflow location statuses, fetching further pages as needed
+func (s *Service) AllCloudOverflowLocations(ctx context.Context, opts *ListOptions) iter.Seq2[CloudOverflowLocation, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]CloudOverflowLocation, options.Meta, error) {
+		result, err := s.ListCloudOverflowLocations(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetCloudOverflowLocation retrieves a specific cloud overflow location status by ID
 func (s *Service) GetCloudOverflowLocation(ctx context.Context, id int) (*CloudOverflowLocation, error) {
 	endpoint := fmt.Sprintf("status/v1/cloud_overflow_location/%d/", id)
@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListConferences retrieves a list of conference statuses
@@ -20,6 +23,17 @@ func (s *Service) ListConferences(ctx context.Context, opts *ListOptions) (*Conf
 	return &result, err
 }
 
+// AllConferences returns an iterator over all conference statuses, fetching further pages as needed
+func (s *Service) AllConferences(ctx context.Context, opts *ListOptions) iter.Seq2[ConferenceStatus, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ConferenceStatus, options.Meta, error) {
+		result, err := s.ListConferences(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetConference retrieves a specific conference status by ID
 func (s *Service) GetConference(ctx context.Context, id string) (*ConferenceStatus, error) {
 	endpoint := fmt.Sprintf("status/v1/conference/%s/", id)
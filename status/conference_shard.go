@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListConferenceShards retrieves a list of conference shard statuses
@@ -20,6 +23,17 @@ func (s *Service) ListConferenceShards(ctx context.Context, opts *ListOptions) (
 	return &result, err
 }
 
+// AllConferenceShards returns an iterator over all conference shard statuses, fetching further pages as needed
+func (s *Service) AllConferenceShards(ctx context.Context, opts *ListOptions) iter.Seq2[ConferenceShard, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ConferenceShard, options.Meta, error) {
+		result, err := s.ListConferenceShards(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetConferenceShard retrieves a specific conference shard status by ID
 func (s *Service) GetConferenceShard(ctx context.Context, id string) (*ConferenceShard, error) {
 	endpoint := fmt.Sprintf("status/v1/conference_shard/%s/", id)
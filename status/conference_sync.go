@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListConferenceSyncs retrieves a list of conference sync statuses
@@ -20,6 +23,17 @@ func (s *Service) ListConferenceSyncs(ctx context.Context, opts *ListOptions) (*
 	return &result, err
 }
 
+// AllConferenceSyncs returns an iterator over all conference sync statuses, fetching further pages as needed
+func (s *Service) AllConferenceSyncs(ctx context.Context, opts *ListOptions) iter.Seq2[ConferenceSync, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ConferenceSync, options.Meta, error) {
+		result, err := s.ListConferenceSyncs(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetConferenceSync retrieves a specific conference sync status by ID
 func (s *Service) GetConferenceSync(ctx context.Context, id int) (*ConferenceSync, error) {
 	endpoint := fmt.Sprintf("status/v1/conference_sync/%d/", id)
@@ -7,11 +7,13 @@
 package status
 
 import (
+	"net/url"
 	"testing"
 
 	"github.com/pexip/go-infinity-sdk/v41/interfaces"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestService_ListConferences(t *testing.T) {
@@ -101,3 +103,31 @@ func TestService_GetConference(t *testing.T) {
 	assert.Equal(t, expectedConference, result)
 	client.AssertExpectations(t)
 }
+
+func TestService_AllConferences(t *testing.T) {
+	client := interfaces.NewHTTPClientMock()
+
+	firstPage := url.Values{}
+	secondPage := url.Values{"offset": []string{"1"}}
+
+	client.On("GetJSON", t.Context(), "status/v1/conference/", &firstPage, mock.AnythingOfType("*status.ConferenceListResponse")).Return(nil).Run(func(args mock.Arguments) {
+		result := args.Get(3).(*ConferenceListResponse)
+		result.Meta = Meta{Limit: 1, Next: "/api/admin/status/v1/conference/?offset=1", TotalCount: 2}
+		result.Objects = []ConferenceStatus{{ID: "1", Name: "Test Conference 1"}}
+	})
+	client.On("GetJSON", t.Context(), "status/v1/conference/", &secondPage, mock.AnythingOfType("*status.ConferenceListResponse")).Return(nil).Run(func(args mock.Arguments) {
+		result := args.Get(3).(*ConferenceListResponse)
+		result.Meta = Meta{Limit: 1, Offset: 1, TotalCount: 2}
+		result.Objects = []ConferenceStatus{{ID: "2", Name: "Test Conference 2"}}
+	})
+
+	service := New(client)
+	var names []string
+	for conference, err := range service.AllConferences(t.Context(), nil) {
+		require.NoError(t, err)
+		names = append(names, conference.Name)
+	}
+
+	assert.Equal(t, []string{"Test Conference 1", "Test Conference 2"}, names)
+	client.AssertExpectations(t)
+}
@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListExchangeSchedulers retrieves a list of Exchange scheduler statuses
@@ -20,6 +23,17 @@ func (s *Service) ListExchangeSchedulers(ctx context.Context, opts *ListOptions)
 	return &result, err
 }
 
+// AllExchangeSchedulers returns an iterator over all Exchange scheduler statuses, fetching further pages as needed
+func (s *Service) AllExchangeSchedulers(ctx context.Context, opts *ListOptions) iter.Seq2[ExchangeScheduler, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ExchangeScheduler, options.Meta, error) {
+		result, err := s.ListExchangeSchedulers(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetExchangeScheduler retrieves a specific Exchange scheduler status by ID
 func (s *Service) GetExchangeScheduler(ctx context.Context, id int) (*ExchangeScheduler, error) {
 	endpoint := fmt.Sprintf("status/v1/exchange_scheduler/%d/", id)
@@ -0,0 +1,62 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package status
+
+import "github.com/pexip/go-infinity-sdk/v41/options"
+
+// filterSchemas lists the filterable and orderable fields of status resources,
+// keyed by list endpoint. Filters on endpoints without a schema are sent unvalidated.
+var filterSchemas = map[string]*options.FilterSchema{
+	"status/v1/conference/": {
+		Fields: map[string][]options.Operator{
+			"name":         options.TextOperators,
+			"service_type": options.TextOperators,
+			"tag":          options.TextOperators,
+			"is_locked":    options.BoolOperators,
+			"is_started":   options.BoolOperators,
+			"start_time":   options.TimeOperators,
+		},
+		Ordering: []string{"name", "service_type", "start_time"},
+	},
+	"status/v1/participant/": {
+		Fields: map[string][]options.Operator{
+			"conference":        options.TextOperators,
+			"display_name":      options.TextOperators,
+			"role":              options.TextOperators,
+			"protocol":          options.TextOperators,
+			"call_direction":    options.TextOperators,
+			"system_location":   options.TextOperators,
+			"media_node":        options.TextOperators,
+			"signalling_node":   options.TextOperators,
+			"source_alias":      options.TextOperators,
+			"destination_alias": options.TextOperators,
+			"is_muted":          options.BoolOperators,
+			"is_presenting":     options.BoolOperators,
+			"connect_time":      options.TimeOperators,
+		},
+		Ordering: []string{"conference", "display_name", "connect_time"},
+	},
+	"status/v1/worker_vm/": {
+		Fields: map[string][]options.Operator{
+			"name":             options.TextOperators,
+			"node_type":        options.TextOperators,
+			"system_location":  options.TextOperators,
+			"maintenance_mode": options.BoolOperators,
+			"media_load":       options.NumericOperators,
+		},
+		Ordering: []string{"name", "system_location", "media_load"},
+	},
+	"status/v1/alarm/": {
+		Fields: map[string][]options.Operator{
+			"name":        options.TextOperators,
+			"level":       options.TextOperators,
+			"node":        options.TextOperators,
+			"time_raised": options.TimeOperators,
+		},
+		Ordering: []string{"name", "level", "node", "time_raised"},
+	},
+}
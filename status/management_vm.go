@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListManagementVMs retrieves a list of management VM statuses
@@ -20,6 +23,17 @@ func (s *Service) ListManagementVMs(ctx context.Context, opts *ListOptions) (*Ma
 	return &result, err
 }
 
+// AllManagementVMs returns an iterator over all management VM statuses, fetching further pages as needed
+func (s *Service) AllManagementVMs(ctx context.Context, opts *ListOptions) iter.Seq2[ManagementVM, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]ManagementVM, options.Meta, error) {
+		result, err := s.ListManagementVMs(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetManagementVM retrieves a specific management VM status by ID
 func (s *Service) GetManagementVM(ctx context.Context, id int) (*ManagementVM, error) {
 	endpoint := fmt.Sprintf("status/v1/management_vm/%d/", id)
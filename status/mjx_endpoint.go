@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListMJXEndpoints retrieves a list of MJX endpoint statuses
@@ -20,6 +23,17 @@ func (s *Service) ListMJXEndpoints(ctx context.Context, opts *ListOptions) (*MJX
 	return &result, err
 }
 
+// AllMJXEndpoints returns an iterator over all MJX endpoint statuses, fetching further pages as needed
+func (s *Service) AllMJXEndpoints(ctx context.Context, opts *ListOptions) iter.Seq2[MJXEndpoint, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MJXEndpoint, options.Meta, error) {
+		result, err := s.ListMJXEndpoints(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMJXEndpoint retrieves a specific MJX endpoint status by ID
 func (s *Service) GetMJXEndpoint(ctx context.Context, id int) (*MJXEndpoint, error) {
 	endpoint := fmt.Sprintf("status/v1/mjx_endpoint/%d/", id)
@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListMJXMeetings retrieves a list of MJX meeting statuses
@@ -20,6 +23,17 @@ func (s *Service) ListMJXMeetings(ctx context.Context, opts *ListOptions) (*MJXM
 	return &result, err
 }
 
+// AllMJXMeetings returns an iterator over all MJX meeting statuses, fetching further pages as needed
+func (s *Service) AllMJXMeetings(ctx context.Context, opts *ListOptions) iter.Seq2[MJXMeeting, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]MJXMeeting, options.Meta, error) {
+		result, err := s.ListMJXMeetings(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetMJXMeeting retrieves a specific MJX meeting status by ID
 func (s *Service) GetMJXMeeting(ctx context.Context, id string) (*MJXMeeting, error) {
 	endpoint := fmt.Sprintf("status/v1/mjx_meeting/%s/", id)
@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListParticipants retrieves a list of participants
@@ -20,6 +23,17 @@ func (s *Service) ListParticipants(ctx context.Context, opts *ListOptions) (*Par
 	return &result, err
 }
 
+// AllParticipants returns an iterator over all participants, fetching further pages as needed
+func (s *Service) AllParticipants(ctx context.Context, opts *ListOptions) iter.Seq2[Participant, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]Participant, options.Meta, error) {
+		result, err := s.ListParticipants(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetParticipant retrieves a specific participant by UUID
 func (s *Service) GetParticipant(ctx context.Context, uuid string) (*Participant, error) {
 	endpoint := fmt.Sprintf("status/v1/participant/%s/", uuid)
@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListSchedulingOperations retrieves a list of scheduling operation statuses
@@ -20,6 +23,17 @@ func (s *Service) ListSchedulingOperations(ctx context.Context, opts *ListOption
 	return &result, err
 }
 
+// AllSchedulingOperations returns an iterator over all scheduling operation statuses, fetching further pages as needed
+func (s *Service) AllSchedulingOperations(ctx context.Context, opts *ListOptions) iter.Seq2[SchedulingOperation, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]SchedulingOperation, options.Meta, error) {
+		result, err := s.ListSchedulingOperations(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSchedulingOperation retrieves a specific scheduling operation status by ID
 func (s *Service) GetSchedulingOperation(ctx context.Context, id int) (*SchedulingOperation, error) {
 	endpoint := fmt.Sprintf("status/v1/scheduling_operation/%d/", id)
@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListSnapshotRequests retrieves a list of snapshot request statuses
@@ -20,6 +23,17 @@ func (s *Service) ListSnapshotRequests(ctx context.Context, opts *ListOptions) (
 	return &result, err
 }
 
+// AllSnapshotRequests returns an iterator over all snapshot request statuses, fetching further pages as needed
+func (s *Service) AllSnapshotRequests(ctx context.Context, opts *ListOptions) iter.Seq2[SnapshotRequest, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]SnapshotRequest, options.Meta, error) {
+		result, err := s.ListSnapshotRequests(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSnapshotRequest retrieves a specific snapshot request status by ID
 func (s *Service) GetSnapshotRequest(ctx context.Context, id int) (*SnapshotRequest, error) {
 	endpoint := fmt.Sprintf("status/v1/snapshot_request/%d/", id)
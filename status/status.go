@@ -35,6 +35,9 @@ type ListOptions = options.BaseListOptions
 func (s *Service) listEndpoint(ctx context.Context, endpoint string, opts *ListOptions, result interface{}) error {
 	var params url.Values
 	if opts != nil {
+		if err := opts.Filter.Validate(filterSchemas[endpoint]); err != nil {
+			return err
+		}
 		params = opts.ToURLValues()
 	}
 	return s.client.GetJSON(ctx, endpoint, &params, result)
@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListSystemLocations retrieves a list of system location statuses
@@ -20,6 +23,17 @@ func (s *Service) ListSystemLocations(ctx context.Context, opts *ListOptions) (*
 	return &result, err
 }
 
+// AllSystemLocations returns an iterator over all system location statuses, fetching further pages as needed
+func (s *Service) AllSystemLocations(ctx context.Context, opts *ListOptions) iter.Seq2[SystemLocation, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]SystemLocation, options.Meta, error) {
+		result, err := s.ListSystemLocations(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetSystemLocation retrieves a specific system location status by ID
 func (s *Service) GetSystemLocation(ctx context.Context, id int) (*SystemLocation, error) {
 	endpoint := fmt.Sprintf("status/v1/system_location/%d/", id)
@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListTeamsNodes retrieves a list of Teams node statuses
@@ -20,6 +23,17 @@ func (s *Service) ListTeamsNodes(ctx context.Context, opts *ListOptions) (*Teams
 	return &result, err
 }
 
+// AllTeamsNodes returns an iterator over all Teams node statuses, fetching further pages as needed
+func (s *Service) AllTeamsNodes(ctx context.Context, opts *ListOptions) iter.Seq2[TeamsNode, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]TeamsNode, options.Meta, error) {
+		result, err := s.ListTeamsNodes(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetTeamsNode retrieves a specific Teams node status by ID
 func (s *Service) GetTeamsNode(ctx context.Context, id int) (*TeamsNode, error) {
 	endpoint := fmt.Sprintf("status/v1/teamsnode/%d/", id)
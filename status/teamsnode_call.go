@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListTeamsNodeCalls retrieves a list of Teams node call statuses
@@ -20,6 +23,17 @@ func (s *Service) ListTeamsNodeCalls(ctx context.Context, opts *ListOptions) (*T
 	return &result, err
 }
 
+// AllTeamsNodeCalls returns an iterator over all Teams node call statuses, fetching further pages as needed
+func (s *Service) AllTeamsNodeCalls(ctx context.Context, opts *ListOptions) iter.Seq2[TeamsNodeCall, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]TeamsNodeCall, options.Meta, error) {
+		result, err := s.ListTeamsNodeCalls(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetTeamsNodeCall retrieves a specific Teams node call status by ID
 func (s *Service) GetTeamsNodeCall(ctx context.Context, id string) (*TeamsNodeCall, error) {
 	endpoint := fmt.Sprintf("status/v1/teamsnode_call/%s/", id)
@@ -0,0 +1,272 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package status
+
+import (
+	"context"
+	"maps"
+	"math/rand"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Watcher defaults
+const (
+	DefaultWatchInterval   = 5 * time.Second
+	DefaultWatchJitter     = 0.1
+	DefaultWatchMaxBackoff = time.Minute
+	DefaultWatchBufferSize = 256
+)
+
+// EventType identifies a change observed by a Watcher
+type EventType string
+
+const (
+	ConferenceStarted              EventType = "conference_started"
+	ConferenceEnded                EventType = "conference_ended"
+	ConferenceLocked               EventType = "conference_locked"
+	ConferenceUnlocked             EventType = "conference_unlocked"
+	ParticipantJoined              EventType = "participant_joined"
+	ParticipantLeft                EventType = "participant_left"
+	ParticipantMuted               EventType = "participant_muted"
+	ParticipantUnmuted             EventType = "participant_unmuted"
+	ParticipantRoleChanged         EventType = "participant_role_changed"
+	ParticipantPresentationStarted EventType = "participant_presentation_started"
+	ParticipantPresentationStopped EventType = "participant_presentation_stopped"
+	// WatchError reports a failed poll. The watcher keeps its last snapshot and tries again with backoff.
+	WatchError EventType = "watch_error"
+)
+
+// Event is a change between two snapshots of the live conferences and participants
+type Event struct {
+	Type EventType
+	Time time.Time // when the change was observed
+	// Conference is set for conference events, with its state before it ended for ConferenceEnded
+	Conference *ConferenceStatus
+	// Participant is set for participant events, with its state before it left for ParticipantLeft
+	Participant *Participant
+	// Previous is the participant's previous state for ParticipantRoleChanged
+	Previous *Participant
+	Err      error // set for WatchError
+}
+
+// Backpressure controls what a Watcher does when its event buffer is full
+type Backpressure int
+
+const (
+	// BackpressureBlock waits for the consumer, delaying the next poll. No events are lost.
+	BackpressureBlock Backpressure = iota
+	// BackpressureDrop drops events that do not fit in the buffer and counts them in Dropped
+	BackpressureDrop
+)
+
+// WatcherOptions configures a Watcher. Zero values select the defaults.
+type WatcherOptions struct {
+	Interval time.Duration // time between polls
+	// Jitter is the random fraction of the interval added to or removed from each wait
+	Jitter float64
+	// DisableJitter waits exactly Interval between polls, ignoring Jitter
+	DisableJitter bool
+	MaxBackoff    time.Duration // longest wait between polls after repeated errors
+	BufferSize    int           // capacity of the event channel
+	Backpressure  Backpressure
+	// EmitInitial reports the conferences and participants found by the first poll as started and joined
+	EmitInitial bool
+}
+
+// Watcher polls the live conferences and participants, diffs consecutive snapshots by ID and emits an event
+// for each change
+type Watcher struct {
+	service *Service
+	opts    WatcherOptions
+	events  chan Event
+	dropped atomic.Int64
+	now     func() time.Time
+}
+
+// NewWatcher creates a watcher of the live conferences and participants. Nil opts selects all the
+// defaults. Call Run to start polling.
+func (s *Service) NewWatcher(opts *WatcherOptions) *Watcher {
+	w := &Watcher{service: s, now: time.Now}
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.Interval <= 0 {
+		w.opts.Interval = DefaultWatchInterval
+	}
+	if w.opts.DisableJitter {
+		w.opts.Jitter = 0
+	} else if w.opts.Jitter <= 0 {
+		w.opts.Jitter = DefaultWatchJitter
+	}
+	if w.opts.MaxBackoff <= 0 {
+		w.opts.MaxBackoff = DefaultWatchMaxBackoff
+	}
+	if w.opts.BufferSize <= 0 {
+		w.opts.BufferSize = DefaultWatchBufferSize
+	}
+	w.events = make(chan Event, w.opts.BufferSize)
+	return w
+}
+
+// Events returns the channel events are delivered on. It is closed when Run returns.
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Dropped returns the number of events dropped with BackpressureDrop
+func (w *Watcher) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Run polls until ctx is cancelled and then returns its error. It must be called only once.
+func (w *Watcher) Run(ctx context.Context) error {
+	defer close(w.events)
+
+	var last *snapshot
+	failures := 0
+	for {
+		next, err := w.poll(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil:
+			failures++
+			w.emit(ctx, Event{Type: WatchError, Time: w.now(), Err: err})
+		default:
+			failures = 0
+			if last != nil || w.opts.EmitInitial {
+				for _, event := range diff(last, next, w.now()) {
+					w.emit(ctx, event)
+				}
+			}
+			last = next
+		}
+
+		timer := time.NewTimer(w.delay(failures))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (w *Watcher) emit(ctx context.Context, event Event) {
+	if w.opts.Backpressure == BackpressureDrop {
+		select {
+		case w.events <- event:
+		default:
+			w.dropped.Add(1)
+		}
+		return
+	}
+	select {
+	case w.events <- event:
+	case <-ctx.Done():
+	}
+}
+
+// delay returns the jittered wait before the next poll, backing off exponentially after failures
+func (w *Watcher) delay(failures int) time.Duration {
+	d := w.opts.Interval
+	for i := 0; i < failures && d < w.opts.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, w.opts.MaxBackoff)
+	jitter := (rand.Float64()*2 - 1) * w.opts.Jitter * float64(d)
+	return max(d+time.Duration(jitter), 0)
+}
+
+// snapshot is the state of the live conferences and participants at one poll
+type snapshot struct {
+	conferences  map[string]ConferenceStatus
+	participants map[string]Participant
+}
+
+func (w *Watcher) poll(ctx context.Context) (*snapshot, error) {
+	s := &snapshot{
+		conferences:  make(map[string]ConferenceStatus),
+		participants: make(map[string]Participant),
+	}
+	for conference, err := range w.service.AllConferences(ctx, nil) {
+		if err != nil {
+			return nil, err
+		}
+		s.conferences[conference.ID] = conference
+	}
+	for participant, err := range w.service.AllParticipants(ctx, nil) {
+		if err != nil {
+			return nil, err
+		}
+		s.participants[participant.ID] = participant
+	}
+	return s, nil
+}
+
+// diff returns the events that turn prev into next: conferences starting, participants joining, changes,
+// participants leaving and conferences ending, each in ID order. A nil prev is an empty snapshot.
+func diff(prev, next *snapshot, now time.Time) []Event {
+	if prev == nil {
+		prev = &snapshot{}
+	}
+	var started, joined, changed, left, ended []Event
+
+	for _, id := range slices.Sorted(maps.Keys(next.conferences)) {
+		conference := next.conferences[id]
+		old, ok := prev.conferences[id]
+		switch {
+		case !ok:
+			started = append(started, Event{Type: ConferenceStarted, Time: now, Conference: &conference})
+		case !old.IsLocked && conference.IsLocked:
+			changed = append(changed, Event{Type: ConferenceLocked, Time: now, Conference: &conference})
+		case old.IsLocked && !conference.IsLocked:
+			changed = append(changed, Event{Type: ConferenceUnlocked, Time: now, Conference: &conference})
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(prev.conferences)) {
+		if _, ok := next.conferences[id]; !ok {
+			conference := prev.conferences[id]
+			ended = append(ended, Event{Type: ConferenceEnded, Time: now, Conference: &conference})
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(next.participants)) {
+		participant := next.participants[id]
+		old, ok := prev.participants[id]
+		if !ok {
+			joined = append(joined, Event{Type: ParticipantJoined, Time: now, Participant: &participant})
+			continue
+		}
+		if old.IsMuted != participant.IsMuted {
+			changed = append(changed, Event{Type: choose(participant.IsMuted, ParticipantMuted, ParticipantUnmuted), Time: now, Participant: &participant})
+		}
+		if old.Role != participant.Role {
+			changed = append(changed, Event{Type: ParticipantRoleChanged, Time: now, Participant: &participant, Previous: &old})
+		}
+		if old.IsPresenting != participant.IsPresenting {
+			changed = append(changed, Event{Type: choose(participant.IsPresenting, ParticipantPresentationStarted, ParticipantPresentationStopped), Time: now, Participant: &participant})
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(prev.participants)) {
+		if _, ok := next.participants[id]; !ok {
+			participant := prev.participants[id]
+			left = append(left, Event{Type: ParticipantLeft, Time: now, Participant: &participant})
+		}
+	}
+
+	return slices.Concat(started, joined, changed, left, ended)
+}
+
+func choose(cond bool, a, b EventType) EventType {
+	if cond {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,164 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package status
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pexip/go-infinity-sdk/v41/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	now := time.Now()
+	prev := &snapshot{
+		conferences: map[string]ConferenceStatus{
+			"c1": {ID: "c1", Name: "Sales"},
+			"c2": {ID: "c2", Name: "Support", IsLocked: true},
+		},
+		participants: map[string]Participant{
+			"p1": {ID: "p1", Conference: "Sales", Role: "guest"},
+			"p2": {ID: "p2", Conference: "Sales", IsMuted: true},
+			"p3": {ID: "p3", Conference: "Support"},
+		},
+	}
+	next := &snapshot{
+		conferences: map[string]ConferenceStatus{
+			"c1": {ID: "c1", Name: "Sales", IsLocked: true},
+			"c3": {ID: "c3", Name: "Board"},
+		},
+		participants: map[string]Participant{
+			"p1": {ID: "p1", Conference: "Sales", Role: "chair", IsPresenting: true},
+			"p2": {ID: "p2", Conference: "Sales"},
+			"p4": {ID: "p4", Conference: "Board"},
+		},
+	}
+
+	var got []string
+	for _, event := range diff(prev, next, now) {
+		assert.Equal(t, now, event.Time)
+		id := ""
+		if event.Participant != nil {
+			id = event.Participant.ID
+		} else {
+			id = event.Conference.ID
+		}
+		got = append(got, string(event.Type)+" "+id)
+	}
+	assert.Equal(t, []string{
+		"conference_started c3",
+		"participant_joined p4",
+		"conference_locked c1",
+		"participant_role_changed p1",
+		"participant_presentation_started p1",
+		"participant_unmuted p2",
+		"participant_left p3",
+		"conference_ended c2",
+	}, got)
+
+	events := diff(prev, next, now)
+	assert.Equal(t, "guest", events[3].Previous.Role)
+	assert.Equal(t, "Support", events[7].Conference.Name)
+}
+
+// fakeStatus serves the snapshots in turn, repeating the last one, and fails the poll numbered failOn
+type fakeStatus struct {
+	*interfaces.HTTPClientMock
+	snapshots []*snapshot
+	current   *snapshot
+	polls     int
+	failOn    int
+}
+
+func (f *fakeStatus) GetJSON(_ context.Context, endpoint string, _ *url.Values, result interface{}) error {
+	switch endpoint {
+	case "status/v1/conference/":
+		f.polls++
+		f.current = f.snapshots[0]
+		if len(f.snapshots) > 1 {
+			f.snapshots = f.snapshots[1:]
+		}
+		for _, conference := range f.current.conferences {
+			result.(*ConferenceListResponse).Objects = append(result.(*ConferenceListResponse).Objects, conference)
+		}
+	case "status/v1/participant/":
+		if f.polls == f.failOn {
+			return errors.New("connection reset")
+		}
+		for _, participant := range f.current.participants {
+			result.(*ParticipantListResponse).Objects = append(result.(*ParticipantListResponse).Objects, participant)
+		}
+	}
+	return nil
+}
+
+func TestWatcher_Run(t *testing.T) {
+	empty := &snapshot{}
+	started := &snapshot{
+		conferences:  map[string]ConferenceStatus{"c1": {ID: "c1", Name: "Sales"}},
+		participants: map[string]Participant{"p1": {ID: "p1", Conference: "Sales"}},
+	}
+	fake := &fakeStatus{snapshots: []*snapshot{empty, started, empty, empty}, failOn: 3}
+
+	watcher := New(fake).NewWatcher(&WatcherOptions{Interval: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- watcher.Run(ctx) }()
+
+	var got []EventType
+	for event := range watcher.Events() {
+		got = append(got, event.Type)
+		if event.Type == ConferenceEnded {
+			cancel()
+		}
+	}
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	// The failed poll keeps the last snapshot, so the conference is only reported as ended once a poll succeeds
+	assert.Equal(t, []EventType{
+		ConferenceStarted, ParticipantJoined,
+		WatchError,
+		ParticipantLeft, ConferenceEnded,
+	}, got)
+}
+
+func TestWatcher_BackpressureDrop(t *testing.T) {
+	watcher := New(interfaces.NewHTTPClientMock()).NewWatcher(&WatcherOptions{BufferSize: 1, Backpressure: BackpressureDrop})
+	watcher.emit(t.Context(), Event{Type: ConferenceStarted})
+	watcher.emit(t.Context(), Event{Type: ConferenceEnded})
+
+	assert.Equal(t, int64(1), watcher.Dropped())
+	assert.Equal(t, ConferenceStarted, (<-watcher.Events()).Type)
+}
+
+func TestWatcher_Delay(t *testing.T) {
+	watcher := New(interfaces.NewHTTPClientMock()).NewWatcher(&WatcherOptions{Interval: time.Second, Jitter: 0.1, MaxBackoff: 5 * time.Second})
+	for range 100 {
+		d := watcher.delay(0)
+		assert.GreaterOrEqual(t, d, 900*time.Millisecond)
+		assert.LessOrEqual(t, d, 1100*time.Millisecond)
+	}
+	assert.LessOrEqual(t, watcher.delay(10), 5500*time.Millisecond)
+	assert.GreaterOrEqual(t, watcher.delay(10), 4500*time.Millisecond)
+}
+
+func TestWatcher_Jitter(t *testing.T) {
+	service := New(interfaces.NewHTTPClientMock())
+	assert.Equal(t, DefaultWatchJitter, service.NewWatcher(nil).opts.Jitter)
+	assert.Equal(t, DefaultWatchJitter, service.NewWatcher(&WatcherOptions{Interval: time.Second}).opts.Jitter)
+
+	watcher := service.NewWatcher(&WatcherOptions{Interval: time.Second, DisableJitter: true})
+	for range 10 {
+		assert.Equal(t, time.Second, watcher.delay(0))
+	}
+}
@@ -9,6 +9,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"iter"
+
+	"github.com/pexip/go-infinity-sdk/v41/options"
 )
 
 // ListWorkerVMs retrieves a list of worker VM statuses
@@ -20,6 +23,17 @@ func (s *Service) ListWorkerVMs(ctx context.Context, opts *ListOptions) (*Worker
 	return &result, err
 }
 
+// AllWorkerVMs returns an iterator over all worker VM statuses, fetching further pages as needed
+func (s *Service) AllWorkerVMs(ctx context.Context, opts *ListOptions) iter.Seq2[WorkerVM, error] {
+	return options.PaginateList(ctx, opts, func(ctx context.Context, opts *ListOptions) ([]WorkerVM, options.Meta, error) {
+		result, err := s.ListWorkerVMs(ctx, opts)
+		if err != nil {
+			return nil, options.Meta{}, err
+		}
+		return result.Objects, options.Meta(result.Meta), nil
+	})
+}
+
 // GetWorkerVM retrieves a specific worker VM status by ID
 func (s *Service) GetWorkerVM(ctx context.Context, id int) (*WorkerVM, error) {
 	endpoint := fmt.Sprintf("status/v1/worker_vm/%d/", id)
@@ -0,0 +1,238 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package infinity
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+const instrumentationName = "github.com/pexip/go-infinity-sdk/v41"
+
+// Attribute keys recorded on spans and metrics in addition to the HTTP semantic conventions
+const (
+	attrOperation  = attribute.Key("infinity.operation")
+	attrAPI        = attribute.Key("infinity.api")
+	attrEndpoint   = attribute.Key("infinity.endpoint")
+	attrRetryCount = attribute.Key("infinity.retry_count")
+	attrResourceID = attribute.Key("infinity.resource_id")
+)
+
+// telemetry records OpenTelemetry spans and metrics for SDK calls. A nil telemetry records nothing.
+type telemetry struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+	retries  metric.Int64Counter
+}
+
+func newTelemetry(tp trace.TracerProvider, mp metric.MeterProvider) (*telemetry, error) {
+	if tp == nil {
+		tp = tracenoop.NewTracerProvider()
+	}
+	if mp == nil {
+		mp = metricnoop.NewMeterProvider()
+	}
+	meter := mp.Meter(instrumentationName)
+
+	t := &telemetry{tracer: tp.Tracer(instrumentationName)}
+	var err error
+	if t.duration, err = meter.Float64Histogram("infinity.client.call.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of SDK calls, including retries")); err != nil {
+		return nil, fmt.Errorf("failed to create duration histogram: %w", err)
+	}
+	if t.errors, err = meter.Int64Counter("infinity.client.call.errors",
+		metric.WithUnit("{error}"),
+		metric.WithDescription("Number of SDK calls that failed")); err != nil {
+		return nil, fmt.Errorf("failed to create error counter: %w", err)
+	}
+	if t.retries, err = meter.Int64Counter("infinity.client.call.retries",
+		metric.WithUnit("{retry}"),
+		metric.WithDescription("Number of retried HTTP attempts")); err != nil {
+		return nil, fmt.Errorf("failed to create retry counter: %w", err)
+	}
+	return t, nil
+}
+
+type callStateKey struct{}
+
+// callState collects what happens inside a call for its span and metrics
+type callState struct {
+	attempts int
+}
+
+// intercept is the outermost interceptor. It records a span and metrics for each SDK call.
+func (t *telemetry) intercept(next Handler) Handler {
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		op := operationName(req)
+		attrs := []attribute.KeyValue{
+			attrOperation.String(op),
+			attrAPI.String(string(categoryOf(req.Endpoint))),
+			attribute.String("http.request.method", req.Method),
+		}
+
+		ctx, span := t.tracer.Start(ctx, op, trace.WithAttributes(attrs...), trace.WithAttributes(attrEndpoint.String(req.Endpoint)))
+		defer span.End()
+		state := &callState{}
+		ctx = context.WithValue(ctx, callStateKey{}, state)
+
+		start := time.Now()
+		resp, err := next(ctx, req)
+
+		if resp != nil {
+			attrs = append(attrs, attribute.Int("http.response.status_code", resp.StatusCode))
+		}
+		if err != nil {
+			attrs = append(attrs, attribute.String("error.type", errorType(resp, err)))
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.SetAttributes(attrs...)
+		span.SetAttributes(attrRetryCount.Int(max(state.attempts-1, 0)))
+		if id := resourceID(req, resp); id != "" {
+			span.SetAttributes(attrResourceID.String(id))
+		}
+
+		set := metric.WithAttributes(attrs...)
+		t.duration.Record(ctx, time.Since(start).Seconds(), set)
+		if err != nil {
+			t.errors.Add(ctx, 1, set)
+		}
+		if state.attempts > 1 {
+			t.retries.Add(ctx, int64(state.attempts-1), set)
+		}
+		return resp, err
+	}
+}
+
+// startAttempt starts the span of one HTTP attempt and injects its trace context into the request headers.
+// The returned function ends the span.
+func (t *telemetry) startAttempt(httpReq *http.Request, attempt int) (*http.Request, func(*Response, error)) {
+	if t == nil {
+		return httpReq, func(*Response, error) {}
+	}
+	ctx := httpReq.Context()
+	if state, ok := ctx.Value(callStateKey{}).(*callState); ok {
+		state.attempts = attempt
+	}
+
+	ctx, span := t.tracer.Start(ctx, httpReq.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", httpReq.Method),
+			attribute.String("url.full", httpReq.URL.String()),
+			attribute.String("server.address", httpReq.URL.Hostname()),
+			attribute.Int("http.request.resend_count", attempt-1),
+		))
+	httpReq = httpReq.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(httpReq.Header))
+
+	return httpReq, func(resp *Response, err error) {
+		if resp != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		}
+		if err != nil || resp.statusCode() >= 400 {
+			span.SetAttributes(attribute.String("error.type", errorType(resp, err)))
+			span.SetStatus(codes.Error, errorType(resp, err))
+		}
+		span.End()
+	}
+}
+
+// operationName names a call after the service method that typically makes it, for example
+// "config.CreateConference" for POST configuration/v1/conference/ or "command.ParticipantDisconnect"
+// for POST command/v1/participant/disconnect/
+func operationName(req *Request) string {
+	parts := strings.Split(strings.Trim(req.Endpoint, "/"), "/")
+	api := parts[0]
+	if api == string(CategoryConfiguration) {
+		api = "config"
+	}
+	if len(parts) < 3 {
+		return api + "." + req.Method
+	}
+	if api == string(CategoryCommand) {
+		return api + "." + pascalCase(strings.Join(parts[2:], "_"))
+	}
+
+	resource := pascalCase(parts[2])
+	hasID := len(parts) > 3
+	switch {
+	case req.Method == http.MethodGet && !hasID:
+		return api + ".List" + plural(resource)
+	case req.Method == http.MethodGet:
+		return api + ".Get" + resource
+	case req.Method == http.MethodPost:
+		return api + ".Create" + resource
+	case req.Method == http.MethodPut, req.Method == http.MethodPatch:
+		return api + ".Update" + resource
+	case req.Method == http.MethodDelete:
+		return api + ".Delete" + resource
+	default:
+		return api + "." + req.Method + resource
+	}
+}
+
+func pascalCase(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == '-' }) {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+func plural(s string) string {
+	switch {
+	case len(s) == 0:
+		return s
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsAny(s[len(s)-2:len(s)-1], "aeiou"):
+		return s[:len(s)-1] + "ies"
+	default:
+		return s + "s"
+	}
+}
+
+// resourceID returns the ID of the resource a call created or addressed
+func resourceID(req *Request, resp *Response) string {
+	if resp != nil {
+		if location := resp.Headers.Get("Location"); location != "" {
+			return path.Base(strings.TrimSuffix(location, "/"))
+		}
+	}
+	parts := strings.Split(strings.Trim(req.Endpoint, "/"), "/")
+	if len(parts) > 3 && parts[0] != string(CategoryCommand) {
+		return parts[3]
+	}
+	return ""
+}
+
+// errorType is the low-cardinality error.type attribute of a failed call
+func errorType(resp *Response, err error) string {
+	if resp != nil && resp.StatusCode >= 400 {
+		return fmt.Sprintf("%d", resp.StatusCode)
+	}
+	if err != nil {
+		return fmt.Sprintf("%T", err)
+	}
+	return "unknown"
+}
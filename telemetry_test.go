@@ -0,0 +1,146 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Pexip AS
+ *
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package infinity
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestOperationName(t *testing.T) {
+	tests := []struct {
+		method   string
+		endpoint string
+		want     string
+	}{
+		{http.MethodGet, "configuration/v1/conference/", "config.ListConferences"},
+		{http.MethodGet, "configuration/v1/conference_alias/", "config.ListConferenceAliases"},
+		{http.MethodGet, "configuration/v1/system_location/", "config.ListSystemLocations"},
+		{http.MethodGet, "configuration/v1/policy_server/", "config.ListPolicyServers"},
+		{http.MethodGet, "configuration/v1/identity_provider/", "config.ListIdentityProviders"},
+		{http.MethodGet, "configuration/v1/conference/1/", "config.GetConference"},
+		{http.MethodPost, "configuration/v1/conference/", "config.CreateConference"},
+		{http.MethodPut, "configuration/v1/conference/1/", "config.UpdateConference"},
+		{http.MethodDelete, "configuration/v1/conference/1/", "config.DeleteConference"},
+		{http.MethodGet, "status/v1/participant/", "status.ListParticipants"},
+		{http.MethodPost, "command/v1/participant/disconnect/", "command.ParticipantDisconnect"},
+		{http.MethodGet, "status/v1", "status.GET"},
+		{http.MethodGet, "status/v1/y/", "status.ListYs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, operationName(&Request{Method: tt.method, Endpoint: tt.endpoint}))
+		})
+	}
+}
+
+func TestClient_WithTracerProvider(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator()) })
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, r.Header.Get("traceparent"))
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Location", "/api/admin/configuration/v1/conference/42/")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	client, err := New(
+		WithBaseURL(server.URL),
+		WithRetryConfig(&RetryConfig{MaxRetries: 1, BackoffMin: time.Millisecond, BackoffMax: time.Millisecond, Multiplier: 1}),
+		WithTracerProvider(tp),
+		WithMeterProvider(mp),
+	)
+	require.NoError(t, err)
+
+	_, err = client.PostWithResponse(t.Context(), "configuration/v1/conference/", map[string]string{"name": "sales"}, nil)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	first, second, call := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "config.CreateConference", call.Name())
+	assert.Contains(t, call.Attributes(), attrRetryCount.Int(1))
+	assert.Contains(t, call.Attributes(), attrResourceID.String("42"))
+	assert.Contains(t, call.Attributes(), attrEndpoint.String("configuration/v1/conference/"))
+	assert.Contains(t, call.Attributes(), attribute.Int("http.response.status_code", http.StatusCreated))
+
+	for _, attempt := range []sdktrace.ReadOnlySpan{first, second} {
+		assert.Equal(t, "POST", attempt.Name())
+		assert.Equal(t, call.SpanContext().SpanID(), attempt.Parent().SpanID())
+	}
+	assert.Equal(t, codes.Error, first.Status().Code)
+	assert.Contains(t, second.Attributes(), attribute.Int("http.request.resend_count", 1))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &rm))
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	require.Contains(t, metrics, "infinity.client.call.duration")
+	assert.Equal(t, uint64(1), metrics["infinity.client.call.duration"].(metricdata.Histogram[float64]).DataPoints[0].Count)
+	assert.Equal(t, int64(1), metrics["infinity.client.call.retries"].(metricdata.Sum[int64]).DataPoints[0].Value)
+	assert.NotContains(t, metrics, "infinity.client.call.errors")
+}
+
+func TestClient_WithMeterProvider_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	reader := sdkmetric.NewManualReader()
+	client, err := New(WithBaseURL(server.URL), WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	require.NoError(t, err)
+
+	err = client.GetJSON(t.Context(), "configuration/v1/conference/7/", nil, nil)
+	require.True(t, IsNotFound(err))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &rm))
+	var errors metricdata.Sum[int64]
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name == "infinity.client.call.errors" {
+			errors = m.Data.(metricdata.Sum[int64])
+		}
+	}
+	require.Len(t, errors.DataPoints, 1)
+	assert.Equal(t, int64(1), errors.DataPoints[0].Value)
+	errorType, _ := errors.DataPoints[0].Attributes.Value("error.type")
+	assert.Equal(t, "404", errorType.AsString())
+	operation, _ := errors.DataPoints[0].Attributes.Value(attrOperation)
+	assert.Equal(t, "config.GetConference", operation.AsString())
+}